@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"culinary-book/backend/auth"
+	"culinary-book/backend/models"
+)
+
+const (
+	accountDeletionGracePeriod = 30 * 24 * time.Hour
+	accountPurgeInterval       = time.Hour
+	maxDisplayNameLength       = 100
+	maxBioLength               = 1000
+	maxAvatarSize              = 1 << 20
+)
+
+var (
+	errImageEncoding = errors.New("изображение не в base64")
+	errImageTooLarge = errors.New("изображение слишком большое")
+	errImageFormat   = errors.New("изображение не в формате JPEG или PNG")
+)
+
+// decodeImage декодирует картинку в base64 (аватар, обложку) и проверяет, что она
+// не больше maxSize и записана в JPEG или PNG.
+func decodeImage(encoded string, maxSize int) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errImageEncoding
+	}
+	if len(data) > maxSize {
+		return nil, errImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, errImageFormat
+	}
+	return data, nil
+}
+
+// withDeletionSchedule дополняет пользователя датой окончательного удаления аккаунта.
+func withDeletionSchedule(user *models.User) *models.User {
+	if user.DeletionRequestedAt != nil {
+		scheduled := user.DeletionRequestedAt.Add(accountDeletionGracePeriod)
+		user.DeletionScheduledAt = &scheduled
+	}
+	return user
+}
+
+func meHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+	case "PATCH":
+		if !updateProfile(w, r, userID) {
+			return
+		}
+	default:
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, err := userRepo.GetUserByID(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Пользователь не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"user":   withDeletionSchedule(user),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func updateProfile(w http.ResponseWriter, r *http.Request, userID int) bool {
+	var update models.ProfileUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return false
+	}
+
+	if update.DisplayName != nil {
+		name := strings.TrimSpace(*update.DisplayName)
+		if len([]rune(name)) > maxDisplayNameLength {
+			http.Error(w, `{"error": "Отображаемое имя не длиннее 100 символов"}`, http.StatusBadRequest)
+			return false
+		}
+		update.DisplayName = &name
+	}
+
+	if update.Bio != nil {
+		bio := strings.TrimSpace(*update.Bio)
+		if len([]rune(bio)) > maxBioLength {
+			http.Error(w, `{"error": "Описание не длиннее 1000 символов"}`, http.StatusBadRequest)
+			return false
+		}
+		update.Bio = &bio
+	}
+
+	if update.Email != nil {
+		email := strings.TrimSpace(*update.Email)
+		if email != "" {
+			parsed, err := mail.ParseAddress(email)
+			if err != nil || parsed.Address != email {
+				http.Error(w, `{"error": "Неверный адрес электронной почты"}`, http.StatusBadRequest)
+				return false
+			}
+
+			taken, err := userRepo.EmailTaken(email, userID)
+			if err != nil {
+				http.Error(w, `{"error": "Ошибка сервера"}`, http.StatusInternalServerError)
+				return false
+			}
+			if taken {
+				http.Error(w, `{"error": "Этот адрес уже используется"}`, http.StatusConflict)
+				return false
+			}
+		}
+		update.Email = &email
+	}
+
+	if update.AvatarBase64 != nil && *update.AvatarBase64 != "" {
+		switch _, err := decodeImage(*update.AvatarBase64, maxAvatarSize); {
+		case errors.Is(err, errImageEncoding):
+			http.Error(w, `{"error": "Неверный формат аватара"}`, http.StatusBadRequest)
+			return false
+		case errors.Is(err, errImageTooLarge):
+			http.Error(w, `{"error": "Аватар больше 1 МБ"}`, http.StatusBadRequest)
+			return false
+		case err != nil:
+			http.Error(w, `{"error": "Аватар должен быть в формате JPEG или PNG"}`, http.StatusBadRequest)
+			return false
+		}
+	}
+
+	if err := userRepo.UpdateProfile(userID, &update); err != nil {
+		http.Error(w, `{"error": "Ошибка при обновлении профиля"}`, http.StatusInternalServerError)
+		return false
+	}
+
+	return true
+}
+
+func changePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if len(req.NewPassword) < 6 {
+		http.Error(w, `{"error": "Пароль должен быть не менее 6 символов"}`, http.StatusBadRequest)
+		return
+	}
+
+	user, err := userRepo.GetUserByID(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Пользователь не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	if !auth.CheckPassword(req.CurrentPassword, user.PasswordHash) {
+		http.Error(w, `{"error": "Текущий пароль указан неверно"}`, http.StatusForbidden)
+		return
+	}
+
+	passwordHash, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при обработке пароля"}`, http.StatusInternalServerError)
+		return
+	}
+
+	if err := userRepo.UpdatePassword(userID, passwordHash); err != nil {
+		http.Error(w, `{"error": "Ошибка при смене пароля"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Пароль изменён",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// deleteAccountHandler ставит аккаунт в очередь на удаление. До окончания
+// льготного периода можно войти, выгрузить данные и отменить удаление.
+func deleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	user, err := userRepo.GetUserByID(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Пользователь не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	if !auth.CheckPassword(req.Password, user.PasswordHash) {
+		http.Error(w, `{"error": "Пароль указан неверно"}`, http.StatusForbidden)
+		return
+	}
+
+	requestedAt, err := userRepo.RequestDeletion(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при запросе удаления"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":       "ok",
+		"message":      "Аккаунт будет удалён по окончании льготного периода",
+		"scheduled_at": requestedAt.Add(accountDeletionGracePeriod),
+		"export_url":   "/api/export",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func cancelDeletionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	if err := userRepo.CancelDeletion(userID); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusBadRequest)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Удаление аккаунта отменено",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func purgeDeletedAccountsLoop() {
+	ticker := time.NewTicker(accountPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, recipeIDs, err := userRepo.PurgeDeletedAccounts(time.Now().Add(-accountDeletionGracePeriod))
+		if err != nil {
+			log.Printf("Ошибка удаления аккаунтов: %v", err)
+		} else if purged > 0 {
+			log.Printf("🗑 Удалено аккаунтов по истечении льготного периода: %d", purged)
+		}
+		for _, recipeID := range recipeIDs {
+			similarIndex.Remove(recipeID)
+		}
+		<-ticker.C
+	}
+}
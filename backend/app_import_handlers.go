@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/appimport"
+	"culinary-book/backend/models"
+)
+
+// readAppImport читает файл другого приложения из тела запроса (имя файла — в параметре name)
+// и приводит найденные рецепты к тому виду, в каком они будут сохранены.
+// Предпросмотр и импорт разбирают файл одинаково, поэтому индексы рецептов у них совпадают.
+func readAppImport(w http.ResponseWriter, r *http.Request) (*appimport.Result, bool) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxArchiveSize))
+	if err != nil {
+		http.Error(w, `{"error": "Файл больше 100 МБ или загружен не полностью"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	result, err := appimport.Parse(r.URL.Query().Get("name"), data)
+	switch {
+	case errors.Is(err, appimport.ErrNotZip):
+		http.Error(w, `{"error": "Файл не является zip-архивом"}`, http.StatusBadRequest)
+		return nil, false
+	case errors.Is(err, appimport.ErrTooLarge):
+		http.Error(w, `{"error": "Распакованные файлы больше 300 МБ"}`, http.StatusBadRequest)
+		return nil, false
+	case err != nil:
+		http.Error(w, `{"error": "Формат файла не распознан: ожидается Paprika (.paprikarecipes), MealMaster или Cooklang (.cook)"}`, http.StatusUnprocessableEntity)
+		return nil, false
+	}
+	if len(result.Recipes) > maxArchiveRecipes {
+		http.Error(w, `{"error": "В файле больше 5000 рецептов"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	for i := range result.Recipes {
+		item := &result.Recipes[i]
+		recipe := &item.Recipe
+
+		if title := []rune(recipe.Title); len(title) > maxRecipeTitle {
+			recipe.Title = string(title[:maxRecipeTitle])
+			item.Warnings = append(item.Warnings, "название обрезано до 200 символов")
+		}
+		if recipe.Servings < 0 || recipe.Servings > maxRecipeServings {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("порции (%d) не сохранены", recipe.Servings))
+			recipe.Servings = 0
+		}
+		if recipe.CookingTime < 0 {
+			recipe.CookingTime = 0
+		}
+
+		if len(item.Image) > 0 {
+			switch {
+			case len(item.Image) > maxArchiveImageSize:
+				item.Warnings = append(item.Warnings, "изображение больше 10 МБ не загружено")
+				item.Image = nil
+			case imageExtension(item.Image) == ".bin":
+				item.Warnings = append(item.Warnings, "изображение в неизвестном формате не загружено")
+				item.Image = nil
+			}
+		}
+	}
+
+	return result, true
+}
+
+// appImportPreviewHandler показывает, что будет импортировано из файла Paprika, MealMaster
+// или Cooklang и что прочитать не удалось. Ничего не сохраняет.
+func appImportPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	result, ok := readAppImport(w, r)
+	if !ok {
+		return
+	}
+
+	byTitle, err := authoredRecipesByTitle(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении рецептов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]models.ImportPreviewItem, 0, len(result.Recipes))
+	for i, item := range result.Recipes {
+		recipe := item.Recipe
+		_, exists := byTitle[strings.ToLower(recipe.Title)]
+
+		ingredients := recipe.Ingredients
+		if ingredients == nil {
+			ingredients = []string{}
+		}
+		warnings := item.Warnings
+		if warnings == nil {
+			warnings = []string{}
+		}
+
+		items = append(items, models.ImportPreviewItem{
+			Index:        i,
+			Source:       item.Source,
+			Format:       item.Format,
+			Title:        recipe.Title,
+			Description:  recipe.Description,
+			Ingredients:  ingredients,
+			Instructions: recipe.Instructions,
+			CookingTime:  recipe.CookingTime,
+			Servings:     recipe.Servings,
+			Difficulty:   recipe.Difficulty,
+			HasImage:     len(item.Image) > 0,
+			Exists:       exists,
+			Warnings:     warnings,
+		})
+	}
+
+	failures := result.Failures
+	if failures == nil {
+		failures = []models.ImportFailure{}
+	}
+
+	response := map[string]interface{}{
+		"status":   "ok",
+		"count":    len(items),
+		"recipes":  items,
+		"failures": failures,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// appImportHandler сохраняет рецепты из того же файла, что был в предпросмотре. Параметр select —
+// индексы выбранных рецептов через запятую (без него импортируются все); mode — как в импорте архива.
+func appImportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = models.ImportSkip
+	}
+	if !models.IsValidImportMode(mode) {
+		http.Error(w, `{"error": "Режим импорта должен быть skip, overwrite или duplicate"}`, http.StatusBadRequest)
+		return
+	}
+
+	var selected map[int]bool
+	if param := r.URL.Query().Get("select"); param != "" {
+		selected = make(map[int]bool)
+		for _, part := range strings.Split(param, ",") {
+			index, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				http.Error(w, `{"error": "Неверный список рецептов"}`, http.StatusBadRequest)
+				return
+			}
+			selected[index] = true
+		}
+	}
+
+	parsed, ok := readAppImport(w, r)
+	if !ok {
+		return
+	}
+
+	byTitle, err := authoredRecipesByTitle(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении рецептов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	result := models.ImportResult{IDMap: map[int]int{}, Warnings: []string{}}
+	for _, failure := range parsed.Failures {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s пропущен: %s", failure.Source, failure.Reason))
+		result.Skipped++
+	}
+
+	for i := range parsed.Recipes {
+		if selected != nil && !selected[i] {
+			continue
+		}
+		item := &parsed.Recipes[i]
+		for _, warning := range item.Warnings {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", item.Recipe.Title, warning))
+		}
+
+		recipe := &item.Recipe
+		recipe.UserID = userID
+		if len(item.Image) > 0 {
+			recipe.ImageBase64 = base64.StdEncoding.EncodeToString(item.Image)
+		}
+
+		if _, err := storeImportedRecipe(recipe, mode, byTitle, &result); err != nil {
+			http.Error(w, `{"error": "Ошибка при сохранении импортированных рецептов"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Импорт завершен",
+		"result":  result,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
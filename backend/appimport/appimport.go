@@ -0,0 +1,230 @@
+// Package appimport читает рецепты, выгруженные из других приложений: архивы Paprika
+// (.paprikarecipes), текстовые файлы MealMaster и рецепты Cooklang (.cook).
+// Несколько файлов можно загрузить одним zip-архивом; картинка к рецепту Cooklang
+// лежит рядом с ним под тем же именем.
+//
+// Parse ничего не сохраняет: рецепты, которые удалось прочитать, и фрагменты,
+// которые прочитать не удалось, возвращаются отдельно, чтобы их можно было показать до импорта.
+package appimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/models"
+)
+
+const (
+	FormatPaprika    = "paprika"
+	FormatMealMaster = "mealmaster"
+	FormatCooklang   = "cooklang"
+
+	// Ограничения на распакованные данные: архив не должен раздуться в памяти
+	maxEntrySize = 20 << 20
+	maxTotalSize = 300 << 20
+)
+
+var (
+	ErrUnknownFormat = errors.New("формат файла не распознан: ожидается .paprikarecipes, MealMaster или .cook")
+	ErrTooLarge      = errors.New("распакованные файлы больше 300 МБ")
+	ErrNotZip        = errors.New("файл не является zip-архивом")
+
+	numberPattern = regexp.MustCompile(`\d+`)
+)
+
+// Recipe — прочитанный рецепт. Source — файл (или файл и номер рецепта в нём), откуда он взят;
+// Warnings — что из исходного рецепта не удалось перенести.
+type Recipe struct {
+	Source   string
+	Format   string
+	Recipe   models.Recipe
+	Image    []byte
+	Warnings []string
+}
+
+// Result — всё, что найдено в загруженном файле.
+type Result struct {
+	Recipes  []Recipe
+	Failures []models.ImportFailure
+}
+
+func (r *Result) fail(source string, err error) {
+	r.Failures = append(r.Failures, models.ImportFailure{Source: source, Reason: err.Error()})
+}
+
+// Parse определяет формат по имени файла, а для текстовых файлов — по содержимому.
+func Parse(name string, data []byte) (*Result, error) {
+	result := &Result{}
+	ext := strings.ToLower(path.Ext(name))
+
+	switch ext {
+	case ".paprikarecipes", ".zip":
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, ErrNotZip
+		}
+		if err := parseZip(zr, result); err != nil {
+			return nil, err
+		}
+	case ".paprikarecipe":
+		parsePaprikaEntry(name, data, result)
+	case ".cook":
+		parseCooklangFile(name, data, nil, result)
+	default:
+		if !isMealMaster(data) {
+			return nil, ErrUnknownFormat
+		}
+		parseMealMaster(name, data, result)
+	}
+
+	if len(result.Recipes) == 0 && len(result.Failures) == 0 {
+		return nil, ErrUnknownFormat
+	}
+	return result, nil
+}
+
+// parseZip разбирает архив Paprika (рецепты в сжатом gzip JSON) или zip с файлами разных форматов.
+func parseZip(zr *zip.Reader, result *Result) error {
+	files := make(map[string]*zip.File)
+	for _, file := range zr.File {
+		files[file.Name] = file
+	}
+
+	var total int64
+	read := func(file *zip.File) ([]byte, error) {
+		data, err := readEntry(file)
+		if err != nil {
+			return nil, err
+		}
+		if total += int64(len(data)); total > maxTotalSize {
+			return nil, ErrTooLarge
+		}
+		return data, nil
+	}
+
+	for _, file := range zr.File {
+		base := path.Base(file.Name)
+		if file.FileInfo().IsDir() || strings.HasPrefix(base, ".") || strings.HasPrefix(file.Name, "__MACOSX/") {
+			continue
+		}
+
+		ext := strings.ToLower(path.Ext(base))
+		if ext != ".paprikarecipe" && ext != ".cook" && !isTextExtension(ext) {
+			continue
+		}
+
+		data, err := read(file)
+		if errors.Is(err, ErrTooLarge) {
+			return err
+		}
+		if err != nil {
+			result.fail(file.Name, err)
+			continue
+		}
+
+		switch {
+		case ext == ".paprikarecipe":
+			parsePaprikaEntry(file.Name, data, result)
+		case ext == ".cook":
+			image := func(name string) ([]byte, error) {
+				file, ok := files[name]
+				if !ok {
+					return nil, nil
+				}
+				return read(file)
+			}
+			parseCooklangFile(file.Name, data, image, result)
+		case isMealMaster(data):
+			parseMealMaster(file.Name, data, result)
+		}
+	}
+	return nil
+}
+
+// readEntry читает файл архива, не доверяя заявленному размеру.
+func readEntry(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > maxEntrySize {
+		return nil, errors.New("файл слишком большой")
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return readLimited(reader)
+}
+
+func readLimited(reader io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, maxEntrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxEntrySize {
+		return nil, errors.New("файл слишком большой")
+	}
+	return data, nil
+}
+
+func isTextExtension(ext string) bool {
+	switch ext {
+	case ".mmf", ".mm", ".mmt", ".txt":
+		return true
+	}
+	return false
+}
+
+// difficulty сводит сложность из другого приложения к значениям приложения; неизвестная возвращается пустой.
+func difficulty(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == "":
+		return ""
+	case strings.Contains(value, "easy"), strings.Contains(value, "simple"),
+		strings.Contains(value, "легк"), strings.Contains(value, "лёгк"), strings.Contains(value, "прост"):
+		return "легкая"
+	case strings.Contains(value, "medium"), strings.Contains(value, "moderate"), strings.Contains(value, "intermediate"),
+		strings.Contains(value, "normal"), strings.Contains(value, "средн"):
+		return "средняя"
+	case strings.Contains(value, "hard"), strings.Contains(value, "difficult"), strings.Contains(value, "сложн"):
+		return "сложная"
+	}
+	return ""
+}
+
+// servings берёт первое число из строки вида «4 servings» или «Makes 6-8».
+func servings(value string) (int, bool) {
+	match := numberPattern.FindString(value)
+	if match == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(match)
+	return n, err == nil
+}
+
+// joinParagraphs собирает описание из непустых частей, разделяя их пустой строкой.
+func joinParagraphs(parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "\n\n")
+}
+
+// splitLines возвращает непустые строки без пробелов по краям.
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
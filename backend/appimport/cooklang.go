@@ -0,0 +1,326 @@
+package appimport
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"culinary-book/backend/dietary"
+	"culinary-book/backend/markdown"
+	"culinary-book/backend/models"
+)
+
+// Cooklang размечает ингредиенты прямо в тексте шагов:
+//
+//	>> servings: 4
+//	Разбейте @яйца{3} в миску, добавьте @мука пшеничная{125%г}.
+//	Жарьте на #сковорода{} ~{10%минут}.
+//
+// Абзацы — шаги, строки «= Тесто» — разделы, «>» — заметки, «--» и «[- -]» — комментарии.
+// Метаданные записываются строками «>> ключ: значение» или YAML front matter.
+var (
+	cooklangIngredient = regexp.MustCompile(`@([@&?+\-]*)(?:([^@#~{}\n]+?)\{([^}]*)\}|([\p{L}\p{N}_]+))(?:\(([^)]*)\))?`)
+	cooklangCookware   = regexp.MustCompile(`#(?:([^@#~{}\n]+?)\{[^}]*\}|([\p{L}\p{N}_]+))`)
+	cooklangTimer      = regexp.MustCompile(`~(?:([^@#~{}\n]*?)\{([^}]*)\}|([\p{L}\p{N}_]+))`)
+	cooklangComment    = regexp.MustCompile(`(?s)\[-.*?-\]`)
+
+	cooklangImageExtensions = []string{".jpg", ".jpeg", ".png", ".webp"}
+)
+
+type cooklangAmount struct {
+	name, quantity, unit, note string
+}
+
+func (a cooklangAmount) String() string {
+	line := a.name
+	if amount := strings.TrimSpace(a.quantity + " " + a.unit); amount != "" {
+		line += " — " + amount
+	}
+	if a.note != "" {
+		line += ", " + a.note
+	}
+	return line
+}
+
+// parseCooklangFile читает рецепт .cook; image загружает файл из того же архива (или nil для одиночного файла).
+func parseCooklangFile(source string, data []byte, image func(name string) ([]byte, error), result *Result) {
+	text := strings.TrimPrefix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\ufeff")
+
+	item := Recipe{Source: source, Format: FormatCooklang}
+	recipe := &item.Recipe
+	recipe.Visibility = models.VisibilityPrivate
+	recipe.Title = strings.TrimSuffix(path.Base(source), path.Ext(source))
+
+	metadata := make(map[string]interface{})
+	if strings.HasPrefix(text, "---\n") {
+		if end := strings.Index(text[4:], "\n---"); end >= 0 {
+			if err := yaml.Unmarshal([]byte(text[4:4+end]), &metadata); err != nil {
+				result.fail(source, fmt.Errorf("ошибка в front matter: %v", err))
+				return
+			}
+			text = text[4+end+4:]
+		}
+	}
+
+	text = cooklangComment.ReplaceAllString(text, "")
+
+	var notes, steps, paragraph []string
+	var amounts []cooklangAmount
+	flush := func() {
+		if len(paragraph) > 0 {
+			steps = append(steps, cooklangStep(strings.Join(paragraph, " "), &amounts))
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if comment := strings.Index(line, "--"); comment >= 0 {
+			line = line[:comment]
+		}
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, ">>"):
+			if key, value, ok := strings.Cut(strings.TrimPrefix(trimmed, ">>"), ":"); ok {
+				metadata[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+			}
+		case strings.HasPrefix(trimmed, ">"):
+			notes = append(notes, strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))
+		case strings.HasPrefix(trimmed, "="):
+			flush()
+			if section := strings.TrimSpace(strings.Trim(trimmed, "=")); section != "" {
+				steps = append(steps, section+":")
+			}
+		case trimmed == "":
+			flush()
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	for _, amount := range amounts {
+		recipe.Ingredients = append(recipe.Ingredients, amount.String())
+	}
+	recipe.Instructions = strings.Join(steps, "\n")
+	if len(recipe.Ingredients) == 0 && recipe.Instructions == "" {
+		result.fail(source, fmt.Errorf("«%s»: нет ни ингредиентов, ни шагов", recipe.Title))
+		return
+	}
+
+	applyCooklangMetadata(&item, metadata, strings.Join(notes, "\n"))
+
+	if image != nil {
+		base := strings.TrimSuffix(source, path.Ext(source))
+		names := make([]string, 0, len(cooklangImageExtensions)+1)
+		if file := metadataString(metadata, "image"); file != "" {
+			names = append(names, path.Join(path.Dir(source), file))
+		}
+		for _, ext := range cooklangImageExtensions {
+			names = append(names, base+ext, base+strings.ToUpper(ext))
+		}
+		for _, name := range names {
+			data, err := image(name)
+			if err != nil {
+				item.Warnings = append(item.Warnings, fmt.Sprintf("изображение %s не загружено: %v", path.Base(name), err))
+				break
+			}
+			if data != nil {
+				item.Image = data
+				break
+			}
+		}
+	}
+
+	result.Recipes = append(result.Recipes, item)
+}
+
+// cooklangStep убирает разметку из шага и собирает ингредиенты. Одинаковые ингредиенты
+// с числовым количеством в одной единице складываются, как в списке покупок Cooklang.
+func cooklangStep(text string, amounts *[]cooklangAmount) string {
+	text = cooklangIngredient.ReplaceAllStringFunc(text, func(token string) string {
+		match := cooklangIngredient.FindStringSubmatch(token)
+		name := strings.TrimSpace(match[2] + match[4])
+		// @& ссылается на то, что получилось в предыдущих шагах, а не на продукт
+		if strings.Contains(match[1], "&") {
+			return name
+		}
+
+		quantity, unit, _ := strings.Cut(match[3], "%")
+		amount := cooklangAmount{
+			name:     name,
+			quantity: strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(quantity), "=")),
+			unit:     strings.TrimSpace(unit),
+			note:     strings.TrimSpace(match[5]),
+		}
+		addCooklangAmount(amounts, amount)
+		return name
+	})
+
+	text = cooklangCookware.ReplaceAllStringFunc(text, func(token string) string {
+		match := cooklangCookware.FindStringSubmatch(token)
+		return strings.TrimSpace(match[1] + match[2])
+	})
+
+	text = cooklangTimer.ReplaceAllStringFunc(text, func(token string) string {
+		match := cooklangTimer.FindStringSubmatch(token)
+		if match[3] != "" {
+			return match[3]
+		}
+		quantity, unit, _ := strings.Cut(match[2], "%")
+		if duration := strings.TrimSpace(strings.TrimSpace(quantity) + " " + strings.TrimSpace(unit)); duration != "" {
+			return duration
+		}
+		return strings.TrimSpace(match[1])
+	})
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func addCooklangAmount(amounts *[]cooklangAmount, amount cooklangAmount) {
+	for i, existing := range *amounts {
+		if !strings.EqualFold(existing.name, amount.name) || !strings.EqualFold(existing.unit, amount.unit) {
+			continue
+		}
+		if existing.quantity == "" && amount.quantity == "" {
+			return
+		}
+		a, okA := parseQuantity(existing.quantity)
+		b, okB := parseQuantity(amount.quantity)
+		if okA && okB && existing.note == amount.note {
+			(*amounts)[i].quantity = strconv.FormatFloat(a+b, 'f', -1, 64)
+			return
+		}
+	}
+	*amounts = append(*amounts, amount)
+}
+
+func parseQuantity(text string) (float64, bool) {
+	text = strings.ReplaceAll(strings.TrimSpace(text), ",", ".")
+	if numerator, denominator, ok := strings.Cut(text, "/"); ok {
+		n, errN := strconv.ParseFloat(strings.TrimSpace(numerator), 64)
+		d, errD := strconv.ParseFloat(strings.TrimSpace(denominator), 64)
+		if errN != nil || errD != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	return value, err == nil
+}
+
+// applyCooklangMetadata переносит метаданные в рецепт; незнакомые ключи пропускаются.
+func applyCooklangMetadata(item *Recipe, metadata map[string]interface{}, notes string) {
+	recipe := &item.Recipe
+
+	if title := metadataString(metadata, "title"); title != "" {
+		recipe.Title = title
+	}
+
+	for _, key := range []string{"servings", "serves", "yield"} {
+		if value := metadataString(metadata, key); value != "" {
+			if n, ok := servings(value); ok {
+				recipe.Servings = n
+			} else {
+				item.Warnings = append(item.Warnings, fmt.Sprintf("порции «%s» не распознаны", value))
+			}
+			break
+		}
+	}
+
+	minutes := func(key string) int {
+		value := metadataString(metadata, key)
+		if value == "" {
+			return 0
+		}
+		n, ok := markdown.ParseMinutes(value)
+		if !ok {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("время «%s» не распознано", value))
+		}
+		return n
+	}
+	for _, key := range []string{"time", "duration", "total time", "time required"} {
+		if recipe.CookingTime = minutes(key); recipe.CookingTime > 0 {
+			break
+		}
+	}
+	if recipe.CookingTime == 0 {
+		recipe.CookingTime = minutes("prep time") + minutes("cook time")
+	}
+
+	if value := metadataString(metadata, "difficulty"); value != "" {
+		if recipe.Difficulty = difficulty(value); recipe.Difficulty == "" {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("сложность «%s» не распознана", value))
+		}
+	}
+
+	description := metadataString(metadata, "description")
+	if description == "" {
+		description = metadataString(metadata, "introduction")
+	}
+	var sourceLine string
+	if value := strings.TrimSpace(metadataString(metadata, "source") + " " + metadataString(metadata, "source.url")); value != "" {
+		sourceLine = "Источник: " + value
+	}
+	recipe.Description = joinParagraphs(description, notes, sourceLine)
+
+	if tags, ok := metadataList(metadata, "tags"); ok {
+		var labels, unknown []string
+		for _, tag := range tags {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if dietary.IsValidLabel(tag) {
+				labels = append(labels, tag)
+			} else if tag != "" {
+				unknown = append(unknown, tag)
+			}
+		}
+		// Теги пишет автор в другом приложении: знакомые метки добавляются к найденным по ингредиентам,
+		// но ничего не снимают — иначе тег «ужин» убрал бы из рецепта с орехами и мукой аллергены
+		if len(labels) > 0 {
+			detected := dietary.Detect(recipe.Ingredients).Labels
+			recipe.LabelOverrides = dietary.Overrides(recipe.Ingredients, append(detected, labels...))
+		}
+		if len(unknown) > 0 {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("теги %s не сохранены", strings.Join(unknown, ", ")))
+		}
+	}
+}
+
+// metadataString читает значение как строку; в front matter источник может быть объектом с name и url.
+func metadataString(metadata map[string]interface{}, key string) string {
+	switch value := metadata[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(value)
+	case map[string]interface{}:
+		var parts []string
+		for _, field := range []string{"name", "url"} {
+			if s, ok := value[field].(string); ok && s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, " ")
+	default:
+		return strings.TrimSpace(fmt.Sprint(value))
+	}
+}
+
+// metadataList читает список из YAML или из строки через запятую.
+func metadataList(metadata map[string]interface{}, key string) ([]string, bool) {
+	switch value := metadata[key].(type) {
+	case string:
+		return strings.Split(value, ","), true
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, element := range value {
+			list = append(list, fmt.Sprint(element))
+		}
+		return list, true
+	}
+	return nil, false
+}
@@ -0,0 +1,253 @@
+package appimport
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"culinary-book/backend/models"
+)
+
+// MealMaster — текстовый формат, в одном файле может быть много рецептов:
+//
+//	MMMMM----- Recipe via Meal-Master (tm) v8.05
+//
+//	      Title: Apple Pie
+//	 Categories: Desserts
+//	      Yield: 8 servings
+//
+//	      2 c  Flour
+//	    1/2 ts Salt
+//
+//	MMMMM---------------------------FILLING----------------------------
+//	      6    Apples
+//
+//	  Mix flour and salt...
+//
+//	MMMMM
+//
+// Ингредиенты записаны колонками: количество (7 символов), код единицы (2 символа), название;
+// длинные списки печатаются в две колонки. Строка с «-» в начале названия продолжает предыдущую.
+var (
+	mealMasterHeader  = regexp.MustCompile(`(?i)^(?:MMMMM|-----).*meal-?master`)
+	mealMasterEnd     = regexp.MustCompile(`^(?:MMMMM|-----)\s*$`)
+	mealMasterSection = regexp.MustCompile(`^(?:MMMMM|-----)-*\s*([^-].*?)\s*-+\s*$`)
+	// Подзаголовок, набранный с отступом или без MMMMM: «   ---FILLING---»
+	mealMasterDivider = regexp.MustCompile(`^-{3,}\s*(.*?)\s*-{3,}$`)
+	// Подзаголовок во второй колонке, прочитанный вместе с ингредиентом первой: «1 Egg   ---FILLING---»
+	mealMasterTrailingDivider = regexp.MustCompile(`\s+-{3,}.*-{3,}$`)
+	mealMasterField           = regexp.MustCompile(`^\s*(Title|Categories|Yield|Servings)\s*:\s*(.*)$`)
+	// Количество справа в первых 7 символах, код единицы в 9–10-м, название с 12-го
+	mealMasterIngredient = regexp.MustCompile(`^([ \d./]{7}) ([ A-Za-z]{2}) (\S.*)$`)
+)
+
+// mealMasterUnits расшифровывает коды единиц MealMaster.
+var mealMasterUnits = map[string]string{
+	"x": "", "ea": "",
+	"sm": "small", "md": "medium", "lg": "large",
+	"cn": "can", "pk": "package", "ct": "carton", "bn": "bunch", "sl": "slice",
+	"pn": "pinch", "dr": "drop", "ds": "dash",
+	"t": "tsp", "ts": "tsp", "T": "tbsp", "tb": "tbsp",
+	"fl": "fl oz", "c": "cup", "pt": "pint", "qt": "quart", "ga": "gallon",
+	"oz": "oz", "lb": "lb",
+	"ml": "ml", "cb": "cc", "cl": "cl", "dl": "dl", "l": "l",
+	"mg": "mg", "cg": "cg", "dg": "dg", "g": "g", "kg": "kg",
+}
+
+// Ширина колонки ингредиентов при печати в две колонки
+const mealMasterColumn = 41
+
+func isMealMaster(data []byte) bool {
+	for _, line := range strings.SplitN(string(data), "\n", 200) {
+		if mealMasterHeader.MatchString(strings.TrimSpace(line)) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseMealMaster(source string, data []byte, result *Result) {
+	text := strings.ReplaceAll(strings.ReplaceAll(string(data), "\r\n", "\n"), "\t", "        ")
+
+	var block []string
+	inRecipe := false
+	number := 0
+	flush := func(complete bool) {
+		number++
+		name := fmt.Sprintf("%s, рецепт %d", source, number)
+		item, err := mealMasterRecipe(block)
+		if err == nil && !complete {
+			item.Warnings = append(item.Warnings, "нет строки окончания рецепта, файл может быть обрезан")
+		}
+		if err != nil {
+			result.fail(name, err)
+		} else {
+			item.Source = name
+			result.Recipes = append(result.Recipes, *item)
+		}
+		block = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \r")
+		switch {
+		case mealMasterHeader.MatchString(strings.TrimSpace(line)):
+			if inRecipe {
+				flush(false)
+			}
+			inRecipe = true
+		case !inRecipe:
+		case mealMasterEnd.MatchString(line):
+			flush(true)
+			inRecipe = false
+		default:
+			block = append(block, line)
+		}
+	}
+	if inRecipe {
+		flush(false)
+	}
+}
+
+// mealMasterRecipe разбирает строки одного рецепта между заголовком и строкой окончания.
+func mealMasterRecipe(lines []string) (*Recipe, error) {
+	item := &Recipe{Format: FormatMealMaster}
+	recipe := &item.Recipe
+	recipe.Visibility = models.VisibilityPrivate
+
+	// Поля в начале рецепта
+	i := 0
+	for ; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		match := mealMasterField.FindStringSubmatch(lines[i])
+		if match == nil {
+			break
+		}
+		switch value := strings.TrimSpace(match[2]); match[1] {
+		case "Title":
+			recipe.Title = value
+		case "Yield", "Servings":
+			if n, ok := servings(value); ok {
+				recipe.Servings = n
+			} else if value != "" {
+				item.Warnings = append(item.Warnings, fmt.Sprintf("выход «%s» не распознан", value))
+			}
+		}
+	}
+	if recipe.Title == "" {
+		return nil, errors.New("у рецепта нет названия")
+	}
+
+	// Ингредиенты идут до первой строки, которая не похожа на ингредиент
+	var paragraph []string
+	var steps []string
+	inIngredients := true
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		match := mealMasterSection.FindStringSubmatch(line)
+		if match == nil {
+			match = mealMasterDivider.FindStringSubmatch(trimmed)
+		}
+		if match != nil {
+			// Подзаголовки групп ингредиентов пропускаются, как подзаголовки в Markdown;
+			// в инструкции подзаголовок становится строкой с двоеточием и не нумеруется
+			if !inIngredients {
+				if len(paragraph) > 0 {
+					steps = append(steps, strings.Join(paragraph, " "))
+					paragraph = nil
+				}
+				if match[1] != "" {
+					steps = append(steps, match[1]+":")
+				}
+			}
+			continue
+		}
+
+		if inIngredients {
+			if trimmed == "" {
+				continue
+			}
+			if ingredients, ok := mealMasterIngredients(line); ok {
+				for _, ingredient := range ingredients {
+					appendMealMasterIngredient(recipe, ingredient)
+				}
+				continue
+			}
+			inIngredients = false
+		}
+
+		if trimmed == "" {
+			if len(paragraph) > 0 {
+				steps = append(steps, strings.Join(paragraph, " "))
+				paragraph = nil
+			}
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+	if len(paragraph) > 0 {
+		steps = append(steps, strings.Join(paragraph, " "))
+	}
+
+	recipe.Instructions = strings.Join(steps, "\n")
+	if len(recipe.Ingredients) == 0 && recipe.Instructions == "" {
+		return nil, fmt.Errorf("«%s»: нет ни ингредиентов, ни инструкции", recipe.Title)
+	}
+	return item, nil
+}
+
+// mealMasterIngredients разбирает строку ингредиентов в одну или две колонки.
+func mealMasterIngredients(line string) ([]string, bool) {
+	// Колонки отсчитываются в символах: названия могут быть не латиницей
+	if runes := []rune(line); len(runes) > mealMasterColumn+11 && strings.TrimSpace(string(runes[mealMasterColumn-2:mealMasterColumn])) == "" {
+		left, okLeft := mealMasterColumnIngredient(strings.TrimRight(string(runes[:mealMasterColumn]), " "))
+		right, okRight := mealMasterColumnIngredient(string(runes[mealMasterColumn:]))
+		if okLeft && okRight {
+			return []string{left, right}, true
+		}
+	}
+	ingredient, ok := mealMasterColumnIngredient(line)
+	if !ok {
+		return nil, false
+	}
+	return []string{ingredient}, true
+}
+
+func mealMasterColumnIngredient(column string) (string, bool) {
+	match := mealMasterIngredient.FindStringSubmatch(column)
+	if match == nil {
+		return "", false
+	}
+	code := strings.TrimSpace(match[2])
+	unit, known := mealMasterUnits[code]
+	if !known && code != "" {
+		return "", false
+	}
+
+	name := strings.TrimSpace(match[3])
+	quantity := strings.TrimSpace(match[1])
+	if quantity == "" && unit == "" {
+		return name, true
+	}
+	return strings.Join(strings.Fields(strings.Join([]string{quantity, unit, name}, " ")), " "), true
+}
+
+// appendMealMasterIngredient добавляет ингредиент; строка с «-» продолжает предыдущий.
+// Подзаголовки («---FILLING---») пропускаются, в том числе попавшие в конец ингредиента из второй колонки.
+func appendMealMasterIngredient(recipe *models.Recipe, ingredient string) {
+	ingredient = mealMasterTrailingDivider.ReplaceAllString(ingredient, "")
+	if ingredient == "" || mealMasterDivider.MatchString(ingredient) {
+		return
+	}
+	if strings.HasPrefix(ingredient, "-") && len(recipe.Ingredients) > 0 {
+		last := len(recipe.Ingredients) - 1
+		recipe.Ingredients[last] += " " + strings.TrimSpace(strings.TrimPrefix(ingredient, "-"))
+		return
+	}
+	recipe.Ingredients = append(recipe.Ingredients, ingredient)
+}
@@ -0,0 +1,139 @@
+package appimport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"culinary-book/backend/markdown"
+	"culinary-book/backend/models"
+)
+
+// paprikaRecipe — рецепт в выгрузке Paprika 3. Каждый рецепт в архиве .paprikarecipes —
+// отдельный файл .paprikarecipe со сжатым gzip JSON; фото встроено в photo_data.
+type paprikaRecipe struct {
+	Name            string         `json:"name"`
+	Description     string         `json:"description"`
+	Ingredients     string         `json:"ingredients"`
+	Directions      string         `json:"directions"`
+	Notes           string         `json:"notes"`
+	NutritionalInfo string         `json:"nutritional_info"`
+	Servings        string         `json:"servings"`
+	Difficulty      string         `json:"difficulty"`
+	PrepTime        string         `json:"prep_time"`
+	CookTime        string         `json:"cook_time"`
+	TotalTime       string         `json:"total_time"`
+	Source          string         `json:"source"`
+	SourceURL       string         `json:"source_url"`
+	PhotoData       string         `json:"photo_data"`
+	Photos          []paprikaPhoto `json:"photos"`
+	Created         string         `json:"created"`
+	InTrash         bool           `json:"in_trash"`
+}
+
+type paprikaPhoto struct {
+	Data string `json:"data"`
+}
+
+const paprikaTimeLayout = "2006-01-02 15:04:05"
+
+func parsePaprikaEntry(source string, data []byte, result *Result) {
+	// Отдельный рецепт из Paprika сжат gzip; несжатый JSON тоже принимается
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			result.fail(source, err)
+			return
+		}
+		data, err = readLimited(reader)
+		if err != nil {
+			result.fail(source, fmt.Errorf("ошибка распаковки: %v", err))
+			return
+		}
+	}
+
+	var paprika paprikaRecipe
+	if err := json.Unmarshal(data, &paprika); err != nil {
+		result.fail(source, fmt.Errorf("неверный JSON рецепта Paprika: %v", err))
+		return
+	}
+	if paprika.InTrash {
+		result.fail(source, fmt.Errorf("«%s» в корзине Paprika", paprika.Name))
+		return
+	}
+	if strings.TrimSpace(paprika.Name) == "" {
+		result.fail(source, markdown.ErrNoTitle)
+		return
+	}
+
+	item := Recipe{Source: source, Format: FormatPaprika}
+	recipe := &item.Recipe
+	recipe.Title = strings.TrimSpace(paprika.Name)
+	recipe.Ingredients = splitLines(paprika.Ingredients)
+	recipe.Instructions = strings.Join(splitLines(paprika.Directions), "\n")
+	recipe.Visibility = models.VisibilityPrivate
+
+	var notes, sourceLine string
+	if note := strings.TrimSpace(paprika.Notes); note != "" {
+		notes = "Заметки:\n" + note
+	}
+	if paprika.Source != "" || paprika.SourceURL != "" {
+		sourceLine = "Источник: " + strings.TrimSpace(paprika.Source+" "+paprika.SourceURL)
+	}
+	recipe.Description = joinParagraphs(paprika.Description, notes, sourceLine)
+
+	switch {
+	case paprika.TotalTime != "":
+		recipe.CookingTime = paprikaMinutes(&item, paprika.TotalTime)
+	case paprika.PrepTime != "" || paprika.CookTime != "":
+		recipe.CookingTime = paprikaMinutes(&item, paprika.PrepTime) + paprikaMinutes(&item, paprika.CookTime)
+	}
+
+	if paprika.Servings != "" {
+		if n, ok := servings(paprika.Servings); ok {
+			recipe.Servings = n
+		} else {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("порции «%s» не распознаны", paprika.Servings))
+		}
+	}
+	if paprika.Difficulty != "" {
+		if recipe.Difficulty = difficulty(paprika.Difficulty); recipe.Difficulty == "" {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("сложность «%s» не распознана", paprika.Difficulty))
+		}
+	}
+	if strings.TrimSpace(paprika.NutritionalInfo) != "" {
+		item.Warnings = append(item.Warnings, "пищевая ценность из Paprika не сохраняется")
+	}
+	if created, err := time.Parse(paprikaTimeLayout, paprika.Created); err == nil {
+		recipe.CreatedAt = created
+	}
+
+	photo := paprika.PhotoData
+	if photo == "" && len(paprika.Photos) > 0 {
+		photo = paprika.Photos[0].Data
+	}
+	if photo != "" {
+		if image, err := base64.StdEncoding.DecodeString(photo); err == nil {
+			item.Image = image
+		} else {
+			item.Warnings = append(item.Warnings, "фото повреждено")
+		}
+	}
+
+	result.Recipes = append(result.Recipes, item)
+}
+
+func paprikaMinutes(item *Recipe, value string) int {
+	if strings.TrimSpace(value) == "" {
+		return 0
+	}
+	minutes, ok := markdown.ParseMinutes(value)
+	if !ok {
+		item.Warnings = append(item.Warnings, fmt.Sprintf("время «%s» не распознано", value))
+	}
+	return minutes
+}
@@ -0,0 +1,663 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"culinary-book/backend/dietary"
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+)
+
+const (
+	maxArchiveSize      = 100 << 20
+	maxArchiveJSONSize  = 20 << 20
+	maxArchiveImageSize = 10 << 20
+	maxArchiveRecipes   = 5000
+	maxRecipeTitle      = 200
+)
+
+// imageExtension подбирает расширение файла изображения по его содержимому.
+func imageExtension(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	}
+	return ".bin"
+}
+
+// writeArchiveImage кладёт изображение в images/ и возвращает путь к нему в архиве;
+// пустая строка означает, что изображение не удалось раскодировать.
+func writeArchiveImage(zw *zip.Writer, name, encoded string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", nil
+	}
+	path := models.ArchiveImagesDir + name + imageExtension(data)
+	file, err := zw.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(data); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// archiveRecipeRef описывает ссылку на рецепт: свой рецепт — по ID из recipes.json, чужой — с названием
+// и автором для проверки при импорте. Недоступный больше рецепт не выгружается.
+func archiveRecipeRef(recipeID int, own map[int]models.Recipe) (models.ArchiveRecipeRef, bool) {
+	if recipe, ok := own[recipeID]; ok {
+		return models.ArchiveRecipeRef{RecipeID: recipeID, Title: recipe.Title, Own: true}, true
+	}
+	recipe, err := recipeRepo.GetRecipeByID(recipeID)
+	if err != nil {
+		return models.ArchiveRecipeRef{}, false
+	}
+	return models.ArchiveRecipeRef{
+		RecipeID:   recipe.ID,
+		Title:      recipe.Title,
+		AuthorName: recipe.AuthorName,
+	}, true
+}
+
+func writeArchiveJSON(zw *zip.Writer, name string, value interface{}) error {
+	file, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// readArchiveFile читает файл архива, не доверяя заявленному размеру.
+func readArchiveFile(files map[string]*zip.File, name string, limit int64) ([]byte, error) {
+	file, ok := files[name]
+	if !ok {
+		return nil, errors.New("файл не найден")
+	}
+	if file.UncompressedSize64 > uint64(limit) {
+		return nil, errors.New("файл слишком большой")
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, errors.New("файл слишком большой")
+	}
+	return data, nil
+}
+
+func exportArchiveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	user, err := userRepo.GetUserByID(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Пользователь не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	recipes, err := recipeRepo.GetAuthoredRecipes(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при выгрузке рецептов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	favoriteIDs, err := favoriteRepo.GetFavoriteRecipes(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при выгрузке избранного"}`, http.StatusInternalServerError)
+		return
+	}
+
+	collections, err := collectionRepo.GetCollections(userID, 0)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при выгрузке коллекций"}`, http.StatusInternalServerError)
+		return
+	}
+
+	reviews, err := reviewRepo.GetReviewsByUser(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при выгрузке отзывов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	var buffer bytes.Buffer
+	zw := zip.NewWriter(&buffer)
+
+	byID := make(map[int]models.Recipe)
+	archiveRecipes := []models.ArchiveRecipe{}
+	images := 0
+
+	for _, recipe := range recipes {
+		byID[recipe.ID] = recipe
+		archived := models.ArchiveRecipe{
+			ID:               recipe.ID,
+			Title:            recipe.Title,
+			Description:      recipe.Description,
+			Ingredients:      recipe.Ingredients,
+			Instructions:     recipe.Instructions,
+			CookingTime:      recipe.CookingTime,
+			Servings:         recipe.Servings,
+			Difficulty:       recipe.Difficulty,
+			Visibility:       recipe.Visibility,
+			Labels:           recipe.Labels,
+			LabelOverrides:   recipe.LabelOverrides,
+			ForkedFromTitle:  recipe.ForkedFromTitle,
+			ForkedFromAuthor: recipe.ForkedFromAuthor,
+			CreatedAt:        recipe.CreatedAt,
+			UpdatedAt:        recipe.UpdatedAt,
+		}
+
+		if recipe.ImageBase64 != "" {
+			path, err := writeArchiveImage(zw, strconv.Itoa(recipe.ID), recipe.ImageBase64)
+			if err != nil {
+				http.Error(w, `{"error": "Ошибка при создании архива"}`, http.StatusInternalServerError)
+				return
+			}
+			if path != "" {
+				archived.Image = path
+				images++
+			}
+		}
+
+		archiveRecipes = append(archiveRecipes, archived)
+	}
+
+	favorites := []models.ArchiveRecipeRef{}
+	for _, recipeID := range favoriteIDs {
+		if ref, ok := archiveRecipeRef(recipeID, byID); ok {
+			favorites = append(favorites, ref)
+		}
+	}
+
+	archiveCollections := []models.ArchiveCollection{}
+	for _, collection := range collections {
+		recipeIDs, err := collectionRepo.GetRecipeIDs(collection.ID)
+		if err != nil {
+			http.Error(w, `{"error": "Ошибка при выгрузке коллекций"}`, http.StatusInternalServerError)
+			return
+		}
+
+		archived := models.ArchiveCollection{
+			Name:        collection.Name,
+			Description: collection.Description,
+			Recipes:     []models.ArchiveRecipeRef{},
+		}
+		for _, recipeID := range recipeIDs {
+			if ref, ok := archiveRecipeRef(recipeID, byID); ok {
+				archived.Recipes = append(archived.Recipes, ref)
+			}
+		}
+
+		if collection.CoverBase64 != "" {
+			path, err := writeArchiveImage(zw, fmt.Sprintf("collection-%d", collection.ID), collection.CoverBase64)
+			if err != nil {
+				http.Error(w, `{"error": "Ошибка при создании архива"}`, http.StatusInternalServerError)
+				return
+			}
+			if path != "" {
+				archived.Cover = path
+				images++
+			}
+		}
+
+		archiveCollections = append(archiveCollections, archived)
+	}
+
+	archiveReviews := []models.ArchiveReview{}
+	for _, review := range reviews {
+		ref, ok := archiveRecipeRef(review.RecipeID, byID)
+		if !ok {
+			continue
+		}
+		archiveReviews = append(archiveReviews, models.ArchiveReview{
+			ArchiveRecipeRef: ref,
+			Rating:           review.Rating,
+			Text:             review.Text,
+			CreatedAt:        review.CreatedAt,
+			UpdatedAt:        review.UpdatedAt,
+		})
+	}
+
+	manifest := models.ArchiveManifest{
+		Format:     models.ArchiveFormat,
+		Version:    models.ArchiveVersion,
+		ExportedAt: time.Now(),
+		Username:   user.Username,
+		Files: map[string]int{
+			models.ArchiveRecipesFile:     len(archiveRecipes),
+			models.ArchiveFavoritesFile:   len(favorites),
+			models.ArchiveCollectionsFile: len(archiveCollections),
+			models.ArchiveReviewsFile:     len(archiveReviews),
+			models.ArchiveImagesDir:       images,
+		},
+	}
+
+	for _, part := range []struct {
+		name  string
+		value interface{}
+	}{
+		{models.ArchiveManifestFile, manifest},
+		{models.ArchiveRecipesFile, archiveRecipes},
+		{models.ArchiveFavoritesFile, favorites},
+		{models.ArchiveCollectionsFile, archiveCollections},
+		{models.ArchiveReviewsFile, archiveReviews},
+	} {
+		if err := writeArchiveJSON(zw, part.name, part.value); err != nil {
+			http.Error(w, `{"error": "Ошибка при создании архива"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		http.Error(w, `{"error": "Ошибка при создании архива"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="culinary-book-%s.zip"`, time.Now().Format(models.DateLayout)))
+	w.Write(buffer.Bytes())
+}
+
+// archiveRecipeToModel проверяет рецепт из архива и переносит его в модель; изображение читается из архива.
+func archiveRecipeToModel(archived models.ArchiveRecipe, files map[string]*zip.File, result *models.ImportResult) (*models.Recipe, bool) {
+	title := strings.TrimSpace(archived.Title)
+	if title == "" || len([]rune(title)) > maxRecipeTitle {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Рецепт %d пропущен: неверное название", archived.ID))
+		return nil, false
+	}
+
+	recipe := &models.Recipe{
+		Title:        title,
+		Description:  archived.Description,
+		Ingredients:  archived.Ingredients,
+		Instructions: archived.Instructions,
+		CookingTime:  archived.CookingTime,
+		Servings:     archived.Servings,
+		Difficulty:   archived.Difficulty,
+		Visibility:   archived.Visibility,
+		CreatedAt:    archived.CreatedAt,
+		UpdatedAt:    archived.UpdatedAt,
+	}
+
+	if !models.IsValidVisibility(recipe.Visibility) {
+		recipe.Visibility = models.VisibilityPrivate
+	}
+	if recipe.Servings < 0 || recipe.Servings > maxRecipeServings {
+		recipe.Servings = 0
+	}
+	if recipe.CookingTime < 0 {
+		recipe.CookingTime = 0
+	}
+
+	for label, present := range archived.LabelOverrides {
+		if dietary.IsValidLabel(label) {
+			if recipe.LabelOverrides == nil {
+				recipe.LabelOverrides = make(map[string]bool)
+			}
+			recipe.LabelOverrides[label] = present
+		}
+	}
+
+	if archived.Image != "" {
+		data, err := readArchiveFile(files, archived.Image, maxArchiveImageSize)
+		switch {
+		case err != nil:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Изображение рецепта «%s» не восстановлено: %v", title, err))
+		case imageExtension(data) == ".bin":
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Изображение рецепта «%s» в неизвестном формате не восстановлено", title))
+		default:
+			recipe.ImageBase64 = base64.StdEncoding.EncodeToString(data)
+		}
+	}
+
+	return recipe, true
+}
+
+// importTitleKey — ключ, по которому импорт сравнивает названия рецептов: без учёта регистра и пробелов по краям.
+func importTitleKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+// authoredRecipesByTitle — рецепты пользователя по названию: по нему импорт находит конфликты.
+func authoredRecipesByTitle(userID int) (map[string]models.Recipe, error) {
+	existing, err := recipeRepo.GetAuthoredRecipes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byTitle := make(map[string]models.Recipe)
+	for _, recipe := range existing {
+		byTitle[importTitleKey(recipe.Title)] = recipe
+	}
+	return byTitle, nil
+}
+
+// storeImportedRecipe сохраняет импортированный рецепт с учётом конфликта по названию:
+// skip оставляет существующий рецепт, overwrite заменяет его, duplicate создаёт новый.
+// Возвращает ID рецепта, которому соответствует импортированный. Созданный рецепт попадает в byTitle,
+// так что одинаковые названия внутри одного файла тоже считаются конфликтом.
+func storeImportedRecipe(recipe *models.Recipe, mode string, byTitle map[string]models.Recipe, result *models.ImportResult) (int, error) {
+	key := importTitleKey(recipe.Title)
+	current, conflict := byTitle[key]
+	switch {
+	case conflict && mode == models.ImportSkip:
+		result.Skipped++
+		return current.ID, nil
+
+	case conflict && mode == models.ImportOverwrite:
+		recipe.ID = current.ID
+		// Перезаписанный рецепт остаётся в своей книге
+		recipe.CookbookID = current.CookbookID
+		if err := recipeRepo.UpdateRecipe(recipe); err != nil {
+			return 0, err
+		}
+		if err := recipeRepo.SetLabelOverrides(recipe, recipe.LabelOverrides); err != nil {
+			return 0, err
+		}
+		result.Updated++
+
+	default:
+		if err := recipeRepo.CreateRecipe(recipe); err != nil {
+			return 0, err
+		}
+		result.Created++
+		if !conflict {
+			byTitle[key] = *recipe
+		}
+	}
+
+	similarIndex.Upsert(*recipe)
+	return recipe.ID, nil
+}
+
+// resolveArchiveRecipeRef находит рецепт, на который ссылается архив. Свой рецепт берётся из IDMap;
+// чужой найдётся, только если архив восстанавливается на тот же сервер и рецепт по-прежнему доступен.
+func resolveArchiveRecipeRef(ref models.ArchiveRecipeRef, principal policy.Principal, action policy.Action, result *models.ImportResult) (int, bool) {
+	if ref.Own {
+		recipeID, ok := result.IDMap[ref.RecipeID]
+		return recipeID, ok
+	}
+
+	recipe, err := recipeRepo.GetRecipeByID(ref.RecipeID)
+	ok := err == nil && policy.Can(principal, action, recipe) && strings.EqualFold(recipe.Title, ref.Title)
+	return ref.RecipeID, ok
+}
+
+// importArchiveCollections восстанавливает коллекции. Коллекция с тем же названием не создаётся заново:
+// рецепты из архива добавляются в уже существующую.
+func importArchiveCollections(userID int, collections []models.ArchiveCollection, files map[string]*zip.File, result *models.ImportResult) error {
+	existing, err := collectionRepo.GetCollections(userID, 0)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]int)
+	for _, collection := range existing {
+		byName[importTitleKey(collection.Name)] = collection.ID
+	}
+
+	principal := userPrincipal(userID)
+	for _, archived := range collections {
+		name := strings.TrimSpace(archived.Name)
+		if name == "" || len([]rune(name)) > maxCollectionNameLength {
+			result.Warnings = append(result.Warnings, "Коллекция с неверным названием пропущена")
+			continue
+		}
+
+		collectionID, ok := byName[importTitleKey(name)]
+		if !ok {
+			collection := &models.Collection{UserID: userID, Name: name}
+			if description := strings.TrimSpace(archived.Description); len([]rune(description)) <= maxCollectionDescriptionLength {
+				collection.Description = description
+			}
+			if archived.Cover != "" {
+				data, err := readArchiveFile(files, archived.Cover, maxCollectionCoverSize)
+				if contentType := http.DetectContentType(data); err != nil || (contentType != "image/jpeg" && contentType != "image/png") {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("Обложка коллекции «%s» не восстановлена", name))
+				} else {
+					collection.CoverBase64 = base64.StdEncoding.EncodeToString(data)
+				}
+			}
+			if err := collectionRepo.CreateCollection(collection); err != nil {
+				return err
+			}
+			collectionID = collection.ID
+			byName[importTitleKey(name)] = collectionID
+			result.Collections++
+		}
+
+		recipeIDs, err := collectionRepo.GetRecipeIDs(collectionID)
+		if err != nil {
+			return err
+		}
+		contains := make(map[int]bool)
+		for _, recipeID := range recipeIDs {
+			contains[recipeID] = true
+		}
+
+		for _, ref := range archived.Recipes {
+			recipeID, ok := resolveArchiveRecipeRef(ref, principal, policy.ActionFavorite, result)
+			if !ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Рецепт «%s» из коллекции «%s» не найден", ref.Title, name))
+				continue
+			}
+			if contains[recipeID] {
+				continue
+			}
+			if err := collectionRepo.AddRecipe(collectionID, recipeID); err != nil {
+				return err
+			}
+			contains[recipeID] = true
+			recipeIDs = append(recipeIDs, recipeID)
+		}
+		// Рецепты, которые уже были в коллекции, остаются сверху, импортированные идут за ними в порядке архива
+		if err := collectionRepo.SetRecipesOrder(collectionID, recipeIDs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importArchiveReviews восстанавливает отзывы; отзыв на рецепт, у которого он уже есть, заменяется.
+func importArchiveReviews(userID int, reviews []models.ArchiveReview, result *models.ImportResult) error {
+	principal := userPrincipal(userID)
+	for _, archived := range reviews {
+		if archived.Rating < 1 || archived.Rating > 5 {
+			continue
+		}
+		recipeID, ok := resolveArchiveRecipeRef(archived.ArchiveRecipeRef, principal, policy.ActionReview, result)
+		if !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Рецепт «%s» для отзыва не найден", archived.Title))
+			continue
+		}
+
+		review := &models.Review{
+			RecipeID: recipeID,
+			UserID:   userID,
+			Rating:   archived.Rating,
+			Text:     strings.TrimSpace(archived.Text),
+		}
+		if err := reviewRepo.SaveReview(review); err != nil {
+			return err
+		}
+		result.Reviews++
+	}
+
+	return nil
+}
+
+// importArchiveHandler восстанавливает архив в аккаунт текущего пользователя. Рецепты получают
+// новые ID; конфликт по названию с уже существующим рецептом решается параметром mode.
+func importArchiveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = models.ImportSkip
+	}
+	if !models.IsValidImportMode(mode) {
+		http.Error(w, `{"error": "Режим импорта должен быть skip, overwrite или duplicate"}`, http.StatusBadRequest)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxArchiveSize))
+	if err != nil {
+		http.Error(w, `{"error": "Архив больше 100 МБ или загружен не полностью"}`, http.StatusBadRequest)
+		return
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		http.Error(w, `{"error": "Файл не является zip-архивом"}`, http.StatusBadRequest)
+		return
+	}
+
+	files := make(map[string]*zip.File)
+	for _, file := range zr.File {
+		files[file.Name] = file
+	}
+
+	var manifest models.ArchiveManifest
+	manifestData, err := readArchiveFile(files, models.ArchiveManifestFile, maxArchiveJSONSize)
+	if err != nil || json.Unmarshal(manifestData, &manifest) != nil || manifest.Format != models.ArchiveFormat {
+		http.Error(w, `{"error": "Это не архив кулинарной книги: нет manifest.json"}`, http.StatusBadRequest)
+		return
+	}
+	if manifest.Version < 1 || manifest.Version > models.ArchiveVersion {
+		http.Error(w, `{"error": "Архив создан более новой версией приложения"}`, http.StatusBadRequest)
+		return
+	}
+
+	var archiveRecipes []models.ArchiveRecipe
+	recipesData, err := readArchiveFile(files, models.ArchiveRecipesFile, maxArchiveJSONSize)
+	if err != nil || json.Unmarshal(recipesData, &archiveRecipes) != nil {
+		http.Error(w, `{"error": "Не удалось прочитать recipes.json"}`, http.StatusBadRequest)
+		return
+	}
+	if len(archiveRecipes) > maxArchiveRecipes {
+		http.Error(w, `{"error": "В архиве больше 5000 рецептов"}`, http.StatusBadRequest)
+		return
+	}
+
+	// Избранное, коллекции и отзывы необязательны: архив без этих файлов тоже восстанавливается
+	var favorites []models.ArchiveRecipeRef
+	var collections []models.ArchiveCollection
+	var reviews []models.ArchiveReview
+	for _, part := range []struct {
+		name    string
+		value   interface{}
+		message string
+	}{
+		{models.ArchiveFavoritesFile, &favorites, `{"error": "Не удалось прочитать favorites.json"}`},
+		{models.ArchiveCollectionsFile, &collections, `{"error": "Не удалось прочитать collections.json"}`},
+		{models.ArchiveReviewsFile, &reviews, `{"error": "Не удалось прочитать reviews.json"}`},
+	} {
+		if data, err := readArchiveFile(files, part.name, maxArchiveJSONSize); err == nil {
+			if err := json.Unmarshal(data, part.value); err != nil {
+				http.Error(w, part.message, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	byTitle, err := authoredRecipesByTitle(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении рецептов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	result := models.ImportResult{IDMap: map[int]int{}, Warnings: []string{}}
+
+	for _, archived := range archiveRecipes {
+		recipe, ok := archiveRecipeToModel(archived, files, &result)
+		if !ok {
+			result.Skipped++
+			continue
+		}
+		recipe.UserID = userID
+
+		recipeID, err := storeImportedRecipe(recipe, mode, byTitle, &result)
+		if err != nil {
+			http.Error(w, `{"error": "Ошибка при сохранении рецептов из архива"}`, http.StatusInternalServerError)
+			return
+		}
+		result.IDMap[archived.ID] = recipeID
+	}
+
+	principal := userPrincipal(userID)
+	for _, favorite := range favorites {
+		recipeID, ok := resolveArchiveRecipeRef(favorite, principal, policy.ActionFavorite, &result)
+		if !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Рецепт из избранного «%s» не найден", favorite.Title))
+			continue
+		}
+		if err := favoriteRepo.AddFavorite(userID, recipeID); err != nil {
+			http.Error(w, `{"error": "Ошибка при восстановлении избранного"}`, http.StatusInternalServerError)
+			return
+		}
+		result.Favorites++
+	}
+
+	if err := importArchiveCollections(userID, collections, files, &result); err != nil {
+		http.Error(w, `{"error": "Ошибка при восстановлении коллекций"}`, http.StatusInternalServerError)
+		return
+	}
+
+	if err := importArchiveReviews(userID, reviews, &result); err != nil {
+		http.Error(w, `{"error": "Ошибка при восстановлении отзывов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Импорт завершен",
+		"result":  result,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -1,7 +1,12 @@
 package auth
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -55,3 +60,47 @@ func GetUserIDFromToken(tokenString string) (int, error) {
 
 	return 0, errors.New("невалидный токен")
 }
+
+func GenerateShareToken(shareID int, expiresAt *time.Time) string {
+	var expires int64
+	if expiresAt != nil {
+		expires = expiresAt.Unix()
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", shareID, expires)))
+	return payload + "." + signSharePayload(payload)
+}
+
+func ParseShareToken(token string) (int, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return 0, errors.New("неверный формат ссылки")
+	}
+
+	if !hmac.Equal([]byte(parts[1]), []byte(signSharePayload(parts[0]))) {
+		return 0, errors.New("неверная подпись ссылки")
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return 0, errors.New("неверный формат ссылки")
+	}
+
+	var shareID int
+	var expires int64
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &shareID, &expires); err != nil {
+		return 0, errors.New("неверный формат ссылки")
+	}
+
+	if expires != 0 && time.Now().Unix() > expires {
+		return 0, errors.New("срок действия ссылки истёк")
+	}
+
+	return shareID, nil
+}
+
+func signSharePayload(payload string) string {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte("share:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+)
+
+const (
+	maxCollectionNameLength        = 100
+	maxCollectionDescriptionLength = 1000
+	maxCollectionCoverSize         = 1 << 20
+)
+
+// favoritesCollection представляет избранное как встроенную коллекцию.
+func favoritesCollection(userID, recipeID int) (models.Collection, error) {
+	collection := models.Collection{
+		ID:      models.FavoritesCollectionID,
+		UserID:  userID,
+		Name:    "Избранное",
+		Builtin: true,
+	}
+
+	count, err := favoriteRepo.CountFavorites(userID)
+	if err != nil {
+		return collection, err
+	}
+	collection.RecipeCount = count
+
+	if recipeID != 0 {
+		collection.Contains, _ = favoriteRepo.IsFavorite(userID, recipeID)
+	}
+
+	return collection, nil
+}
+
+// checkCollectionOwner проверяет, что коллекция принадлежит пользователю. Встроенная всегда своя.
+func checkCollectionOwner(w http.ResponseWriter, userID, collectionID int) bool {
+	if collectionID == models.FavoritesCollectionID {
+		return true
+	}
+
+	owner, err := collectionRepo.IsOwner(userID, collectionID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при проверке коллекции"}`, http.StatusInternalServerError)
+		return false
+	}
+	if !owner {
+		http.Error(w, `{"error": "Коллекция не найдена"}`, http.StatusNotFound)
+		return false
+	}
+
+	return true
+}
+
+// decodeCollection читает и проверяет название, описание и обложку коллекции.
+func decodeCollection(w http.ResponseWriter, r *http.Request, collection *models.Collection) bool {
+	if err := json.NewDecoder(r.Body).Decode(collection); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return false
+	}
+
+	collection.Name = strings.TrimSpace(collection.Name)
+	if collection.Name == "" || len([]rune(collection.Name)) > maxCollectionNameLength {
+		http.Error(w, `{"error": "Название коллекции должно быть от 1 до 100 символов"}`, http.StatusBadRequest)
+		return false
+	}
+
+	collection.Description = strings.TrimSpace(collection.Description)
+	if len([]rune(collection.Description)) > maxCollectionDescriptionLength {
+		http.Error(w, `{"error": "Описание коллекции не должно превышать 1000 символов"}`, http.StatusBadRequest)
+		return false
+	}
+
+	if collection.CoverBase64 != "" {
+		switch _, err := decodeImage(collection.CoverBase64, maxCollectionCoverSize); {
+		case errors.Is(err, errImageEncoding):
+			http.Error(w, `{"error": "Неверный формат обложки"}`, http.StatusBadRequest)
+			return false
+		case errors.Is(err, errImageTooLarge):
+			http.Error(w, `{"error": "Обложка больше 1 МБ"}`, http.StatusBadRequest)
+			return false
+		case err != nil:
+			http.Error(w, `{"error": "Обложка должна быть в формате JPEG или PNG"}`, http.StatusBadRequest)
+			return false
+		}
+	}
+
+	return true
+}
+
+func collectionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	recipeID, _ := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+
+	favorites, err := favoritesCollection(userID, recipeID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении избранного"}`, http.StatusInternalServerError)
+		return
+	}
+
+	collections, err := collectionRepo.GetCollections(userID, recipeID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении коллекций"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":      "ok",
+		"collections": append([]models.Collection{favorites}, collections...),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func createCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var collection models.Collection
+	if !decodeCollection(w, r, &collection) {
+		return
+	}
+	collection.UserID = userID
+
+	if err := collectionRepo.CreateCollection(&collection); err != nil {
+		http.Error(w, `{"error": "Ошибка при создании коллекции"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":     "ok",
+		"message":    "Коллекция создана",
+		"collection": collection,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func updateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var collection models.Collection
+	if !decodeCollection(w, r, &collection) {
+		return
+	}
+
+	if collection.ID == models.FavoritesCollectionID {
+		http.Error(w, `{"error": "Избранное нельзя изменить"}`, http.StatusBadRequest)
+		return
+	}
+	collection.UserID = userID
+
+	if err := collectionRepo.UpdateCollection(&collection); err != nil {
+		http.Error(w, `{"error": "Коллекция не найдена"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":     "ok",
+		"message":    "Коллекция обновлена",
+		"collection": collection,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func deleteCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	collectionID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID коллекции"}`, http.StatusBadRequest)
+		return
+	}
+
+	if collectionID == models.FavoritesCollectionID {
+		http.Error(w, `{"error": "Избранное нельзя удалить"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := collectionRepo.DeleteCollection(userID, collectionID); err != nil {
+		http.Error(w, `{"error": "Коллекция не найдена"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Коллекция удалена",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func reorderCollectionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		IDs []int `json:"ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	// Избранное всегда стоит первым, его позиция не хранится
+	var ids []int
+	for _, id := range req.IDs {
+		if id != models.FavoritesCollectionID {
+			ids = append(ids, id)
+		}
+	}
+
+	if err := collectionRepo.SetCollectionsOrder(userID, ids); err != nil {
+		http.Error(w, `{"error": "Ошибка при сохранении порядка"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Порядок коллекций сохранен",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func collectionRecipesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	collectionID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID коллекции"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !checkCollectionOwner(w, userID, collectionID) {
+		return
+	}
+
+	var recipeIDs []int
+	if collectionID == models.FavoritesCollectionID {
+		recipeIDs, err = favoriteRepo.GetFavoriteRecipes(userID)
+	} else {
+		recipeIDs, err = collectionRepo.GetRecipeIDs(collectionID)
+	}
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении рецептов коллекции"}`, http.StatusInternalServerError)
+		return
+	}
+
+	principal := userPrincipal(userID)
+
+	recipes := []models.Recipe{}
+	for _, recipeID := range recipeIDs {
+		recipe, err := recipeRepo.GetRecipeByID(recipeID)
+		if err == nil && policy.Can(principal, policy.ActionRead, recipe) {
+			recipe.IsFavorite, _ = favoriteRepo.IsFavorite(userID, recipe.ID)
+			recipes = append(recipes, *recipe)
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"count":   len(recipes),
+		"recipes": recipes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func addToCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		CollectionID int `json:"collection_id"`
+		RecipeID     int `json:"recipe_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !checkCollectionOwner(w, userID, req.CollectionID) {
+		return
+	}
+
+	if _, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionFavorite, req.RecipeID); !ok {
+		return
+	}
+
+	if req.CollectionID == models.FavoritesCollectionID {
+		err = favoriteRepo.AddFavorite(userID, req.RecipeID)
+	} else {
+		err = collectionRepo.AddRecipe(req.CollectionID, req.RecipeID)
+	}
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при добавлении в коллекцию"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Рецепт добавлен в коллекцию",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func removeFromCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	collectionID, err := strconv.Atoi(r.URL.Query().Get("collection_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID коллекции"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !checkCollectionOwner(w, userID, collectionID) {
+		return
+	}
+
+	if collectionID == models.FavoritesCollectionID {
+		err = favoriteRepo.RemoveFavorite(userID, recipeID)
+	} else {
+		err = collectionRepo.RemoveRecipe(collectionID, recipeID)
+	}
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при удалении из коллекции"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Рецепт удален из коллекции",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func orderCollectionRecipesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		CollectionID int   `json:"collection_id"`
+		RecipeIDs    []int `json:"recipe_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !checkCollectionOwner(w, userID, req.CollectionID) {
+		return
+	}
+
+	if req.CollectionID == models.FavoritesCollectionID {
+		err = favoriteRepo.SetOrder(userID, req.RecipeIDs)
+	} else {
+		err = collectionRepo.SetRecipesOrder(req.CollectionID, req.RecipeIDs)
+	}
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при сохранении порядка"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Порядок рецептов сохранен",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+)
+
+const (
+	commentsPageSize = 20
+	maxCommentLength = 2000
+)
+
+// maskHiddenComments убирает текст скрытых модератором комментариев
+// для всех, кроме владельца рецепта и автора комментария.
+func maskHiddenComments(comments []models.Comment, principal policy.Principal, canModerate bool) {
+	for i := range comments {
+		c := &comments[i]
+		if c.Hidden && !canModerate && c.UserID != principal.UserID {
+			c.Body = ""
+		}
+		maskHiddenComments(c.Replies, principal, canModerate)
+	}
+}
+
+func commentsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal := principalFromRequest(r)
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, principal, policy.ActionRead, recipeID)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	threads, total, err := commentRepo.GetThreads(recipeID, commentsPageSize, (page-1)*commentsPageSize)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении комментариев"}`, http.StatusInternalServerError)
+		return
+	}
+
+	canModerate := policy.Can(principal, policy.ActionModerate, recipe)
+	maskHiddenComments(threads, principal, canModerate)
+
+	if principal.IsAuthenticated() {
+		commentRepo.MarkRead(principal.UserID, recipeID)
+	}
+
+	response := map[string]interface{}{
+		"status":       "ok",
+		"page":         page,
+		"total":        total,
+		"has_more":     page*commentsPageSize < total,
+		"can_moderate": canModerate,
+		"comments":     threads,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func createCommentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		RecipeID int    `json:"recipe_id"`
+		ParentID *int   `json:"parent_id"`
+		Body     string `json:"body"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" || len([]rune(req.Body)) > maxCommentLength {
+		http.Error(w, `{"error": "Комментарий пустой или слишком длинный"}`, http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionComment, req.RecipeID); !ok {
+		return
+	}
+
+	if req.ParentID != nil {
+		parent, err := commentRepo.GetCommentByID(*req.ParentID)
+		if err != nil || parent.RecipeID != req.RecipeID {
+			http.Error(w, `{"error": "Комментарий для ответа не найден"}`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	comment := &models.Comment{
+		RecipeID: req.RecipeID,
+		UserID:   userID,
+		ParentID: req.ParentID,
+		Body:     req.Body,
+	}
+
+	if err := commentRepo.CreateComment(comment); err != nil {
+		http.Error(w, `{"error": "Ошибка при добавлении комментария"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Комментарий добавлен",
+		"comment": comment,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func updateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		ID   int    `json:"id"`
+		Body string `json:"body"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" || len([]rune(req.Body)) > maxCommentLength {
+		http.Error(w, `{"error": "Комментарий пустой или слишком длинный"}`, http.StatusBadRequest)
+		return
+	}
+
+	principal := userPrincipal(userID)
+
+	comment, err := commentRepo.GetCommentByID(req.ID)
+	if err != nil {
+		http.Error(w, `{"error": "Комментарий не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	if _, ok := loadRecipeForAction(w, principal, policy.ActionComment, comment.RecipeID); !ok {
+		return
+	}
+
+	if !policy.CanEditComment(principal, comment) {
+		http.Error(w, `{"error": "Нет прав на это действие"}`, http.StatusForbidden)
+		return
+	}
+
+	if err := commentRepo.UpdateComment(comment.ID, req.Body); err != nil {
+		http.Error(w, `{"error": "Ошибка при изменении комментария"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Комментарий изменён",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func deleteCommentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	commentID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID комментария"}`, http.StatusBadRequest)
+		return
+	}
+
+	principal := userPrincipal(userID)
+
+	comment, err := commentRepo.GetCommentByID(commentID)
+	if err != nil {
+		http.Error(w, `{"error": "Комментарий не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, principal, policy.ActionRead, comment.RecipeID)
+	if !ok {
+		return
+	}
+
+	if !policy.CanDeleteComment(principal, comment, recipe) {
+		http.Error(w, `{"error": "Нет прав на это действие"}`, http.StatusForbidden)
+		return
+	}
+
+	if err := commentRepo.DeleteComment(comment.ID); err != nil {
+		http.Error(w, `{"error": "Ошибка при удалении комментария"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Комментарий удалён",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func moderateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		ID     int   `json:"id"`
+		Hidden *bool `json:"hidden"`
+		Pinned *bool `json:"pinned"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	comment, err := commentRepo.GetCommentByID(req.ID)
+	if err != nil {
+		http.Error(w, `{"error": "Комментарий не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	if _, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionModerate, comment.RecipeID); !ok {
+		return
+	}
+
+	if req.Pinned != nil && *req.Pinned && comment.ParentID != nil {
+		http.Error(w, `{"error": "Закрепить можно только комментарий верхнего уровня"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := commentRepo.SetModeration(comment.ID, req.Hidden, req.Pinned); err != nil {
+		http.Error(w, `{"error": "Ошибка при модерации комментария"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Комментарий обновлён",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func unreadCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	counts, err := commentRepo.GetUnreadCounts(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при подсчёте комментариев"}`, http.StatusInternalServerError)
+		return
+	}
+
+	total := 0
+	unread := make(map[string]int, len(counts))
+	for recipeID, count := range counts {
+		unread[strconv.Itoa(recipeID)] = count
+		total += count
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"total":  total,
+		"unread": unread,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
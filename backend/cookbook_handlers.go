@@ -0,0 +1,544 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+)
+
+const maxCookbookNameLength = 100
+
+func cookbooksHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	cookbooks, err := cookbookRepo.GetUserCookbooks(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении книг"}`, http.StatusInternalServerError)
+		return
+	}
+
+	invites, err := cookbookRepo.GetPendingInvites(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении приглашений"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":    "ok",
+		"cookbooks": cookbooks,
+		"invites":   invites,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func createCookbookHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxCookbookNameLength {
+		http.Error(w, `{"error": "Название книги должно быть от 1 до 100 символов"}`, http.StatusBadRequest)
+		return
+	}
+
+	cookbook := &models.Cookbook{
+		Name:    name,
+		OwnerID: userID,
+	}
+
+	if err := cookbookRepo.CreateCookbook(cookbook); err != nil {
+		http.Error(w, `{"error": "Ошибка при создании книги"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":   "ok",
+		"message":  "Книга создана",
+		"cookbook": cookbook,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func renameCookbookHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxCookbookNameLength {
+		http.Error(w, `{"error": "Название книги должно быть от 1 до 100 символов"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !policy.CanManageCookbook(userPrincipal(userID), req.ID) {
+		http.Error(w, `{"error": "Книгой управляет только владелец"}`, http.StatusForbidden)
+		return
+	}
+
+	if err := cookbookRepo.RenameCookbook(req.ID, name); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Книга переименована",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func deleteCookbookHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	cookbookID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID книги"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !policy.CanManageCookbook(userPrincipal(userID), cookbookID) {
+		http.Error(w, `{"error": "Книгой управляет только владелец"}`, http.StatusForbidden)
+		return
+	}
+
+	recipeIDs, err := cookbookRepo.DeleteCookbook(cookbookID)
+	if err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusNotFound)
+		return
+	}
+
+	// Рецепты вернулись в личные: в индексе похожих они переходят в круг автора
+	if len(recipeIDs) > 0 {
+		recipes, err := recipeRepo.GetRecipesByIDs(recipeIDs, userID)
+		if err != nil {
+			log.Printf("Ошибка обновления индекса похожих рецептов: %v", err)
+		}
+		for _, recipe := range recipes {
+			similarIndex.Upsert(recipe)
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Книга удалена, рецепты вернулись авторам",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func cookbookMembersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	cookbookID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID книги"}`, http.StatusBadRequest)
+		return
+	}
+
+	principal := userPrincipal(userID)
+	if !policy.CanViewCookbook(principal, cookbookID) {
+		http.Error(w, `{"error": "Книга не найдена"}`, http.StatusNotFound)
+		return
+	}
+
+	members, err := cookbookRepo.GetMembers(cookbookID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении участников"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"role":    principal.CookbookRole(cookbookID),
+		"members": members,
+	}
+
+	if policy.CanManageCookbook(principal, cookbookID) {
+		invites, err := cookbookRepo.GetCookbookInvites(cookbookID)
+		if err == nil {
+			response["invites"] = invites
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func inviteToCookbookHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		CookbookID int    `json:"cookbook_id"`
+		Username   string `json:"username"`
+		Role       string `json:"role"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.Role != models.CookbookRoleEditor && req.Role != models.CookbookRoleViewer {
+		http.Error(w, `{"error": "Можно пригласить только редактора или читателя"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !policy.CanManageCookbook(userPrincipal(userID), req.CookbookID) {
+		http.Error(w, `{"error": "Приглашать участников может только владелец"}`, http.StatusForbidden)
+		return
+	}
+
+	invitee, err := userRepo.GetUserByUsername(strings.TrimSpace(req.Username))
+	if err != nil {
+		http.Error(w, `{"error": "Пользователь не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	invite := &models.CookbookInvite{
+		CookbookID:  req.CookbookID,
+		InviteeID:   invitee.ID,
+		InviteeName: invitee.Username,
+		Role:        req.Role,
+	}
+
+	if err := cookbookRepo.CreateInvite(invite, userID); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusConflict)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Приглашение отправлено",
+		"invite":  invite,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func respondInviteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		ID     int  `json:"id"`
+		Accept bool `json:"accept"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	invite, err := cookbookRepo.GetInviteByID(req.ID)
+	if err != nil || invite.InviteeID != userID {
+		http.Error(w, `{"error": "Приглашение не найдено"}`, http.StatusNotFound)
+		return
+	}
+
+	if err := cookbookRepo.RespondInvite(invite, req.Accept); err != nil {
+		http.Error(w, `{"error": "Ошибка при обработке приглашения"}`, http.StatusInternalServerError)
+		return
+	}
+
+	message := "Приглашение отклонено"
+	if req.Accept {
+		message = "Вы присоединились к книге «" + invite.CookbookName + "»"
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": message,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func cancelInviteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	cookbookID, err := strconv.Atoi(r.URL.Query().Get("cookbook_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID книги"}`, http.StatusBadRequest)
+		return
+	}
+
+	inviteID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID приглашения"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !policy.CanManageCookbook(userPrincipal(userID), cookbookID) {
+		http.Error(w, `{"error": "Приглашениями управляет только владелец"}`, http.StatusForbidden)
+		return
+	}
+
+	if err := cookbookRepo.CancelInvite(cookbookID, inviteID); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Приглашение отменено",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func setMemberRoleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		CookbookID int    `json:"cookbook_id"`
+		UserID     int    `json:"user_id"`
+		Role       string `json:"role"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.Role != models.CookbookRoleEditor && req.Role != models.CookbookRoleViewer {
+		http.Error(w, `{"error": "Неверная роль"}`, http.StatusBadRequest)
+		return
+	}
+
+	if !policy.CanManageCookbook(userPrincipal(userID), req.CookbookID) {
+		http.Error(w, `{"error": "Роли меняет только владелец"}`, http.StatusForbidden)
+		return
+	}
+
+	if err := cookbookRepo.SetMemberRole(req.CookbookID, req.UserID, req.Role); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Роль изменена",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// removeMemberHandler исключает участника (владелец) или выводит из книги самого пользователя.
+func removeMemberHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	cookbookID, err := strconv.Atoi(r.URL.Query().Get("cookbook_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID книги"}`, http.StatusBadRequest)
+		return
+	}
+
+	memberID, err := strconv.Atoi(r.URL.Query().Get("user_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID пользователя"}`, http.StatusBadRequest)
+		return
+	}
+
+	principal := userPrincipal(userID)
+	if memberID != userID && !policy.CanManageCookbook(principal, cookbookID) {
+		http.Error(w, `{"error": "Исключать участников может только владелец"}`, http.StatusForbidden)
+		return
+	}
+
+	if memberID == userID && principal.CookbookRole(cookbookID) == models.CookbookRoleOwner {
+		http.Error(w, `{"error": "Владелец не может покинуть книгу — удалите её"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := cookbookRepo.RemoveMember(cookbookID, memberID); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Участник удалён из книги",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// moveRecipeHandler переносит рецепт в книгу (cookbook_id) или в личные (cookbook_id = 0).
+func moveRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		RecipeID   int `json:"recipe_id"`
+		CookbookID int `json:"cookbook_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	principal := userPrincipal(userID)
+
+	recipe, ok := loadRecipeForAction(w, principal, policy.ActionWrite, req.RecipeID)
+	if !ok {
+		return
+	}
+
+	var target *int
+	if req.CookbookID != 0 {
+		if !policy.CanAddToCookbook(principal, req.CookbookID) {
+			http.Error(w, `{"error": "Нет прав на добавление рецептов в эту книгу"}`, http.StatusForbidden)
+			return
+		}
+		target = &req.CookbookID
+	} else if recipe.UserID != userID && !policy.Can(principal, policy.ActionShare, recipe) {
+		http.Error(w, `{"error": "Вернуть рецепт в личные может автор или владелец книги"}`, http.StatusForbidden)
+		return
+	}
+
+	if err := recipeRepo.MoveToCookbook(recipe.ID, target); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusNotFound)
+		return
+	}
+	recipe.CookbookID = target
+	similarIndex.Upsert(*recipe)
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Рецепт перенесён",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
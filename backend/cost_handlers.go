@@ -0,0 +1,521 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/ingredients"
+	"culinary-book/backend/models"
+	"culinary-book/backend/nutrition"
+	"culinary-book/backend/policy"
+)
+
+const (
+	maxPriceNameLength = 200
+	maxPriceValue      = 10000000
+	maxCostSortRecipes = 500
+)
+
+type ingredientCost struct {
+	Raw       string  `json:"raw"`
+	Name      string  `json:"name"`
+	PriceID   int     `json:"price_id,omitempty"`
+	PriceName string  `json:"price_name,omitempty"`
+	Cost      float64 `json:"cost"`
+	Priced    bool    `json:"priced"`
+	Note      string  `json:"note,omitempty"`
+}
+
+type recipeCost struct {
+	RecipeID    int              `json:"recipe_id"`
+	Title       string           `json:"title"`
+	Multiplier  float64          `json:"multiplier"`
+	Servings    int              `json:"servings"`
+	Total       float64          `json:"total"`
+	PerServing  *float64         `json:"per_serving,omitempty"`
+	Ingredients []ingredientCost `json:"ingredients"`
+	Missing     []string         `json:"missing"`
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// decodePrice принимает фасовку строкой («1 кг муки») или отдельными полями; без количества — за 1 шт.
+func decodePrice(w http.ResponseWriter, r *http.Request, price *models.IngredientPrice) bool {
+	var req struct {
+		ID       int     `json:"id"`
+		Text     string  `json:"text"`
+		Name     string  `json:"name"`
+		Quantity float64 `json:"quantity"`
+		Unit     string  `json:"unit"`
+		Price    float64 `json:"price"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return false
+	}
+
+	price.ID = req.ID
+	if strings.TrimSpace(req.Text) != "" {
+		parsed := ingredients.Parse(req.Text)
+		price.Name, price.Quantity, price.Unit = parsed.Name, parsed.Quantity, parsed.Unit
+	} else {
+		quantity, unit, ok := ingredients.Normalize(req.Quantity, req.Unit)
+		if !ok {
+			http.Error(w, `{"error": "Неизвестная единица измерения"}`, http.StatusBadRequest)
+			return false
+		}
+		price.Name, price.Quantity, price.Unit = strings.TrimSpace(req.Name), quantity, unit
+	}
+
+	if price.Name == "" || len([]rune(price.Name)) > maxPriceNameLength {
+		http.Error(w, `{"error": "Название продукта должно быть от 1 до 200 символов"}`, http.StatusBadRequest)
+		return false
+	}
+
+	if price.Unit == "" {
+		price.Unit = ingredients.UnitPiece
+	}
+	if price.Quantity == 0 {
+		price.Quantity = 1
+	}
+	if price.Quantity < 0 {
+		http.Error(w, `{"error": "Количество не может быть отрицательным"}`, http.StatusBadRequest)
+		return false
+	}
+
+	if req.Price < 0 || req.Price > maxPriceValue {
+		http.Error(w, `{"error": "Цена должна быть от 0 до 10000000"}`, http.StatusBadRequest)
+		return false
+	}
+
+	price.Price = req.Price
+	price.NameKey = ingredients.Key(price.Name)
+	return true
+}
+
+// priceCandidates подбирает цены для ингредиента: сначала точное совпадение названия, затем
+// совпадение по словам («мука» подходит к «мука пшеничная»); при равенстве — в той же единице.
+func priceCandidates(key, unit string, prices []models.IngredientPrice) []*models.IngredientPrice {
+	rank := func(price models.IngredientPrice) int {
+		score := 0
+		if price.NameKey != key {
+			score += 2
+		}
+		if price.Unit != unit {
+			score++
+		}
+		return score
+	}
+
+	var candidates []*models.IngredientPrice
+	for i := range prices {
+		if ingredients.Matches(key, prices[i].NameKey) {
+			candidates = append(candidates, &prices[i])
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return rank(*candidates[i]) < rank(*candidates[j])
+	})
+
+	return candidates
+}
+
+// convertQuantity переводит количество между г, мл и шт через плотность и вес штуки из таблицы продуктов.
+func convertQuantity(quantity float64, from, to string, food *nutrition.Food) (float64, bool) {
+	if from == to {
+		return quantity, true
+	}
+
+	var known nutrition.Food
+	if food != nil {
+		known = *food
+	}
+
+	grams, ok := nutrition.Grams(ingredients.Ingredient{Quantity: quantity, Unit: from}, known)
+	if !ok {
+		return 0, false
+	}
+
+	switch to {
+	case ingredients.UnitGram:
+		return grams, true
+	case ingredients.UnitMl:
+		density := known.Density
+		if density == 0 {
+			density = 1
+		}
+		return grams / density, true
+	case ingredients.UnitPiece:
+		if known.PieceGrams == 0 {
+			return 0, false
+		}
+		return grams / known.PieceGrams, true
+	}
+	return 0, false
+}
+
+// computeCost считает стоимость рецепта по прайс-листу. Ингредиенты без цены
+// или с единицей, которую не удалось перевести, попадают в Missing и в сумму не входят.
+// Если не посчитан ни один ингредиент, цена порции остаётся пустой, а не нулевой.
+func computeCost(recipe *models.Recipe, multiplier float64, prices []models.IngredientPrice, foods []nutrition.Food) recipeCost {
+	result := recipeCost{
+		RecipeID:    recipe.ID,
+		Title:       recipe.Title,
+		Multiplier:  multiplier,
+		Servings:    recipe.Servings,
+		Ingredients: []ingredientCost{},
+		Missing:     []string{},
+	}
+
+	costed := 0
+	for _, line := range recipe.Ingredients {
+		ingredient := ingredients.Parse(line).Scale(multiplier)
+		if ingredient.Name == "" {
+			continue
+		}
+
+		key := ingredients.Key(ingredient.Name)
+		item := ingredientCost{Raw: ingredient.Raw, Name: ingredient.Name}
+
+		candidates := priceCandidates(key, ingredient.Unit, prices)
+		if len(candidates) == 0 {
+			item.Note = "нет цены"
+			result.Missing = append(result.Missing, ingredient.Name)
+			result.Ingredients = append(result.Ingredients, item)
+			continue
+		}
+
+		if ingredient.Quantity == 0 {
+			item.PriceID, item.PriceName, item.Priced = candidates[0].ID, candidates[0].Name, true
+			item.Note = "количество не указано"
+			result.Ingredients = append(result.Ingredients, item)
+			continue
+		}
+
+		food := nutrition.Match(key, foods)
+		for _, price := range candidates {
+			quantity, ok := convertQuantity(ingredient.Quantity, ingredient.Unit, price.Unit, food)
+			if !ok || price.Quantity <= 0 {
+				continue
+			}
+			item.PriceID, item.PriceName, item.Priced = price.ID, price.Name, true
+			item.Cost = quantity / price.Quantity * price.Price
+			costed++
+			break
+		}
+
+		if !item.Priced {
+			item.Note = "цена указана в другой единице"
+			result.Missing = append(result.Missing, ingredient.Name)
+		}
+
+		result.Total += item.Cost
+		item.Cost = roundMoney(item.Cost)
+		result.Ingredients = append(result.Ingredients, item)
+	}
+
+	if recipe.Servings > 0 && costed > 0 {
+		perServing := roundMoney(result.Total / (float64(recipe.Servings) * multiplier))
+		result.PerServing = &perServing
+	}
+	result.Total = roundMoney(result.Total)
+
+	return result
+}
+
+// annotateRecipeCosts дописывает в рецепты списка стоимость порции по прайс-листу пользователя.
+func annotateRecipeCosts(userID int, recipes []models.Recipe) error {
+	if userID == 0 || len(recipes) == 0 {
+		return nil
+	}
+
+	prices, err := priceRepo.GetPrices(userID)
+	if err != nil || len(prices) == 0 {
+		return err
+	}
+
+	foods, err := foodRepo.GetFoods()
+	if err != nil {
+		return err
+	}
+
+	for i := range recipes {
+		cost := computeCost(&recipes[i], 1, prices, foods)
+		recipes[i].CostPerServing = cost.PerServing
+		recipes[i].CostMissing = len(cost.Missing)
+	}
+
+	return nil
+}
+
+// sortRecipesByCost упорядочивает рецепты от дешёвой порции к дорогой; рецепты без расчёта идут в конце.
+func sortRecipesByCost(recipes []models.Recipe) {
+	sort.SliceStable(recipes, func(i, j int) bool {
+		a, b := recipes[i].CostPerServing, recipes[j].CostPerServing
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return *a < *b
+	})
+}
+
+func pricesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	prices, err := priceRepo.GetPrices(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении цен"}`, http.StatusInternalServerError)
+		return
+	}
+	for i := range prices {
+		prices[i].Display = ingredients.FormatQuantity(prices[i].Quantity, prices[i].Unit)
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"count":  len(prices),
+		"prices": prices,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func addPriceHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var price models.IngredientPrice
+	if !decodePrice(w, r, &price) {
+		return
+	}
+	price.UserID = userID
+
+	if err := priceRepo.SetPrice(&price); err != nil {
+		http.Error(w, `{"error": "Ошибка при сохранении цены"}`, http.StatusInternalServerError)
+		return
+	}
+	price.Display = ingredients.FormatQuantity(price.Quantity, price.Unit)
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"price":  price,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func updatePriceHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var price models.IngredientPrice
+	if !decodePrice(w, r, &price) {
+		return
+	}
+	price.UserID = userID
+
+	if err := priceRepo.UpdatePrice(&price); err != nil {
+		http.Error(w, `{"error": "Цена не найдена или уже указана для этого продукта"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Цена обновлена",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func deletePriceHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	priceID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID цены"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := priceRepo.DeletePrice(userID, priceID); err != nil {
+		http.Error(w, `{"error": "Цена не найдена"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Цена удалена",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func recipeCostHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionRead, recipeID)
+	if !ok {
+		return
+	}
+
+	prices, err := priceRepo.GetPrices(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении цен"}`, http.StatusInternalServerError)
+		return
+	}
+
+	foods, err := foodRepo.GetFoods()
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении таблицы продуктов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"cost":   computeCost(recipe, 1, prices, foods),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// estimateCostHandler считает общую стоимость набора рецептов, например меню на праздник.
+func estimateCostHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Recipes []models.ShoppingRecipe `json:"recipes"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Recipes) == 0 || len(req.Recipes) > maxShoppingRecipes {
+		http.Error(w, `{"error": "Выберите от 1 до 50 рецептов"}`, http.StatusBadRequest)
+		return
+	}
+
+	prices, err := priceRepo.GetPrices(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении цен"}`, http.StatusInternalServerError)
+		return
+	}
+
+	foods, err := foodRepo.GetFoods()
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении таблицы продуктов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	principal := userPrincipal(userID)
+
+	costs := []recipeCost{}
+	missing := []string{}
+	seen := make(map[string]bool)
+	total := 0.0
+
+	for _, selected := range req.Recipes {
+		if selected.Multiplier == 0 {
+			selected.Multiplier = 1
+		}
+		if selected.Multiplier < 0 || selected.Multiplier > maxShoppingMultiplier {
+			http.Error(w, `{"error": "Множитель должен быть от 0 до 20"}`, http.StatusBadRequest)
+			return
+		}
+
+		recipe, ok := loadRecipeForAction(w, principal, policy.ActionRead, selected.RecipeID)
+		if !ok {
+			return
+		}
+
+		cost := computeCost(recipe, selected.Multiplier, prices, foods)
+		total += cost.Total
+		for _, name := range cost.Missing {
+			if key := ingredients.Key(name); !seen[key] {
+				seen[key] = true
+				missing = append(missing, name)
+			}
+		}
+		costs = append(costs, cost)
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"total":   roundMoney(total),
+		"recipes": costs,
+		"missing": missing,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
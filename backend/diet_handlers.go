@@ -0,0 +1,377 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/dietary"
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+)
+
+const (
+	maxDietProfileNameLength = 100
+	maxDietProfiles          = 20
+)
+
+// relabelRecipes применяет текущую базу знаний к уже сохранённым рецептам.
+func relabelRecipes() {
+	count, err := recipeRepo.RelabelAll()
+	if err != nil {
+		log.Printf("⚠️  Не удалось пересчитать метки рецептов: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("✅ Метки аллергенов и диет обновлены у рецептов: %d", count)
+	}
+}
+
+// splitLabels разбирает список меток через запятую и проверяет, что все они известны.
+func splitLabels(value string, valid func(string) bool) ([]string, bool) {
+	var labels []string
+	for _, label := range strings.Split(value, ",") {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			continue
+		}
+		if !valid(label) {
+			return nil, false
+		}
+		labels = append(labels, label)
+	}
+	return labels, true
+}
+
+// labelFilterFromRequest собирает фильтр «безопасно для» из параметров запроса:
+// safe_for — ID сохранённых профилей через запятую, avoid и diet — метки напрямую.
+// Условия нескольких профилей объединяются, чтобы рецепт подошёл всем сразу.
+func labelFilterFromRequest(w http.ResponseWriter, r *http.Request, userID int) (models.LabelFilter, bool) {
+	var filter models.LabelFilter
+	query := r.URL.Query()
+
+	avoid, ok := splitLabels(query.Get("avoid"), dietary.IsAllergen)
+	if !ok {
+		http.Error(w, `{"error": "Неизвестный аллерген"}`, http.StatusBadRequest)
+		return filter, false
+	}
+	require, ok := splitLabels(query.Get("diet"), dietary.IsDiet)
+	if !ok {
+		http.Error(w, `{"error": "Неизвестная диета"}`, http.StatusBadRequest)
+		return filter, false
+	}
+	filter.Avoid, filter.Require = avoid, require
+
+	safeFor := strings.TrimSpace(query.Get("safe_for"))
+	if safeFor == "" {
+		return filter, true
+	}
+	if userID == 0 {
+		http.Error(w, `{"error": "Требуется авторизация"}`, http.StatusUnauthorized)
+		return filter, false
+	}
+
+	profiles, err := dietProfileRepo.GetProfiles(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении профилей питания"}`, http.StatusInternalServerError)
+		return filter, false
+	}
+
+	for _, idStr := range strings.Split(safeFor, ",") {
+		profileID, err := strconv.Atoi(strings.TrimSpace(idStr))
+		if err != nil {
+			http.Error(w, `{"error": "Неверный ID профиля"}`, http.StatusBadRequest)
+			return filter, false
+		}
+
+		found := false
+		for _, profile := range profiles {
+			if profile.ID == profileID {
+				filter.Avoid = append(filter.Avoid, profile.Avoid...)
+				filter.Require = append(filter.Require, profile.Require...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			http.Error(w, `{"error": "Профиль питания не найден"}`, http.StatusNotFound)
+			return filter, false
+		}
+	}
+
+	return filter, true
+}
+
+func filterRecipesByLabels(recipes []models.Recipe, filter models.LabelFilter) []models.Recipe {
+	if len(filter.Avoid) == 0 && len(filter.Require) == 0 {
+		return recipes
+	}
+
+	var filtered []models.Recipe
+	for _, recipe := range recipes {
+		if dietary.Allowed(recipe.Labels, filter.Avoid, filter.Require) {
+			filtered = append(filtered, recipe)
+		}
+	}
+	return filtered
+}
+
+// decodeDietProfile читает и проверяет название и метки профиля.
+func decodeDietProfile(w http.ResponseWriter, r *http.Request, profile *models.DietProfile) bool {
+	if err := json.NewDecoder(r.Body).Decode(profile); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return false
+	}
+
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" || len([]rune(profile.Name)) > maxDietProfileNameLength {
+		http.Error(w, `{"error": "Название профиля должно быть от 1 до 100 символов"}`, http.StatusBadRequest)
+		return false
+	}
+
+	avoid, ok := splitLabels(strings.Join(profile.Avoid, ","), dietary.IsAllergen)
+	if !ok {
+		http.Error(w, `{"error": "Неизвестный аллерген"}`, http.StatusBadRequest)
+		return false
+	}
+	require, ok := splitLabels(strings.Join(profile.Require, ","), dietary.IsDiet)
+	if !ok {
+		http.Error(w, `{"error": "Неизвестная диета"}`, http.StatusBadRequest)
+		return false
+	}
+	if len(avoid) == 0 && len(require) == 0 {
+		http.Error(w, `{"error": "Выберите хотя бы один аллерген или диету"}`, http.StatusBadRequest)
+		return false
+	}
+	profile.Avoid, profile.Require = avoid, require
+
+	return true
+}
+
+func recipeLabelsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal := principalFromRequest(r)
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, principal, policy.ActionRead, recipeID)
+	if !ok {
+		return
+	}
+
+	detection := dietary.Detect(recipe.Ingredients)
+
+	overrides := recipe.LabelOverrides
+	if overrides == nil {
+		overrides = map[string]bool{}
+	}
+
+	response := map[string]interface{}{
+		"status":    "ok",
+		"labels":    recipe.Labels,
+		"detected":  detection.Labels,
+		"reasons":   detection.Reasons,
+		"overrides": overrides,
+		"can_edit":  policy.Can(principal, policy.ActionWrite, recipe),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func updateRecipeLabelsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		RecipeID  int             `json:"recipe_id"`
+		Overrides map[string]bool `json:"overrides"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	for label := range req.Overrides {
+		if !dietary.IsValidLabel(label) {
+			http.Error(w, `{"error": "Неизвестная метка"}`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	recipe, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionWrite, req.RecipeID)
+	if !ok {
+		return
+	}
+
+	if err := recipeRepo.SetLabelOverrides(recipe, req.Overrides); err != nil {
+		http.Error(w, `{"error": "Ошибка при сохранении меток"}`, http.StatusInternalServerError)
+		return
+	}
+	similarIndex.Upsert(*recipe)
+
+	response := map[string]interface{}{
+		"status":    "ok",
+		"message":   "Метки обновлены",
+		"labels":    recipe.Labels,
+		"overrides": recipe.LabelOverrides,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func dietProfilesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	profiles, err := dietProfileRepo.GetProfiles(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении профилей питания"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":    "ok",
+		"profiles":  profiles,
+		"allergens": dietary.Allergens,
+		"diets":     dietary.Diets,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func createDietProfileHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var profile models.DietProfile
+	if !decodeDietProfile(w, r, &profile) {
+		return
+	}
+	profile.UserID = userID
+
+	existing, err := dietProfileRepo.GetProfiles(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении профилей питания"}`, http.StatusInternalServerError)
+		return
+	}
+	if len(existing) >= maxDietProfiles {
+		http.Error(w, `{"error": "Можно сохранить не более 20 профилей"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := dietProfileRepo.CreateProfile(&profile); err != nil {
+		http.Error(w, `{"error": "Ошибка при создании профиля"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Профиль создан",
+		"profile": profile,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func updateDietProfileHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var profile models.DietProfile
+	if !decodeDietProfile(w, r, &profile) {
+		return
+	}
+	profile.UserID = userID
+
+	if err := dietProfileRepo.UpdateProfile(&profile); err != nil {
+		http.Error(w, `{"error": "Профиль не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Профиль обновлен",
+		"profile": profile,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func deleteDietProfileHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	profileID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID профиля"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := dietProfileRepo.DeleteProfile(userID, profileID); err != nil {
+		http.Error(w, `{"error": "Профиль не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Профиль удален",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,313 @@
+// Package dietary определяет аллергены и диеты рецепта по строкам ингредиентов.
+package dietary
+
+import (
+	"sort"
+	"strings"
+
+	"culinary-book/backend/ingredients"
+)
+
+const (
+	Nuts       = "nuts"
+	Gluten     = "gluten"
+	Lactose    = "lactose"
+	Vegetarian = "vegetarian"
+	Vegan      = "vegan"
+)
+
+// Allergens и Diets перечисляют метки в порядке показа.
+var (
+	Allergens = []string{Nuts, Gluten, Lactose}
+	Diets     = []string{Vegetarian, Vegan}
+)
+
+func IsAllergen(label string) bool {
+	return contains(Allergens, label)
+}
+
+func IsDiet(label string) bool {
+	return contains(Diets, label)
+}
+
+func IsValidLabel(label string) bool {
+	return IsAllergen(label) || IsDiet(label)
+}
+
+// rule описывает группу продуктов: prefixes сравниваются с началом слова,
+// words — со словом целиком или его основой (ingredients.Key). except перечисляет сочетания,
+// которые к группе не относятся («кокосовое молоко» не содержит лактозу).
+type rule struct {
+	prefixes []string
+	words    []string
+	except   []compound
+}
+
+// compound — прилагательное прямо перед словом, которое отменяет совпадение только этого слова:
+// «рисовая мука» без глютена, а «кукурузный хлеб» — с глютеном. Оба списка — начала слов.
+type compound struct {
+	adjectives []string
+	nouns      []string
+}
+
+var (
+	nutsRule = rule{
+		prefixes: []string{"орех", "орешк", "миндал", "фундук", "кешью", "арахис", "фисташ", "пекан",
+			"макадами", "марципан", "пралине", "нутелл", "кедров"},
+		except: []compound{
+			{adjectives: []string{"мускат"}, nouns: []string{"орех"}},
+		},
+	}
+
+	glutenRule = rule{
+		prefixes: []string{"мук", "пшени", "хлеб", "батон", "булк", "багет", "сухар", "панировоч", "макарон",
+			"спагетти", "лапш", "вермишел", "манк", "манн", "булгур", "кускус", "ячмен", "перлов", "ржан",
+			"овсян", "геркулес", "тест", "лаваш", "крекер", "бисквит", "блин", "лепешк", "тортиль", "пив",
+			"сейтан", "клецк", "пельмен", "вареник", "круассан", "фетучин", "пенне", "лазань", "печенье", "печенья"},
+		words: []string{"паст"},
+		except: []compound{
+			{adjectives: []string{"кукуруз", "рисов", "гречн", "гречк", "миндальн", "кокосов", "нутов", "картофельн",
+				"безглютен", "орехов"}, nouns: []string{"мук"}},
+			{adjectives: []string{"томатн", "кунжутн", "орехов", "миндальн", "арахисов"}, nouns: []string{"паст"}},
+			{adjectives: []string{"рисов", "безглютен"}, nouns: []string{"лапш", "вермишел", "макарон", "спагетти"}},
+		},
+	}
+
+	lactoseRule = rule{
+		prefixes: []string{"молок", "молоч", "сливк", "сливоч", "сметан", "творог", "творож", "кефир", "ряженк",
+			"йогурт", "пармезан", "моцарелл", "брынз", "рикотт", "маскарпоне", "сгущ", "пломбир",
+			"сыворотк", "чеддер", "гауд", "камамбер", "простокваш", "варенец", "сырн"},
+		words: []string{"сыр", "сыром", "сыров", "фет", "бри"},
+		except: []compound{
+			{adjectives: []string{"кокосов", "соев", "миндальн", "овсян", "рисов", "безлактоз", "растительн",
+				"арахисов", "орехов"}, nouns: []string{"молок", "сливк", "йогурт", "сыр", "сметан", "творог"}},
+		},
+	}
+
+	meatRule = rule{
+		prefixes: []string{"мяс", "говяд", "говяж", "свин", "баран", "телят", "куриц", "курин", "цыпл", "индейк",
+			"индюш", "утк", "утин", "гусь", "гусин", "кролик", "фарш", "бекон", "ветчин", "колбас", "сосиск",
+			"сардельк", "грудинк", "окорок", "салями", "пепперони", "печенк", "сердечк", "потрох", "желудк",
+			"рыб", "лосос", "семг", "форел", "тунец", "тунц", "треск", "сельд", "скумбри", "карп", "судак",
+			"минтай", "хек", "анчоус", "килек", "шпрот", "кревет", "кальмар", "миди", "краб",
+			"устриц", "осьминог", "икр", "желатин", "бульон", "хамон", "прошутто", "стейк", "антрекот",
+			"вырезк", "филе", "окорочк", "бедр", "крылышк", "голен"},
+		words: []string{"кур", "язык", "сал", "печень", "печени"},
+		except: []compound{
+			{adjectives: []string{"овощн", "грибн", "растительн", "соев", "вегетариан", "веган"},
+				nouns: []string{"бульон", "мяс", "фарш", "колбас", "сосиск", "сардельк", "бекон", "ветчин", "стейк"}},
+			{adjectives: []string{"кабачк", "баклажанн", "грибн", "овощн"}, nouns: []string{"икр"}},
+		},
+	}
+
+	animalRule = rule{
+		prefixes: []string{"яйц", "яиц", "яичн", "желтк", "желток", "майонез", "гхи", "ghee"},
+		words:    []string{"мед", "медом", "белок", "белк"},
+		except: []compound{
+			{adjectives: []string{"постн", "веган", "растительн"}, nouns: []string{"майонез", "белок", "белк"}},
+		},
+	}
+)
+
+// matches проверяет, относится ли название ингредиента к группе продуктов.
+// Слово из исключения (compound) не засчитывается, но остальные слова названия проверяются.
+func (r rule) matches(name string) bool {
+	words := strings.Fields(strings.ReplaceAll(strings.ToLower(name), "ё", "е"))
+
+	for i, word := range words {
+		if !r.matchesWord(word) {
+			continue
+		}
+		if i > 0 && r.excepted(words[i-1], word) {
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+func (r rule) matchesWord(word string) bool {
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	key := ingredients.Key(word)
+	for _, exact := range r.words {
+		if word == exact || key == exact {
+			return true
+		}
+	}
+	return false
+}
+
+func (r rule) excepted(adjective, noun string) bool {
+	for _, c := range r.except {
+		if hasAnyPrefix(noun, c.nouns) && hasAnyPrefix(adjective, c.adjectives) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(word string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Detection — результат разбора ингредиентов: найденные аллергены и продукты,
+// из-за которых рецепт не подходит для диеты, со строками-причинами.
+type Detection struct {
+	Labels  []string            `json:"labels"`
+	Reasons map[string][]string `json:"reasons"`
+}
+
+// Detect размечает рецепт по базе знаний без учёта ручных исправлений.
+// Причины для диет — ингредиенты, из-за которых рецепт им не соответствует.
+func Detect(lines []string) Detection {
+	reasons := make(map[string][]string)
+
+	for _, line := range lines {
+		name := ingredients.Parse(line).Name
+		if name == "" {
+			continue
+		}
+
+		if nutsRule.matches(name) {
+			reasons[Nuts] = append(reasons[Nuts], line)
+		}
+		if glutenRule.matches(name) {
+			reasons[Gluten] = append(reasons[Gluten], line)
+		}
+
+		lactose := lactoseRule.matches(name)
+		if lactose {
+			reasons[Lactose] = append(reasons[Lactose], line)
+		}
+
+		if meatRule.matches(name) {
+			reasons[Vegetarian] = append(reasons[Vegetarian], line)
+			reasons[Vegan] = append(reasons[Vegan], line)
+		} else if lactose || animalRule.matches(name) {
+			reasons[Vegan] = append(reasons[Vegan], line)
+		}
+	}
+
+	var labels []string
+	for _, allergen := range Allergens {
+		if len(reasons[allergen]) > 0 {
+			labels = append(labels, allergen)
+		}
+	}
+	// Рецепт без ингредиентов ничего не говорит о диете
+	if len(lines) > 0 {
+		for _, diet := range Diets {
+			if len(reasons[diet]) == 0 {
+				labels = append(labels, diet)
+			}
+		}
+	}
+
+	return Detection{Labels: normalize(labels), Reasons: reasons}
+}
+
+// Labels возвращает итоговые метки рецепта: найденные автоматически,
+// исправленные вручную (true — метка есть, false — метки нет).
+func Labels(lines []string, overrides map[string]bool) []string {
+	set := make(map[string]bool)
+	for _, label := range Detect(lines).Labels {
+		set[label] = true
+	}
+
+	for label, present := range overrides {
+		if IsValidLabel(label) {
+			set[label] = present
+		}
+	}
+
+	// Веганское блюдо всегда вегетарианское, а невегетарианское не может быть веганским
+	if set[Vegan] {
+		set[Vegetarian] = true
+	}
+	if !set[Vegetarian] {
+		set[Vegan] = false
+	}
+
+	var labels []string
+	for label, present := range set {
+		if present {
+			labels = append(labels, label)
+		}
+	}
+
+	return normalize(labels)
+}
+
+// Overrides подбирает ручные исправления, при которых Labels вернёт ровно labels:
+// так восстанавливаются метки рецепта, импортированного из файла. Неизвестные метки пропускаются.
+func Overrides(lines []string, labels []string) map[string]bool {
+	detected := Detect(lines).Labels
+
+	var overrides map[string]bool
+	for _, label := range append(append([]string{}, Allergens...), Diets...) {
+		wanted := contains(labels, label)
+		if contains(detected, label) != wanted {
+			if overrides == nil {
+				overrides = make(map[string]bool)
+			}
+			overrides[label] = wanted
+		}
+	}
+	return overrides
+}
+
+// Allowed проверяет, подходит ли рецепт с метками labels профилю:
+// в нём нет аллергенов из avoid и есть все диеты из require.
+func Allowed(labels, avoid, require []string) bool {
+	for _, label := range avoid {
+		if contains(labels, label) {
+			return false
+		}
+	}
+	for _, label := range require {
+		if !contains(labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize упорядочивает метки как в Allergens и Diets; пустой список не равен nil.
+func normalize(labels []string) []string {
+	order := append(append([]string{}, Allergens...), Diets...)
+	index := make(map[string]int)
+	for i, label := range order {
+		index[label] = i
+	}
+
+	result := []string{}
+	for _, label := range labels {
+		if _, ok := index[label]; ok && !contains(result, label) {
+			result = append(result, label)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return index[result[i]] < index[result[j]]
+	})
+
+	return result
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
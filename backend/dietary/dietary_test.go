@@ -0,0 +1,81 @@
+package dietary
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	var (
+		vegan      = []string{Vegetarian, Vegan}
+		vegetarian = []string{Vegetarian}
+	)
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		// Исключение относится только к своему сочетанию: хлеб остаётся глютеном
+		{"кукурузный хлеб", []string{Gluten, Vegetarian, Vegan}},
+		{"картофельный хлеб", []string{Gluten, Vegetarian, Vegan}},
+		{"ореховый хлеб", []string{Nuts, Gluten, Vegetarian, Vegan}},
+		{"рисовая мука 200 г", vegan},
+		{"кукурузная мука 100 г", vegan},
+		{"мука пшеничная 500 г", []string{Gluten, Vegetarian, Vegan}},
+		{"рисовая лапша", vegan},
+		{"гречневая лапша", []string{Gluten, Vegetarian, Vegan}},
+		{"томатная паста 2 ст. л.", vegan},
+		{"паста 300 г", []string{Gluten, Vegetarian, Vegan}},
+
+		{"кокосовое молоко 400 мл", vegan},
+		{"молоко 500 мл", []string{Lactose, Vegetarian}},
+		{"соевый сыр", vegan},
+		{"сыр 100 г", []string{Lactose, Vegetarian}},
+		{"кокосовый творожный сыр", []string{Lactose, Vegetarian}},
+
+		{"мускатный орех", vegan},
+		{"грецкие орехи 50 г", []string{Nuts, Vegetarian, Vegan}},
+
+		{"овощной бульон 1 л", vegan},
+		{"куриный бульон 1 л", []string{}},
+		{"кабачковая икра", vegan},
+		{"постный майонез", vegan},
+		{"майонез", vegetarian},
+	}
+
+	for _, tc := range tests {
+		if got := Detect([]string{tc.line}).Labels; !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Detect(%q) = %v, want %v", tc.line, got, tc.want)
+		}
+	}
+}
+
+func TestDetectReasons(t *testing.T) {
+	lines := []string{"рисовая мука 200 г", "кукурузный хлеб", "молоко 500 мл"}
+	detection := Detect(lines)
+
+	if want := []string{"кукурузный хлеб"}; !reflect.DeepEqual(detection.Reasons[Gluten], want) {
+		t.Errorf("gluten reasons = %v, want %v", detection.Reasons[Gluten], want)
+	}
+	if want := []string{"молоко 500 мл"}; !reflect.DeepEqual(detection.Reasons[Lactose], want) {
+		t.Errorf("lactose reasons = %v, want %v", detection.Reasons[Lactose], want)
+	}
+}
+
+func TestDetectEmpty(t *testing.T) {
+	if got := Detect(nil).Labels; len(got) != 0 {
+		t.Errorf("Detect(nil) = %v, want no labels", got)
+	}
+}
+
+func TestOverridesAndLabels(t *testing.T) {
+	lines := []string{"мука пшеничная 500 г", "молоко 500 мл"}
+
+	overrides := Overrides(lines, []string{Lactose, Vegetarian})
+	if want := map[string]bool{Gluten: false}; !reflect.DeepEqual(overrides, want) {
+		t.Errorf("Overrides = %v, want %v", overrides, want)
+	}
+	if got, want := Labels(lines, overrides), []string{Lactose, Vegetarian}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Labels = %v, want %v", got, want)
+	}
+}
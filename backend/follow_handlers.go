@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"culinary-book/backend/models"
+)
+
+const (
+	feedPageSize    = 20
+	maxFeedPageSize = 50
+)
+
+func followHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		UserID int `json:"user_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID == userID {
+		http.Error(w, `{"error": "Нельзя подписаться на себя"}`, http.StatusBadRequest)
+		return
+	}
+
+	if _, err := userRepo.GetUserByID(req.UserID); err != nil {
+		http.Error(w, `{"error": "Пользователь не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	if err := followRepo.Follow(userID, req.UserID); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusConflict)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Вы подписались",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func unfollowHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	followeeID, err := strconv.Atoi(r.URL.Query().Get("user_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID пользователя"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := followRepo.Unfollow(userID, followeeID); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Подписка отменена",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func followersHandler(w http.ResponseWriter, r *http.Request) {
+	followListHandler(w, r, followRepo.GetFollowers)
+}
+
+func followingHandler(w http.ResponseWriter, r *http.Request) {
+	followListHandler(w, r, followRepo.GetFollowing)
+}
+
+// followListHandler отдаёт подписчиков или подписки пользователя из ?user_id=,
+// по умолчанию — текущего.
+func followListHandler(w http.ResponseWriter, r *http.Request, list func(userID int) ([]models.UserSummary, error)) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	if idStr := r.URL.Query().Get("user_id"); idStr != "" {
+		userID, err = strconv.Atoi(idStr)
+		if err != nil {
+			http.Error(w, `{"error": "Неверный ID пользователя"}`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	users, err := list(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении подписок"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"count":  len(users),
+		"users":  users,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func feedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var before models.FeedCursor
+	if param := r.URL.Query().Get("before"); param != "" {
+		before, err = models.ParseFeedCursor(param)
+		if err != nil {
+			http.Error(w, `{"error": "Неверный курсор ленты"}`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > maxFeedPageSize {
+		limit = feedPageSize
+	}
+
+	items, err := followRepo.GetFeed(userID, before, limit+1)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении ленты"}`, http.StatusInternalServerError)
+		return
+	}
+
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+
+	nextCursor := ""
+	if hasMore {
+		last := items[len(items)-1]
+		nextCursor = models.FeedCursor{CreatedAt: last.CreatedAt, ID: last.ID}.String()
+	}
+
+	response := map[string]interface{}{
+		"status":      "ok",
+		"items":       items,
+		"has_more":    hasMore,
+		"next_cursor": nextCursor,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// publishToFeed не прерывает основной запрос: лента вторична по отношению к рецептам.
+func publishToFeed(actorID int, kind string, recipeID int) {
+	if err := followRepo.Publish(actorID, kind, recipeID); err != nil {
+		log.Printf("Ошибка публикации в ленту: %v", err)
+	}
+}
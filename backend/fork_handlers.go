@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+)
+
+const notificationsLimit = 50
+
+func forkRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		RecipeID int  `json:"recipe_id"`
+		Notify   bool `json:"notify"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	source, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionFork, req.RecipeID)
+	if !ok {
+		return
+	}
+
+	fork, err := recipeRepo.ForkRecipe(source, userID, req.Notify)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при копировании рецепта: `+err.Error()+`"}`, http.StatusInternalServerError)
+		return
+	}
+	similarIndex.Upsert(*fork)
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Рецепт скопирован в ваши рецепты",
+		"recipe":  fork,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func forkNotifyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		ID     int  `json:"id"`
+		Notify bool `json:"notify"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionWrite, req.ID); !ok {
+		return
+	}
+
+	if err := recipeRepo.SetForkNotify(req.ID, req.Notify); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusBadRequest)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"notify": req.Notify,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func notificationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	notifications, unread, err := notificationRepo.GetNotifications(userID, notificationsLimit)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении уведомлений"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":        "ok",
+		"unread":        unread,
+		"notifications": notifications,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func readNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		IDs []int `json:"ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := notificationRepo.MarkRead(userID, req.IDs); err != nil {
+		http.Error(w, `{"error": "Ошибка при обновлении уведомлений"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// notifyForks сообщает владельцам копий об изменении оригинала,
+// пока тот остаётся им доступен.
+func notifyForks(recipe *models.Recipe) {
+	if recipe.Visibility == models.VisibilityPrivate {
+		return
+	}
+	if err := notificationRepo.NotifyForkOwners(recipe.ID); err != nil {
+		log.Printf("Ошибка отправки уведомлений о копиях: %v", err)
+	}
+}
@@ -5,7 +5,10 @@ go 1.21
 require (
 	github.com/golang-jwt/jwt/v5 v5.3.0
 	github.com/jackc/pgx/v5 v5.5.0
+	github.com/jung-kurt/gofpdf v1.16.2
 	golang.org/x/crypto v0.14.0
+	golang.org/x/net v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
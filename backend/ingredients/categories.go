@@ -0,0 +1,82 @@
+package ingredients
+
+import (
+	"strings"
+)
+
+const CategoryOther = "Прочее"
+
+// Categories перечисляет отделы магазина в порядке обхода.
+var Categories = []string{
+	"Овощи и фрукты",
+	"Мясо и рыба",
+	"Молочные продукты и яйца",
+	"Хлеб и выпечка",
+	"Бакалея",
+	"Специи и соусы",
+	"Напитки",
+	"Замороженные продукты",
+	CategoryOther,
+}
+
+// categoryKeywords — основы слов, по которым ингредиент относится к отделу.
+var categoryKeywords = map[string][]string{
+	"Овощи и фрукты": {
+		"картоф", "морков", "лук", "чеснок", "помидор", "томат", "огур", "капуст", "перец болгар",
+		"свекл", "кабач", "баклажан", "тыкв", "зелен", "укроп", "петрушк", "кинз", "базилик", "салат",
+		"шпинат", "гриб", "шампиньон", "яблок", "груш", "банан", "лимон", "апельсин", "ягод", "клубник",
+		"малин", "вишн", "виноград", "авокадо", "имбир", "сельдер", "редис", "горох зелен",
+	},
+	"Мясо и рыба": {
+		"говяд", "свинин", "баранин", "курин", "куриц", "индейк", "фарш", "мяс", "бекон", "ветчин",
+		"колбас", "сосиск", "рыб", "лосос", "семг", "треск", "тунец", "кревет", "кальмар", "мид", "филе",
+	},
+	"Молочные продукты и яйца": {
+		"молок", "кефир", "сметан", "слив", "творог", "сыр", "йогурт", "масло сливоч", "ряженк", "яйц", "яиц",
+	},
+	"Хлеб и выпечка": {
+		"хлеб", "батон", "лаваш", "булк", "багет", "тесто", "сухар", "панировоч",
+	},
+	"Бакалея": {
+		"мук", "сахар", "рис", "греч", "макарон", "спагетти", "паст", "круп", "овсян", "манк", "пшен",
+		"фасол", "чечевиц", "нут", "горох", "масло раститель", "масло оливк", "оливков", "подсолнеч",
+		"крахмал", "разрыхл", "сод", "дрожж", "орех", "изюм", "мед", "шоколад", "какао", "консерв",
+	},
+	"Специи и соусы": {
+		"сол", "перец черн", "перец молот", "паприк", "кориандр", "кумин", "зира", "карри", "куркум",
+		"лавров", "корица", "ванил", "гвоздик", "мускат", "соус", "кетчуп", "майонез", "горчиц",
+		"уксус", "приправ", "специ",
+	},
+	"Напитки": {
+		"вод", "сок", "вино", "пиво", "чай", "кофе", "морс",
+	},
+	"Замороженные продукты": {
+		"заморож", "мороженое",
+	},
+}
+
+// Category подбирает отдел магазина по названию ингредиента.
+// Побеждает самое длинное совпадение, чтобы «масло сливочное» не попало в бакалею.
+func Category(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(name), "ё", "е")
+
+	best, bestLength := CategoryOther, 0
+	for category, keywords := range categoryKeywords {
+		for _, keyword := range keywords {
+			if len(keyword) > bestLength && strings.Contains(name, keyword) {
+				best, bestLength = category, len(keyword)
+			}
+		}
+	}
+
+	return best
+}
+
+func IsValidCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+// Package ingredients разбирает строки ингредиентов вида «200 г муки» или «Мука — 1,5 стакана»
+// на количество, единицу и название и приводит единицы к базовым (г, мл, шт).
+package ingredients
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	UnitGram  = "г"
+	UnitMl    = "мл"
+	UnitPiece = "шт"
+)
+
+// Ingredient — разобранная строка рецепта. Quantity == 0 означает «по вкусу» или неизвестное количество.
+type Ingredient struct {
+	Raw      string  `json:"raw"`
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+}
+
+type unitInfo struct {
+	base   string
+	factor float64
+}
+
+// units сопоставляет написания единиц с базовой единицей и множителем.
+// Ложки и стаканы переводятся в миллилитры, чтобы их можно было складывать с жидкостями.
+var units = map[string]unitInfo{
+	"г":         {UnitGram, 1},
+	"гр":        {UnitGram, 1},
+	"грамм":     {UnitGram, 1},
+	"грамма":    {UnitGram, 1},
+	"граммов":   {UnitGram, 1},
+	"g":         {UnitGram, 1},
+	"кг":        {UnitGram, 1000},
+	"килограмм": {UnitGram, 1000},
+	"kg":        {UnitGram, 1000},
+	"мл":        {UnitMl, 1},
+	"ml":        {UnitMl, 1},
+	"л":         {UnitMl, 1000},
+	"литр":      {UnitMl, 1000},
+	"литра":     {UnitMl, 1000},
+	"l":         {UnitMl, 1000},
+	"стакан":    {UnitMl, 250},
+	"стакана":   {UnitMl, 250},
+	"стаканов":  {UnitMl, 250},
+	"ст.л":      {UnitMl, 15},
+	"ст.ложка":  {UnitMl, 15},
+	"ст.ложки":  {UnitMl, 15},
+	"ч.л":       {UnitMl, 5},
+	"ч.ложка":   {UnitMl, 5},
+	"ч.ложки":   {UnitMl, 5},
+	"шт":        {UnitPiece, 1},
+	"штук":      {UnitPiece, 1},
+	"штуки":     {UnitPiece, 1},
+	"штука":     {UnitPiece, 1},
+	"pcs":       {UnitPiece, 1},
+	// Американские меры из импортированных рецептов
+	"tsp":         {UnitMl, 5},
+	"teaspoon":    {UnitMl, 5},
+	"teaspoons":   {UnitMl, 5},
+	"tbsp":        {UnitMl, 15},
+	"tablespoon":  {UnitMl, 15},
+	"tablespoons": {UnitMl, 15},
+	"cup":         {UnitMl, 240},
+	"cups":        {UnitMl, 240},
+	"oz":          {UnitGram, 28.35},
+	"ounce":       {UnitGram, 28.35},
+	"ounces":      {UnitGram, 28.35},
+	"lb":          {UnitGram, 453.6},
+	"lbs":         {UnitGram, 453.6},
+	"pound":       {UnitGram, 453.6},
+	"pounds":      {UnitGram, 453.6},
+}
+
+var (
+	quantityPattern = regexp.MustCompile(`(\d+\s+\d+/\d+|\d+/\d+|\d+(?:[.,]\d+)?)(?:\s*[-–]\s*(\d+(?:[.,]\d+)?))?`)
+	separators      = regexp.MustCompile(`\s*[—–:-]\s+|\s+[—–-]\s*`)
+	spaces          = regexp.MustCompile(`\s+`)
+)
+
+// Parse разбирает строку ингредиента. Для диапазона «2-3 шт» берётся верхняя граница,
+// чтобы в списке покупок хватило на рецепт.
+func Parse(line string) Ingredient {
+	ingredient := Ingredient{Raw: strings.TrimSpace(line)}
+	text := strings.ToLower(ingredient.Raw)
+	text = strings.NewReplacer("½", " 1/2", "¼", " 1/4", "¾", " 3/4", "ст. л", "ст.л", "ч. л", "ч.л").Replace(text)
+
+	loc := quantityPattern.FindStringSubmatchIndex(text)
+	if loc == nil {
+		ingredient.Name = cleanName(text)
+		return ingredient
+	}
+
+	quantityText := text[loc[2]:loc[3]]
+	if loc[4] >= 0 {
+		quantityText = text[loc[4]:loc[5]]
+	}
+	quantity := parseNumber(quantityText)
+
+	before := strings.TrimSpace(text[:loc[0]])
+	after := strings.TrimSpace(text[loc[1]:])
+
+	unit, rest := splitUnit(after)
+	info, known := units[unit]
+
+	if known {
+		ingredient.Quantity = quantity * info.factor
+		ingredient.Unit = info.base
+	} else {
+		// «2 яйца»: число без единицы считаем штуками
+		ingredient.Quantity = quantity
+		ingredient.Unit = UnitPiece
+		rest = after
+	}
+
+	name := before
+	if name == "" {
+		name = rest
+	}
+	ingredient.Name = cleanName(name)
+
+	return ingredient
+}
+
+func splitUnit(text string) (string, string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", ""
+	}
+
+	unit := strings.TrimRight(fields[0], ".,")
+	return unit, strings.Join(fields[1:], " ")
+}
+
+func parseNumber(text string) float64 {
+	text = strings.ReplaceAll(strings.TrimSpace(text), ",", ".")
+
+	total := 0.0
+	for _, part := range strings.Fields(text) {
+		if numerator, denominator, ok := strings.Cut(part, "/"); ok {
+			n, _ := strconv.ParseFloat(numerator, 64)
+			d, _ := strconv.ParseFloat(denominator, 64)
+			if d != 0 {
+				total += n / d
+			}
+			continue
+		}
+		value, _ := strconv.ParseFloat(part, 64)
+		total += value
+	}
+
+	return total
+}
+
+func cleanName(text string) string {
+	text = separators.ReplaceAllString(text, " ")
+	text = strings.TrimFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, suffix := range []string{"по вкусу", "для подачи", "по желанию"} {
+		text = strings.TrimSpace(strings.TrimSuffix(text, suffix))
+	}
+	text = strings.TrimRight(text, " ,.()")
+
+	return spaces.ReplaceAllString(text, " ")
+}
+
+// Key возвращает ключ для сравнения названий: отбрасывает окончания,
+// чтобы «мука» и «муки», «яйцо» и «яйца» совпадали.
+func Key(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	for i, word := range words {
+		runes := []rune(strings.ReplaceAll(word, "ё", "е"))
+		for len(runes) > 3 && strings.ContainsRune("аяыиоуеьйю", runes[len(runes)-1]) {
+			runes = runes[:len(runes)-1]
+		}
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// Scale умножает количество, оставляя «по вкусу» без изменений.
+func (i Ingredient) Scale(multiplier float64) Ingredient {
+	i.Quantity *= multiplier
+	return i
+}
+
+// FormatQuantity показывает количество в удобных единицах: 1500 г → «1.5 кг».
+func FormatQuantity(quantity float64, unit string) string {
+	if quantity == 0 {
+		return ""
+	}
+
+	switch {
+	case unit == UnitGram && quantity >= 1000:
+		quantity, unit = quantity/1000, "кг"
+	case unit == UnitMl && quantity >= 1000:
+		quantity, unit = quantity/1000, "л"
+	}
+
+	value := strconv.FormatFloat(math.Round(quantity*100)/100, 'f', -1, 64)
+	if unit == "" {
+		return value
+	}
+	return value + " " + unit
+}
+
+// Normalize переводит количество в базовую единицу. Пустая единица допустима для «по вкусу».
+func Normalize(quantity float64, unit string) (float64, string, bool) {
+	unit = strings.TrimRight(strings.ToLower(strings.TrimSpace(unit)), ".")
+	if unit == "" {
+		return quantity, "", true
+	}
+
+	info, ok := units[unit]
+	if !ok {
+		return 0, "", false
+	}
+	return quantity * info.factor, info.base, true
+}
+
+// Matches сравнивает ключи названий: «картофел» подходит к «картофел молод»,
+// но «сол» не подходит к «фасол», потому что совпадать должны целые слова.
+func Matches(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+
+	shorter, longer := strings.Fields(a), strings.Fields(b)
+	if len(shorter) > len(longer) {
+		shorter, longer = longer, shorter
+	}
+
+	words := make(map[string]bool)
+	for _, word := range longer {
+		words[word] = true
+	}
+	for _, word := range shorter {
+		if !words[word] {
+			return false
+		}
+	}
+	return true
+}
@@ -3,17 +3,21 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"culinary-book/backend/auth"
 	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
 	"culinary-book/backend/repository"
+	"culinary-book/backend/similarity"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -22,6 +26,20 @@ var db *pgx.Conn
 var userRepo *repository.UserRepository
 var recipeRepo *repository.RecipeRepository
 var favoriteRepo *repository.FavoriteRepository
+var shareRepo *repository.ShareRepository
+var reviewRepo *repository.ReviewRepository
+var commentRepo *repository.CommentRepository
+var followRepo *repository.FollowRepository
+var notificationRepo *repository.NotificationRepository
+var cookbookRepo *repository.CookbookRepository
+var collectionRepo *repository.CollectionRepository
+var mealPlanRepo *repository.MealPlanRepository
+var shoppingRepo *repository.ShoppingRepository
+var pantryRepo *repository.PantryRepository
+var foodRepo *repository.FoodRepository
+var dietProfileRepo *repository.DietProfileRepository
+var priceRepo *repository.PriceRepository
+var similarIndex = similarity.NewIndex(similarNeighborsKept)
 
 func initDB() error {
 	connStr := fmt.Sprintf(
@@ -49,6 +67,19 @@ func initDB() error {
 	userRepo = repository.NewUserRepository(db)
 	recipeRepo = repository.NewRecipeRepository(db)
 	favoriteRepo = repository.NewFavoriteRepository(db)
+	shareRepo = repository.NewShareRepository(db)
+	reviewRepo = repository.NewReviewRepository(db)
+	commentRepo = repository.NewCommentRepository(db)
+	followRepo = repository.NewFollowRepository(db)
+	notificationRepo = repository.NewNotificationRepository(db)
+	cookbookRepo = repository.NewCookbookRepository(db)
+	collectionRepo = repository.NewCollectionRepository(db)
+	mealPlanRepo = repository.NewMealPlanRepository(db)
+	shoppingRepo = repository.NewShoppingRepository(db)
+	pantryRepo = repository.NewPantryRepository(db)
+	foodRepo = repository.NewFoodRepository(db)
+	dietProfileRepo = repository.NewDietProfileRepository(db)
+	priceRepo = repository.NewPriceRepository(db)
 
 	log.Println("✅ Подключение к PostgreSQL установлено")
 	return nil
@@ -57,21 +88,16 @@ func initDB() error {
 func createTables() error {
 	ctx := context.Background()
 
-	_, err := db.Exec(ctx, `
-		CREATE TABLE IF NOT EXISTS users (
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS users (
 			id SERIAL PRIMARY KEY,
 			username VARCHAR(50) UNIQUE NOT NULL,
 			password_hash VARCHAR(255) NOT NULL,
 			email VARCHAR(100),
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)
-	`)
-	if err != nil {
-		return err
-	}
+		)`,
 
-	_, err = db.Exec(ctx, `
-		CREATE TABLE IF NOT EXISTS recipes (
+		`CREATE TABLE IF NOT EXISTS recipes (
 			id SERIAL PRIMARY KEY,
 			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
 			title VARCHAR(200) NOT NULL,
@@ -82,17 +108,282 @@ func createTables() error {
 			difficulty VARCHAR(20),
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)
-	`)
-	if err != nil {
-		return err
-	}
+		)`,
 
-	_, err = db.Exec(ctx, `
-		CREATE INDEX IF NOT EXISTS idx_recipes_user_id ON recipes(user_id)
-	`)
-	if err != nil {
-		return err
+		`CREATE INDEX IF NOT EXISTS idx_recipes_user_id ON recipes(user_id)`,
+
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS image_base64 TEXT`,
+
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS visibility VARCHAR(20) NOT NULL DEFAULT 'private'`,
+
+		`CREATE INDEX IF NOT EXISTS idx_recipes_public ON recipes(created_at DESC) WHERE visibility = 'public'`,
+
+		`CREATE TABLE IF NOT EXISTS favorites (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, recipe_id)
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_favorites_user_id ON favorites(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_favorites_recipe_id ON favorites(recipe_id)`,
+
+		`CREATE TABLE IF NOT EXISTS recipe_shares (
+			id SERIAL PRIMARY KEY,
+			recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMP,
+			revoked_at TIMESTAMP
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_recipe_shares_recipe_id ON recipe_shares(recipe_id)`,
+
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS rating_sum INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0`,
+
+		`CREATE TABLE IF NOT EXISTS recipe_reviews (
+			id SERIAL PRIMARY KEY,
+			recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+			text TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(recipe_id, user_id)
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_recipe_reviews_recipe_id ON recipe_reviews(recipe_id)`,
+
+		`CREATE TABLE IF NOT EXISTS recipe_comments (
+			id SERIAL PRIMARY KEY,
+			recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			parent_id INTEGER REFERENCES recipe_comments(id) ON DELETE CASCADE,
+			body TEXT NOT NULL,
+			hidden BOOLEAN NOT NULL DEFAULT FALSE,
+			pinned BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_recipe_comments_recipe_id ON recipe_comments(recipe_id, parent_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_recipe_comments_parent_id ON recipe_comments(parent_id)`,
+
+		`CREATE TABLE IF NOT EXISTS comment_reads (
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+			last_read_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (user_id, recipe_id)
+		)`,
+
+		`CREATE TABLE IF NOT EXISTS follows (
+			follower_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			followee_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (follower_id, followee_id),
+			CHECK (follower_id <> followee_id)
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_follows_followee_id ON follows(followee_id)`,
+
+		`CREATE TABLE IF NOT EXISTS feed_items (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			actor_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			kind VARCHAR(20) NOT NULL,
+			recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_feed_items_user ON feed_items(user_id, created_at DESC, id DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_feed_items_actor ON feed_items(actor_id, recipe_id)`,
+
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS forked_from_id INTEGER REFERENCES recipes(id) ON DELETE SET NULL`,
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS forked_from_title VARCHAR(200)`,
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS forked_from_author VARCHAR(50)`,
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS fork_count INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS fork_notify BOOLEAN NOT NULL DEFAULT FALSE`,
+
+		`CREATE INDEX IF NOT EXISTS idx_recipes_forked_from_id ON recipes(forked_from_id)`,
+
+		`CREATE TABLE IF NOT EXISTS notifications (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			kind VARCHAR(30) NOT NULL,
+			recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+			source_recipe_id INTEGER REFERENCES recipes(id) ON DELETE SET NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			read_at TIMESTAMP
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, created_at DESC)`,
+
+		`CREATE TABLE IF NOT EXISTS cookbooks (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			owner_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE TABLE IF NOT EXISTS cookbook_members (
+			cookbook_id INTEGER REFERENCES cookbooks(id) ON DELETE CASCADE,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			role VARCHAR(20) NOT NULL,
+			joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (cookbook_id, user_id)
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_cookbook_members_user_id ON cookbook_members(user_id)`,
+
+		`CREATE TABLE IF NOT EXISTS cookbook_invites (
+			id SERIAL PRIMARY KEY,
+			cookbook_id INTEGER REFERENCES cookbooks(id) ON DELETE CASCADE,
+			inviter_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			invitee_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			role VARCHAR(20) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(cookbook_id, invitee_id)
+		)`,
+
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS cookbook_id INTEGER REFERENCES cookbooks(id) ON DELETE SET NULL`,
+
+		`CREATE INDEX IF NOT EXISTS idx_recipes_cookbook_id ON recipes(cookbook_id)`,
+
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name VARCHAR(100)`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_base64 TEXT`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS bio TEXT`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP`,
+
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(LOWER(email)) WHERE email <> ''`,
+
+		`ALTER TABLE favorites ADD COLUMN IF NOT EXISTS position INTEGER`,
+
+		`CREATE TABLE IF NOT EXISTS collections (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			description TEXT,
+			cover_base64 TEXT,
+			position INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_collections_user_id ON collections(user_id)`,
+
+		`CREATE TABLE IF NOT EXISTS collection_recipes (
+			collection_id INTEGER REFERENCES collections(id) ON DELETE CASCADE,
+			recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+			position INTEGER,
+			added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (collection_id, recipe_id)
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_collection_recipes_recipe_id ON collection_recipes(recipe_id)`,
+
+		`CREATE TABLE IF NOT EXISTS meal_plan_entries (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			plan_date DATE NOT NULL,
+			slot VARCHAR(20) NOT NULL,
+			recipe_id INTEGER REFERENCES recipes(id) ON DELETE CASCADE,
+			servings INTEGER NOT NULL DEFAULT 0,
+			note VARCHAR(200),
+			position INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_meal_plan_user_date ON meal_plan_entries(user_id, plan_date)`,
+
+		`CREATE TABLE IF NOT EXISTS shopping_items (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(200) NOT NULL,
+			name_key VARCHAR(200) NOT NULL,
+			quantity DOUBLE PRECISION NOT NULL DEFAULT 0,
+			unit VARCHAR(10) NOT NULL DEFAULT '',
+			category VARCHAR(50) NOT NULL,
+			checked BOOLEAN NOT NULL DEFAULT FALSE,
+			manual BOOLEAN NOT NULL DEFAULT FALSE,
+			sources TEXT,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_shopping_items_user_id ON shopping_items(user_id, name_key)`,
+
+		`CREATE TABLE IF NOT EXISTS pantry_items (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(200) NOT NULL,
+			name_key VARCHAR(200) NOT NULL,
+			quantity DOUBLE PRECISION NOT NULL DEFAULT 0,
+			unit VARCHAR(10) NOT NULL DEFAULT '',
+			location VARCHAR(20) NOT NULL DEFAULT 'fridge',
+			expires_at DATE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_pantry_items_user_id ON pantry_items(user_id, expires_at)`,
+
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS servings INTEGER NOT NULL DEFAULT 0`,
+
+		`CREATE TABLE IF NOT EXISTS foods (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(200) NOT NULL UNIQUE,
+			aliases TEXT,
+			kcal DOUBLE PRECISION NOT NULL DEFAULT 0,
+			protein DOUBLE PRECISION NOT NULL DEFAULT 0,
+			fat DOUBLE PRECISION NOT NULL DEFAULT 0,
+			carbs DOUBLE PRECISION NOT NULL DEFAULT 0,
+			fiber DOUBLE PRECISION NOT NULL DEFAULT 0,
+			piece_grams DOUBLE PRECISION NOT NULL DEFAULT 0,
+			density DOUBLE PRECISION NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE TABLE IF NOT EXISTS food_overrides (
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			ingredient VARCHAR(200) NOT NULL,
+			name_key VARCHAR(200) NOT NULL,
+			food_id INTEGER REFERENCES foods(id) ON DELETE CASCADE,
+			grams DOUBLE PRECISION NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, name_key)
+		)`,
+
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS label_overrides JSONB NOT NULL DEFAULT '{}'`,
+
+		`CREATE TABLE IF NOT EXISTS diet_profiles (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			avoid_labels TEXT[] NOT NULL DEFAULT '{}',
+			require_labels TEXT[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE TABLE IF NOT EXISTS ingredient_prices (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(200) NOT NULL,
+			name_key VARCHAR(200) NOT NULL,
+			price DOUBLE PRECISION NOT NULL DEFAULT 0,
+			quantity DOUBLE PRECISION NOT NULL DEFAULT 1,
+			unit VARCHAR(10) NOT NULL DEFAULT 'шт',
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(user_id, name_key, unit)
+		)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(ctx, query); err != nil {
+			return err
+		}
 	}
 
 	log.Println("✅ Таблицы созданы/проверены")
@@ -124,6 +415,49 @@ func authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+func getUserIDFromRequest(r *http.Request) (int, error) {
+	parts := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return 0, errors.New("токен не указан")
+	}
+
+	return auth.GetUserIDFromToken(parts[1])
+}
+
+func principalFromRequest(r *http.Request) policy.Principal {
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		return policy.Anonymous()
+	}
+	return userPrincipal(userID)
+}
+
+// userPrincipal дополняет пользователя его ролями в общих книгах.
+func userPrincipal(userID int) policy.Principal {
+	roles, err := cookbookRepo.GetUserRoles(userID)
+	if err != nil {
+		log.Printf("Ошибка получения ролей в книгах: %v", err)
+	}
+	return policy.User(userID).WithCookbookRoles(roles)
+}
+
+// loadRecipeForAction загружает рецепт и проверяет права через policy.
+// Если рецепт нельзя даже прочитать, отвечает 404, чтобы не раскрывать его существование.
+func loadRecipeForAction(w http.ResponseWriter, principal policy.Principal, action policy.Action, recipeID int) (*models.Recipe, bool) {
+	recipe, err := recipeRepo.GetRecipeByID(recipeID)
+	if err != nil || !policy.Can(principal, policy.ActionRead, recipe) {
+		http.Error(w, `{"error": "Рецепт не найден"}`, http.StatusNotFound)
+		return nil, false
+	}
+
+	if !policy.Can(principal, action, recipe) {
+		http.Error(w, `{"error": "Нет прав на это действие"}`, http.StatusForbidden)
+		return nil, false
+	}
+
+	return recipe, true
+}
+
 func registerHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
@@ -225,7 +559,7 @@ func loginHandler(w http.ResponseWriter, r *http.Request) {
 		Status:  "ok",
 		Message: "Вход выполнен успешно",
 		Token:   token,
-		User:    user,
+		User:    withDeletionSchedule(user),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -233,29 +567,108 @@ func loginHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func recipesHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Лента доступна и без входа; с токеном из неё исключаются свои рецепты.
+	principal := principalFromRequest(r)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	search := strings.TrimSpace(r.URL.Query().Get("q"))
+	sortBy := r.URL.Query().Get("sort")
+
+	filter, ok := labelFilterFromRequest(w, r, principal.UserID)
+	if !ok {
+		return
+	}
+
+	// Стоимость зависит от цен читателя, поэтому по ней сортируются последние рецепты уже в памяти
+	offset, fetchLimit := (page-1)*limit, limit
+	sortByCost := sortBy == models.SortCost && principal.IsAuthenticated()
+	if sortByCost {
+		offset, fetchLimit = 0, maxCostSortRecipes
+	}
+
+	recipes, total, err := recipeRepo.GetPublicRecipes(principal.UserID, search, sortBy, filter, fetchLimit, offset)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении рецептов"}`, http.StatusInternalServerError)
+		return
+	}
+	recipes = policy.FilterReadable(principal, recipes)
+
+	if err := annotateRecipeCosts(principal.UserID, recipes); err != nil {
+		http.Error(w, `{"error": "Ошибка при расчете стоимости"}`, http.StatusInternalServerError)
+		return
+	}
+	if sortByCost {
+		// Страницы дальше первых maxCostSortRecipes рецептов не отдаются, поэтому и total не больше
+		if total > maxCostSortRecipes {
+			total = maxCostSortRecipes
+		}
+		sortRecipesByCost(recipes)
+		start, end := (page-1)*limit, page*limit
+		if start > len(recipes) {
+			start = len(recipes)
+		}
+		if end > len(recipes) {
+			end = len(recipes)
+		}
+		recipes = recipes[start:end]
+	}
 
 	response := map[string]interface{}{
 		"status":  "ok",
-		"message": "API рецептов работает. Используйте /api/my-recipes для получения своих рецептов",
-		"data": []map[string]interface{}{
-			{
-				"id":          1,
-				"title":       "Борщ",
-				"description": "Традиционный суп",
-				"difficulty":  "medium",
-				"time":        90,
-			},
-			{
-				"id":          2,
-				"title":       "Оливье",
-				"description": "Салат на Новый год",
-				"difficulty":  "easy",
-				"time":        60,
-			},
-		},
+		"page":    page,
+		"limit":   limit,
+		"total":   total,
+		"count":   len(recipes),
+		"recipes": recipes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func getRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal := principalFromRequest(r)
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, principal, policy.ActionRead, recipeID)
+	if !ok {
+		return
+	}
+
+	if principal.IsAuthenticated() {
+		recipe.IsFavorite, _ = favoriteRepo.IsFavorite(principal.UserID, recipe.ID)
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"recipe": recipe,
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -275,11 +688,37 @@ func myRecipesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	recipes, err := recipeRepo.GetRecipesByUserID(userID)
+	sortBy := r.URL.Query().Get("sort")
+
+	filter, ok := labelFilterFromRequest(w, r, userID)
+	if !ok {
+		return
+	}
+
+	var recipes []models.Recipe
+	if cookbookIDStr := r.URL.Query().Get("cookbook_id"); cookbookIDStr != "" {
+		cookbookID, convErr := strconv.Atoi(cookbookIDStr)
+		if convErr != nil || !policy.CanViewCookbook(userPrincipal(userID), cookbookID) {
+			http.Error(w, `{"error": "Книга не найдена"}`, http.StatusNotFound)
+			return
+		}
+		recipes, err = recipeRepo.GetRecipesByCookbook(cookbookID, userID, sortBy)
+	} else {
+		recipes, err = recipeRepo.GetRecipesByUserID(userID, sortBy)
+	}
 	if err != nil {
 		http.Error(w, `{"error": "Ошибка при получении рецептов"}`, http.StatusInternalServerError)
 		return
 	}
+	recipes = filterRecipesByLabels(recipes, filter)
+
+	if err := annotateRecipeCosts(userID, recipes); err != nil {
+		http.Error(w, `{"error": "Ошибка при расчете стоимости"}`, http.StatusInternalServerError)
+		return
+	}
+	if sortBy == models.SortCost {
+		sortRecipesByCost(recipes)
+	}
 
 	response := map[string]interface{}{
 		"status":  "ok",
@@ -337,8 +776,11 @@ func createRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		Ingredients  []string `json:"ingredients"`
 		Instructions string   `json:"instructions"`
 		CookingTime  int      `json:"cooking_time"`
+		Servings     int      `json:"servings"`
 		Difficulty   string   `json:"difficulty"`
 		ImageBase64  string   `json:"image_base64"`
+		Visibility   string   `json:"visibility"`
+		CookbookID   int      `json:"cookbook_id"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&recipeReq); err != nil {
@@ -346,6 +788,28 @@ func createRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var cookbookID *int
+	if recipeReq.CookbookID != 0 {
+		if !policy.CanAddToCookbook(userPrincipal(userID), recipeReq.CookbookID) {
+			http.Error(w, `{"error": "Нет прав на добавление рецептов в эту книгу"}`, http.StatusForbidden)
+			return
+		}
+		cookbookID = &recipeReq.CookbookID
+	}
+
+	if recipeReq.Visibility == "" {
+		recipeReq.Visibility = models.VisibilityPrivate
+	}
+	if !models.IsValidVisibility(recipeReq.Visibility) {
+		http.Error(w, `{"error": "Неверный уровень видимости"}`, http.StatusBadRequest)
+		return
+	}
+
+	if recipeReq.Servings < 0 || recipeReq.Servings > maxRecipeServings {
+		http.Error(w, `{"error": "Количество порций должно быть от 1 до 100"}`, http.StatusBadRequest)
+		return
+	}
+
 	recipe := &models.Recipe{
 		UserID:       userID,
 		Title:        recipeReq.Title,
@@ -353,14 +817,22 @@ func createRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		Ingredients:  recipeReq.Ingredients,
 		Instructions: recipeReq.Instructions,
 		CookingTime:  recipeReq.CookingTime,
+		Servings:     recipeReq.Servings,
 		Difficulty:   recipeReq.Difficulty,
 		ImageBase64:  recipeReq.ImageBase64,
+		Visibility:   recipeReq.Visibility,
+		CookbookID:   cookbookID,
 	}
 
 	if err := recipeRepo.CreateRecipe(recipe); err != nil {
 		http.Error(w, `{"error": "Ошибка при создании рецепта: `+err.Error()+`"}`, http.StatusInternalServerError)
 		return
 	}
+	similarIndex.Upsert(*recipe)
+
+	if recipe.Visibility == models.VisibilityPublic {
+		publishToFeed(userID, models.FeedRecipeCreated, recipe.ID)
+	}
 
 	response := map[string]interface{}{
 		"status":  "ok",
@@ -398,8 +870,10 @@ func updateRecipeHandler(w http.ResponseWriter, r *http.Request) {
         Ingredients  []string `json:"ingredients"`
         Instructions string   `json:"instructions"`
         CookingTime  int      `json:"cooking_time"`
+        Servings     *int     `json:"servings"`
         Difficulty   string   `json:"difficulty"`
         ImageBase64  string   `json:"image_base64"`
+        Visibility   string   `json:"visibility"`
     }
 
     if err := json.NewDecoder(r.Body).Decode(&recipeReq); err != nil {
@@ -412,26 +886,66 @@ func updateRecipeHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    if recipeReq.Visibility != "" && !models.IsValidVisibility(recipeReq.Visibility) {
+        http.Error(w, `{"error": "Неверный уровень видимости"}`, http.StatusBadRequest)
+        return
+    }
+
+    existing, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionWrite, recipeReq.ID)
+    if !ok {
+        return
+    }
+    // Клиенты без поля видимости не должны снимать рецепт с публикации
+    if recipeReq.Visibility == "" {
+        recipeReq.Visibility = existing.Visibility
+    }
+
+    // Клиенты без поля порций не должны сбрасывать уже указанное значение; 0 убирает порции
+    servings := existing.Servings
+    if recipeReq.Servings != nil {
+        servings = *recipeReq.Servings
+    }
+    if servings < 0 || servings > maxRecipeServings {
+        http.Error(w, `{"error": "Количество порций должно быть от 1 до 100"}`, http.StatusBadRequest)
+        return
+    }
+
     recipe := &models.Recipe{
-        ID:           recipeReq.ID,
-        UserID:       userID,
-        Title:        recipeReq.Title,
-        Description:  recipeReq.Description,
-        Ingredients:  recipeReq.Ingredients,
-        Instructions: recipeReq.Instructions,
-        CookingTime:  recipeReq.CookingTime,
-        Difficulty:   recipeReq.Difficulty,
-        ImageBase64:  recipeReq.ImageBase64,
+        ID:             existing.ID,
+        UserID:         existing.UserID,
+        Title:          recipeReq.Title,
+        Description:    recipeReq.Description,
+        Ingredients:    recipeReq.Ingredients,
+        Instructions:   recipeReq.Instructions,
+        CookingTime:    recipeReq.CookingTime,
+        Servings:       servings,
+        Difficulty:     recipeReq.Difficulty,
+        ImageBase64:    recipeReq.ImageBase64,
+        Visibility:     recipeReq.Visibility,
+        CookbookID:     existing.CookbookID,
+        LabelOverrides: existing.LabelOverrides,
     }
 
     if err := recipeRepo.UpdateRecipe(recipe); err != nil {
         if strings.Contains(err.Error(), "no rows") {
-            http.Error(w, `{"error": "Рецепт не найден или нет прав на редактирование"}`, http.StatusNotFound)
+            http.Error(w, `{"error": "Рецепт не найден"}`, http.StatusNotFound)
             return
         }
         http.Error(w, `{"error": "Ошибка при обновлении рецепта: `+err.Error()+`"}`, http.StatusInternalServerError)
         return
     }
+    similarIndex.Upsert(*recipe)
+
+    notifyForks(recipe)
+
+    // В ленте рецепт обновляет его автор, даже если правку сделал редактор общей книги
+    if recipe.Visibility == models.VisibilityPublic {
+        if existing.Visibility == models.VisibilityPublic {
+            publishToFeed(existing.UserID, models.FeedRecipeUpdated, recipe.ID)
+        } else {
+            publishToFeed(existing.UserID, models.FeedRecipeCreated, recipe.ID)
+        }
+    }
 
     response := map[string]interface{}{
         "status":  "ok",
@@ -474,10 +988,15 @@ func deleteRecipeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := recipeRepo.DeleteRecipe(recipeID, userID); err != nil {
+	if _, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionDelete, recipeID); !ok {
+		return
+	}
+
+	if err := recipeRepo.DeleteRecipe(recipeID); err != nil {
 		http.Error(w, `{"error": "Ошибка при удалении рецепта: `+err.Error()+`"}`, http.StatusInternalServerError)
 		return
 	}
+	similarIndex.Remove(recipeID)
 
 	response := map[string]interface{}{
 		"status":  "ok",
@@ -502,21 +1021,44 @@ func favoritesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter, ok := labelFilterFromRequest(w, r, userID)
+	if !ok {
+		return
+	}
+
 	favoriteIDs, err := favoriteRepo.GetFavoriteRecipes(userID)
 	if err != nil {
 		http.Error(w, `{"error": "Ошибка при получении избранного"}`, http.StatusInternalServerError)
 		return
 	}
 
+	principal := userPrincipal(userID)
+
 	var favoriteRecipes []models.Recipe
 	for _, recipeID := range favoriteIDs {
 		recipe, err := recipeRepo.GetRecipeByID(recipeID)
-		if err == nil {
+		if err == nil && policy.Can(principal, policy.ActionRead, recipe) {
 			recipe.IsFavorite = true
 			favoriteRecipes = append(favoriteRecipes, *recipe)
 		}
 	}
 
+	favoriteRecipes = filterRecipesByLabels(favoriteRecipes, filter)
+
+	if err := annotateRecipeCosts(userID, favoriteRecipes); err != nil {
+		http.Error(w, `{"error": "Ошибка при расчете стоимости"}`, http.StatusInternalServerError)
+		return
+	}
+
+	switch r.URL.Query().Get("sort") {
+	case models.SortRating:
+		sort.SliceStable(favoriteRecipes, func(i, j int) bool {
+			return favoriteRecipes[i].RatingAvg > favoriteRecipes[j].RatingAvg
+		})
+	case models.SortCost:
+		sortRecipesByCost(favoriteRecipes)
+	}
+
 	response := map[string]interface{}{
 		"status":   "ok",
 		"count":    len(favoriteRecipes),
@@ -550,9 +1092,7 @@ func addFavoriteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = recipeRepo.GetRecipeByID(req.RecipeID)
-	if err != nil {
-		http.Error(w, `{"error": "Рецепт не найден"}`, http.StatusNotFound)
+	if _, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionFavorite, req.RecipeID); !ok {
 		return
 	}
 
@@ -624,12 +1164,18 @@ func main() {
 		if err := createTables(); err != nil {
 			log.Printf("⚠️  Не удалось создать таблицы: %v", err)
 		}
+
+		importFoods()
+		relabelRecipes()
+		buildSimilarIndex()
+		go purgeDeletedAccountsLoop()
 	}
 
 	http.HandleFunc("/api/health", healthHandler)
 	http.HandleFunc("/api/register", registerHandler)
 	http.HandleFunc("/api/login", loginHandler)
 	http.HandleFunc("/api/recipes", recipesHandler)
+	http.HandleFunc("/api/recipe", getRecipeHandler)
 	http.HandleFunc("/api/my-recipes", authMiddleware(myRecipesHandler))
 	http.HandleFunc("/api/create-recipe", authMiddleware(createRecipeHandler))
 	http.HandleFunc("/api/update-recipe", authMiddleware(updateRecipeHandler))
@@ -637,6 +1183,97 @@ func main() {
 	http.HandleFunc("/api/favorites", authMiddleware(favoritesHandler))
 	http.HandleFunc("/api/favorites/add", authMiddleware(addFavoriteHandler))
 	http.HandleFunc("/api/favorites/remove", authMiddleware(removeFavoriteHandler))
+	http.HandleFunc("/api/shares", authMiddleware(sharesHandler))
+	http.HandleFunc("/api/shares/create", authMiddleware(createShareHandler))
+	http.HandleFunc("/api/shares/revoke", authMiddleware(revokeShareHandler))
+	http.HandleFunc("/share/", sharedRecipeHandler)
+	http.HandleFunc("/api/reviews", reviewsHandler)
+	http.HandleFunc("/api/reviews/save", authMiddleware(saveReviewHandler))
+	http.HandleFunc("/api/reviews/delete", authMiddleware(deleteReviewHandler))
+	http.HandleFunc("/api/comments", commentsHandler)
+	http.HandleFunc("/api/comments/create", authMiddleware(createCommentHandler))
+	http.HandleFunc("/api/comments/update", authMiddleware(updateCommentHandler))
+	http.HandleFunc("/api/comments/delete", authMiddleware(deleteCommentHandler))
+	http.HandleFunc("/api/comments/moderate", authMiddleware(moderateCommentHandler))
+	http.HandleFunc("/api/comments/unread", authMiddleware(unreadCommentsHandler))
+	http.HandleFunc("/api/follow", authMiddleware(followHandler))
+	http.HandleFunc("/api/unfollow", authMiddleware(unfollowHandler))
+	http.HandleFunc("/api/followers", authMiddleware(followersHandler))
+	http.HandleFunc("/api/following", authMiddleware(followingHandler))
+	http.HandleFunc("/api/feed", authMiddleware(feedHandler))
+	http.HandleFunc("/api/recipes/fork", authMiddleware(forkRecipeHandler))
+	http.HandleFunc("/api/recipes/fork-notify", authMiddleware(forkNotifyHandler))
+	http.HandleFunc("/api/notifications", authMiddleware(notificationsHandler))
+	http.HandleFunc("/api/notifications/read", authMiddleware(readNotificationsHandler))
+	http.HandleFunc("/api/cookbooks", authMiddleware(cookbooksHandler))
+	http.HandleFunc("/api/cookbooks/create", authMiddleware(createCookbookHandler))
+	http.HandleFunc("/api/cookbooks/rename", authMiddleware(renameCookbookHandler))
+	http.HandleFunc("/api/cookbooks/delete", authMiddleware(deleteCookbookHandler))
+	http.HandleFunc("/api/cookbooks/members", authMiddleware(cookbookMembersHandler))
+	http.HandleFunc("/api/cookbooks/members/role", authMiddleware(setMemberRoleHandler))
+	http.HandleFunc("/api/cookbooks/members/remove", authMiddleware(removeMemberHandler))
+	http.HandleFunc("/api/cookbooks/invite", authMiddleware(inviteToCookbookHandler))
+	http.HandleFunc("/api/cookbooks/invites/respond", authMiddleware(respondInviteHandler))
+	http.HandleFunc("/api/cookbooks/invites/cancel", authMiddleware(cancelInviteHandler))
+	http.HandleFunc("/api/cookbooks/move", authMiddleware(moveRecipeHandler))
+	http.HandleFunc("/api/me", authMiddleware(meHandler))
+	http.HandleFunc("/api/me/password", authMiddleware(changePasswordHandler))
+	http.HandleFunc("/api/me/delete", authMiddleware(deleteAccountHandler))
+	http.HandleFunc("/api/me/delete/cancel", authMiddleware(cancelDeletionHandler))
+	http.HandleFunc("/api/collections", authMiddleware(collectionsHandler))
+	http.HandleFunc("/api/collections/create", authMiddleware(createCollectionHandler))
+	http.HandleFunc("/api/collections/update", authMiddleware(updateCollectionHandler))
+	http.HandleFunc("/api/collections/delete", authMiddleware(deleteCollectionHandler))
+	http.HandleFunc("/api/collections/reorder", authMiddleware(reorderCollectionsHandler))
+	http.HandleFunc("/api/collections/recipes", authMiddleware(collectionRecipesHandler))
+	http.HandleFunc("/api/collections/add", authMiddleware(addToCollectionHandler))
+	http.HandleFunc("/api/collections/remove", authMiddleware(removeFromCollectionHandler))
+	http.HandleFunc("/api/collections/order", authMiddleware(orderCollectionRecipesHandler))
+	http.HandleFunc("/api/meal-plan", authMiddleware(mealPlanHandler))
+	http.HandleFunc("/api/meal-plan/add", authMiddleware(addMealPlanEntryHandler))
+	http.HandleFunc("/api/meal-plan/update", authMiddleware(updateMealPlanEntryHandler))
+	http.HandleFunc("/api/meal-plan/delete", authMiddleware(deleteMealPlanEntryHandler))
+	http.HandleFunc("/api/meal-plan/copy-week", authMiddleware(copyMealPlanWeekHandler))
+	http.HandleFunc("/api/meal-plan/clear-week", authMiddleware(clearMealPlanWeekHandler))
+	http.HandleFunc("/api/shopping-list", authMiddleware(shoppingListHandler))
+	http.HandleFunc("/api/shopping-list/generate", authMiddleware(generateShoppingListHandler))
+	http.HandleFunc("/api/shopping-list/add", authMiddleware(addShoppingItemHandler))
+	http.HandleFunc("/api/shopping-list/update", authMiddleware(updateShoppingItemHandler))
+	http.HandleFunc("/api/shopping-list/check", authMiddleware(checkShoppingItemHandler))
+	http.HandleFunc("/api/shopping-list/delete", authMiddleware(deleteShoppingItemHandler))
+	http.HandleFunc("/api/shopping-list/clear", authMiddleware(clearShoppingListHandler))
+	http.HandleFunc("/api/pantry", authMiddleware(pantryHandler))
+	http.HandleFunc("/api/pantry/add", authMiddleware(addPantryItemHandler))
+	http.HandleFunc("/api/pantry/update", authMiddleware(updatePantryItemHandler))
+	http.HandleFunc("/api/pantry/delete", authMiddleware(deletePantryItemHandler))
+	http.HandleFunc("/api/pantry/cooked", authMiddleware(cookedHandler))
+	http.HandleFunc("/api/pantry/use-soon", authMiddleware(useSoonHandler))
+	http.HandleFunc("/api/nutrition", recipeNutritionHandler)
+	http.HandleFunc("/api/nutrition/foods", foodsHandler)
+	http.HandleFunc("/api/nutrition/overrides", authMiddleware(setFoodOverrideHandler))
+	http.HandleFunc("/api/nutrition/overrides/delete", authMiddleware(deleteFoodOverrideHandler))
+	http.HandleFunc("/api/recipe-labels", recipeLabelsHandler)
+	http.HandleFunc("/api/recipe-labels/update", authMiddleware(updateRecipeLabelsHandler))
+	http.HandleFunc("/api/diet-profiles", authMiddleware(dietProfilesHandler))
+	http.HandleFunc("/api/diet-profiles/create", authMiddleware(createDietProfileHandler))
+	http.HandleFunc("/api/diet-profiles/update", authMiddleware(updateDietProfileHandler))
+	http.HandleFunc("/api/diet-profiles/delete", authMiddleware(deleteDietProfileHandler))
+	http.HandleFunc("/api/prices", authMiddleware(pricesHandler))
+	http.HandleFunc("/api/prices/add", authMiddleware(addPriceHandler))
+	http.HandleFunc("/api/prices/update", authMiddleware(updatePriceHandler))
+	http.HandleFunc("/api/prices/delete", authMiddleware(deletePriceHandler))
+	http.HandleFunc("/api/costs", authMiddleware(recipeCostHandler))
+	http.HandleFunc("/api/costs/estimate", authMiddleware(estimateCostHandler))
+	http.HandleFunc("/api/similar-recipes", similarRecipesHandler)
+	http.HandleFunc("/api/export", authMiddleware(exportArchiveHandler))
+	http.HandleFunc("/api/import", authMiddleware(importArchiveHandler))
+	http.HandleFunc("/api/schema-org", schemaOrgRecipeHandler)
+	http.HandleFunc("/api/schema-org/parse", authMiddleware(schemaOrgParseHandler))
+	http.HandleFunc("/api/markdown/export", authMiddleware(exportMarkdownHandler))
+	http.HandleFunc("/api/markdown/import", authMiddleware(importMarkdownHandler))
+	http.HandleFunc("/api/pdf-book", authMiddleware(pdfBookHandler))
+	http.HandleFunc("/api/app-import/preview", authMiddleware(appImportPreviewHandler))
+	http.HandleFunc("/api/app-import", authMiddleware(appImportHandler))
 
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -651,8 +1288,10 @@ func main() {
 			"endpoints": []string{
 				"POST /api/register",
 				"POST /api/login",
-				"GET  /api/recipes",
+				"GET  /api/recipes?page=&limit=&q= (публичная лента)",
+				"GET  /api/recipe?id=",
 				"GET  /api/my-recipes (требует Bearer token)",
+				"GET  /share/{token} (рецепт по ссылке, ?format=json)",
 			},
 		})
 	})
@@ -0,0 +1,399 @@
+// Package markdown записывает рецепт в Markdown с YAML front matter и читает такой файл обратно.
+//
+//	---
+//	title: Борщ
+//	time: 90
+//	difficulty: средняя
+//	servings: 6
+//	tags: [gluten]
+//	image: Борщ.jpg
+//	---
+//
+//	# Борщ
+//
+//	![](Борщ.jpg)
+//
+//	Описание.
+//
+//	## Ингредиенты
+//
+//	- 500 г свёклы
+//
+//	## Приготовление
+//
+//	1. Сварить бульон.
+//
+// Описание и инструкция переносятся строка в строку, поэтому экспорт и импорт не теряют форматирование.
+// Строки, которые начинаются с #, экранируются обратной косой чертой, чтобы не стать заголовками;
+// так же экранируются строки-картинки и ингредиенты, похожие на пункты списка задач («[x] …»).
+// Картинкой рецепта считается только картинка сразу после заголовка.
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"culinary-book/backend/dietary"
+	"culinary-book/backend/models"
+	"culinary-book/backend/schemaorg"
+)
+
+const (
+	Extension = ".md"
+
+	IngredientsHeading = "Ингредиенты"
+	StepsHeading       = "Приготовление"
+)
+
+var (
+	ErrNoTitle = errors.New("у рецепта нет названия")
+
+	// Заголовки разделов, которые встречаются в заметках, написанных вручную
+	ingredientHeadings = []string{"ингредиенты", "ingredients", "продукты"}
+	stepHeadings       = []string{"приготовление", "шаги", "инструкция", "инструкции", "способ приготовления",
+		"steps", "instructions", "directions", "method", "preparation"}
+
+	imagePattern    = regexp.MustCompile(`^!\[[^\]]*\]\((?:<([^>]+)>|(\S+))(?:\s+"[^"]*")?\)\s*$`)
+	listItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?(.*)$`)
+	taskPattern     = regexp.MustCompile(`^\\*\[[ xX]\]\s`)
+	durationPattern = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*([a-zа-яё]*)`)
+)
+
+// Minutes — время приготовления. В файле его можно записать числом минут
+// или строкой: «1 ч 30 мин», «90 min», «PT1H30M».
+type Minutes int
+
+func (m *Minutes) UnmarshalYAML(node *yaml.Node) error {
+	var n int
+	if err := node.Decode(&n); err == nil {
+		*m = Minutes(n)
+		return nil
+	}
+
+	var s string
+	if err := node.Decode(&s); err != nil {
+		return err
+	}
+	minutes, ok := ParseMinutes(s)
+	if !ok {
+		return fmt.Errorf("неверное время приготовления %q", s)
+	}
+	*m = Minutes(minutes)
+	return nil
+}
+
+// ParseMinutes читает время, записанное словами: «1 ч 30 мин», «1 hr 30 mins», «45», «PT1H30M».
+func ParseMinutes(s string) (int, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if minutes := schemaorg.ParseDuration(s); minutes > 0 {
+		return minutes, true
+	}
+
+	matches := durationPattern.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+
+	var minutes float64
+	for _, match := range matches {
+		n, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+		if err != nil {
+			return 0, false
+		}
+		switch unit := match[2]; {
+		case strings.HasPrefix(unit, "ч"), strings.HasPrefix(unit, "h"):
+			minutes += n * 60
+		case strings.HasPrefix(unit, "д"), strings.HasPrefix(unit, "d"):
+			minutes += n * 24 * 60
+		default:
+			minutes += n
+		}
+	}
+	return int(minutes + 0.5), true
+}
+
+// FrontMatter — поля YAML в начале файла. Tags — метки аллергенов и диет;
+// пустой список означает «меток нет», а отсутствие поля — «определить по ингредиентам».
+// Теги другого приложения меток не задают: если среди них есть незнакомые, метки определяются по ингредиентам.
+type FrontMatter struct {
+	Title      string    `yaml:"title"`
+	Time       Minutes   `yaml:"time,omitempty"`
+	Difficulty string    `yaml:"difficulty,omitempty"`
+	Servings   int       `yaml:"servings,omitempty"`
+	Tags       []string  `yaml:"tags"`
+	Visibility string    `yaml:"visibility,omitempty"`
+	Image      string    `yaml:"image,omitempty"`
+	Author     string    `yaml:"author,omitempty"`
+	Created    time.Time `yaml:"created,omitempty"`
+	Updated    time.Time `yaml:"updated,omitempty"`
+}
+
+// Document — рецепт, прочитанный из Markdown. Image — путь к картинке относительно файла рецепта;
+// UnknownTags — теги, которые не являются метками приложения и при импорте не сохраняются.
+type Document struct {
+	Recipe      models.Recipe
+	Image       string
+	UnknownTags []string
+}
+
+// Render записывает рецепт в Markdown; image — имя файла картинки рядом с рецептом или пустая строка.
+func Render(recipe models.Recipe, image string) ([]byte, error) {
+	front := FrontMatter{
+		Title:      recipe.Title,
+		Time:       Minutes(recipe.CookingTime),
+		Difficulty: recipe.Difficulty,
+		Servings:   recipe.Servings,
+		Tags:       append([]string{}, recipe.Labels...),
+		Visibility: recipe.Visibility,
+		Image:      image,
+		Author:     recipe.AuthorName,
+		Created:    recipe.CreatedAt,
+		Updated:    recipe.UpdatedAt,
+	}
+
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(front); err != nil {
+		return nil, err
+	}
+	encoder.Close()
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s\n\n", escape(recipe.Title))
+	if image != "" {
+		fmt.Fprintf(&b, "![](%s)\n\n", linkDestination(image))
+	}
+	if description := strings.TrimSpace(recipe.Description); description != "" {
+		b.WriteString(escape(description) + "\n\n")
+	}
+
+	b.WriteString("## " + IngredientsHeading + "\n\n")
+	for _, line := range recipe.Ingredients {
+		b.WriteString("- " + escapeIngredient(strings.TrimSpace(line)) + "\n")
+	}
+
+	b.WriteString("\n## " + StepsHeading + "\n\n")
+	if instructions := strings.TrimSpace(recipe.Instructions); instructions != "" {
+		b.WriteString(escape(instructions) + "\n")
+	}
+
+	return b.Bytes(), nil
+}
+
+// Parse читает рецепт из Markdown. Файл без front matter тоже читается:
+// название берётся из заголовка первого уровня.
+func Parse(data []byte) (*Document, error) {
+	text := strings.ReplaceAll(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))), "\r\n", "\n")
+
+	var front FrontMatter
+	if rest, yamlText, ok := splitFrontMatter(text); ok {
+		if err := yaml.Unmarshal([]byte(yamlText), &front); err != nil {
+			return nil, fmt.Errorf("ошибка в front matter: %v", err)
+		}
+		text = rest
+	}
+
+	const (
+		sectionDescription = iota
+		sectionIngredients
+		sectionSteps
+	)
+
+	var heading string
+	var description, ingredients, steps []string
+	image := front.Image
+	section := sectionDescription
+	started := false
+	// Картинка рецепта — только первая строка после заголовка; дальше картинки остаются в описании
+	imageAllowed := true
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "# ") && !started {
+			heading = unescape(strings.TrimSpace(trimmed[2:]))
+			started = true
+			continue
+		}
+		if trimmed != "" {
+			started = true
+		}
+
+		if strings.HasPrefix(trimmed, "## ") {
+			name := strings.ToLower(strings.TrimRight(strings.TrimSpace(trimmed[3:]), ":"))
+			switch {
+			case containsString(ingredientHeadings, name):
+				section = sectionIngredients
+				continue
+			case containsString(stepHeadings, name):
+				section = sectionSteps
+				continue
+			}
+			// Прочие разделы («Заметки», «Источник») сохраняются в описании вместе с заголовком
+			section = sectionDescription
+		}
+
+		switch section {
+		case sectionIngredients:
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			if match := listItemPattern.FindStringSubmatch(line); match != nil {
+				trimmed = strings.TrimSpace(match[1])
+			}
+			if trimmed != "" {
+				ingredients = append(ingredients, unescapeIngredient(trimmed))
+			}
+		case sectionSteps:
+			steps = append(steps, unescape(line))
+		default:
+			if trimmed != "" && imageAllowed {
+				imageAllowed = false
+				if match := imagePattern.FindStringSubmatch(trimmed); match != nil {
+					target := match[1] + match[2]
+					if image == "" {
+						image = target
+						continue
+					}
+					if target == image {
+						continue
+					}
+				}
+			}
+			description = append(description, unescape(line))
+		}
+	}
+
+	title := strings.TrimSpace(front.Title)
+	if title == "" {
+		title = heading
+	}
+	if title == "" {
+		return nil, ErrNoTitle
+	}
+
+	doc := &Document{
+		Recipe: models.Recipe{
+			Title:        title,
+			Description:  strings.TrimSpace(strings.Join(description, "\n")),
+			Ingredients:  ingredients,
+			Instructions: strings.TrimSpace(strings.Join(steps, "\n")),
+			CookingTime:  int(front.Time),
+			Servings:     front.Servings,
+			Difficulty:   front.Difficulty,
+			Visibility:   front.Visibility,
+			AuthorName:   front.Author,
+			CreatedAt:    front.Created,
+			UpdatedAt:    front.Updated,
+		},
+		Image: image,
+	}
+
+	if front.Tags != nil {
+		var labels []string
+		for _, tag := range front.Tags {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if dietary.IsValidLabel(tag) {
+				labels = append(labels, tag)
+			} else if tag != "" {
+				doc.UnknownTags = append(doc.UnknownTags, tag)
+			}
+		}
+		// Полным набором меток теги считаются, только если все они — метки приложения, как в его же выгрузке
+		if len(doc.UnknownTags) == 0 {
+			doc.Recipe.LabelOverrides = dietary.Overrides(ingredients, labels)
+		}
+	}
+
+	return doc, nil
+}
+
+// splitFrontMatter отделяет YAML между строками «---» в начале файла.
+func splitFrontMatter(text string) (rest, front string, ok bool) {
+	if !strings.HasPrefix(text, "---\n") {
+		return text, "", false
+	}
+
+	body := text[len("---\n"):]
+	for _, marker := range []string{"---", "..."} {
+		if strings.HasPrefix(body, marker+"\n") || body == marker {
+			return strings.TrimPrefix(body, marker), "", true
+		}
+		if end := strings.Index(body, "\n"+marker+"\n"); end >= 0 {
+			return body[end+len(marker)+2:], body[:end], true
+		}
+		if strings.HasSuffix(body, "\n"+marker) {
+			return "", strings.TrimSuffix(body, "\n"+marker), true
+		}
+	}
+	return text, "", false
+}
+
+// escape защищает строки, которые начинаются с # (или с уже экранированного \#), от разбора как заголовков,
+// а строки-картинки (в том числе уже экранированные) — от разбора как картинки рецепта.
+func escape(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, `\#`) || isImageLine(trimmed) {
+			lines[i] = line[:len(line)-len(trimmed)] + `\` + trimmed
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func unescape(line string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	if strings.HasPrefix(trimmed, `\#`) || strings.HasPrefix(trimmed, `\\#`) ||
+		strings.HasPrefix(trimmed, `\`) && isImageLine(trimmed) {
+		return line[:len(line)-len(trimmed)] + trimmed[1:]
+	}
+	return line
+}
+
+// isImageLine — строка-картинка, возможно с обратными косыми чертами в начале.
+func isImageLine(line string) bool {
+	return imagePattern.MatchString(strings.TrimLeft(line, `\`))
+}
+
+// escapeIngredient экранирует ингредиент как строку текста и дополнительно — начало «[x] »,
+// которое иначе было бы прочитано как отметка пункта списка задач.
+func escapeIngredient(line string) string {
+	if taskPattern.MatchString(line) {
+		return `\` + line
+	}
+	return escape(line)
+}
+
+func unescapeIngredient(line string) string {
+	if strings.HasPrefix(line, `\`) && taskPattern.MatchString(line) {
+		return line[1:]
+	}
+	return unescape(line)
+}
+
+// linkDestination заключает путь с пробелами в угловые скобки, как требует CommonMark.
+func linkDestination(path string) string {
+	if strings.ContainsAny(path, " ()") {
+		return "<" + path + ">"
+	}
+	return path
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+
+	"culinary-book/backend/dietary"
+	"culinary-book/backend/models"
+)
+
+func TestRenderParseRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe models.Recipe
+		image  string
+	}{
+		{
+			name: "plain recipe",
+			recipe: models.Recipe{
+				Title:        "Борщ",
+				Description:  "Классический борщ.\n\nПодавать со сметаной.",
+				Ingredients:  []string{"500 г свёклы", "1 кг говядины"},
+				Instructions: "1. Сварить бульон.\n2. Добавить свёклу.",
+				CookingTime:  90,
+				Servings:     6,
+				Difficulty:   "средняя",
+				Visibility:   models.VisibilityPrivate,
+			},
+			image: "Борщ.jpg",
+		},
+		{
+			name: "image line in description without recipe image",
+			recipe: models.Recipe{
+				Title:        "Блины",
+				Description:  "![](x.jpg)\nТак они выглядят.",
+				Ingredients:  []string{"мука 200 г"},
+				Instructions: "Жарить.",
+			},
+		},
+		{
+			name: "image line in description with recipe image",
+			recipe: models.Recipe{
+				Title:        "Оладьи",
+				Description:  "Текст.\n![](Оладьи.jpg)\n\\![](escaped.jpg)",
+				Ingredients:  []string{"кефир 500 мл"},
+				Instructions: "Жарить.",
+			},
+			image: "Оладьи.jpg",
+		},
+		{
+			name: "task markers and headings in ingredients",
+			recipe: models.Recipe{
+				Title:        "Салат",
+				Ingredients:  []string{"[x] огурцы", "[ ] помидоры", `\[x] уже экранировано`, "#1 соль", `\#2 перец`, "1. укроп"},
+				Instructions: "# не заголовок\n\\# и это тоже",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		data, err := Render(tc.recipe, tc.image)
+		if err != nil {
+			t.Fatalf("%s: Render: %v", tc.name, err)
+		}
+		doc, err := Parse(data)
+		if err != nil {
+			t.Fatalf("%s: Parse: %v", tc.name, err)
+		}
+
+		got := doc.Recipe
+		if got.Title != tc.recipe.Title {
+			t.Errorf("%s: title %q, want %q", tc.name, got.Title, tc.recipe.Title)
+		}
+		if got.Description != tc.recipe.Description {
+			t.Errorf("%s: description %q, want %q", tc.name, got.Description, tc.recipe.Description)
+		}
+		if !reflect.DeepEqual(got.Ingredients, tc.recipe.Ingredients) {
+			t.Errorf("%s: ingredients %q, want %q", tc.name, got.Ingredients, tc.recipe.Ingredients)
+		}
+		if got.Instructions != tc.recipe.Instructions {
+			t.Errorf("%s: instructions %q, want %q", tc.name, got.Instructions, tc.recipe.Instructions)
+		}
+		if got.CookingTime != tc.recipe.CookingTime || got.Servings != tc.recipe.Servings ||
+			got.Difficulty != tc.recipe.Difficulty || got.Visibility != tc.recipe.Visibility {
+			t.Errorf("%s: front matter %d/%d/%q/%q, want %d/%d/%q/%q", tc.name,
+				got.CookingTime, got.Servings, got.Difficulty, got.Visibility,
+				tc.recipe.CookingTime, tc.recipe.Servings, tc.recipe.Difficulty, tc.recipe.Visibility)
+		}
+		if doc.Image != tc.image {
+			t.Errorf("%s: image %q, want %q", tc.name, doc.Image, tc.image)
+		}
+	}
+}
+
+func TestParseHandwritten(t *testing.T) {
+	data := []byte("# Омлет\n\n![](omelet.png)\n\nБыстрый завтрак.\n\n![](step.png)\n\n" +
+		"## Ingredients\n\n- [x] 3 яйца\n* [ ] молоко\n\n## Steps\n\nВзбить и пожарить.\n")
+
+	doc, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if doc.Recipe.Title != "Омлет" {
+		t.Errorf("title %q", doc.Recipe.Title)
+	}
+	if doc.Image != "omelet.png" {
+		t.Errorf("image %q, want omelet.png", doc.Image)
+	}
+	if want := "Быстрый завтрак.\n\n![](step.png)"; doc.Recipe.Description != want {
+		t.Errorf("description %q, want %q", doc.Recipe.Description, want)
+	}
+	if want := []string{"3 яйца", "молоко"}; !reflect.DeepEqual(doc.Recipe.Ingredients, want) {
+		t.Errorf("ingredients %q, want %q", doc.Recipe.Ingredients, want)
+	}
+	if doc.Recipe.Instructions != "Взбить и пожарить." {
+		t.Errorf("instructions %q", doc.Recipe.Instructions)
+	}
+}
+
+func TestParseMinutes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"45", 45, true},
+		{"1 ч 30 мин", 90, true},
+		{"1 hr 30 mins", 90, true},
+		{"PT1H30M", 90, true},
+		{"1,5 ч", 90, true},
+		{"долго", 0, false},
+	}
+
+	for _, tc := range tests {
+		got, ok := ParseMinutes(tc.in)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ParseMinutes(%q) = %d, %v; want %d, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	body := "\n# Пирог\n\n## Ингредиенты\n\n- грецкие орехи 100 г\n- мука 300 г\n- молоко 200 мл\n"
+
+	tests := []struct {
+		name      string
+		tags      string
+		overrides map[string]bool
+		unknown   []string
+	}{
+		{"no tags", "", nil, nil},
+		{"app labels", "tags: [nuts, gluten, vegetarian]\n", map[string]bool{dietary.Lactose: false}, nil},
+		{"empty list", "tags: []\n", map[string]bool{dietary.Nuts: false, dietary.Gluten: false, dietary.Lactose: false, dietary.Vegetarian: false}, nil},
+		{"foreign tags", "tags: [ужин]\n", nil, []string{"ужин"}},
+		{"mixed tags", "tags: [ужин, nuts]\n", nil, []string{"ужин"}},
+	}
+
+	for _, tc := range tests {
+		doc, err := Parse([]byte("---\ntitle: Пирог\n" + tc.tags + "---\n" + body))
+		if err != nil {
+			t.Fatalf("%s: Parse: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(doc.Recipe.LabelOverrides, tc.overrides) {
+			t.Errorf("%s: overrides %v, want %v", tc.name, doc.Recipe.LabelOverrides, tc.overrides)
+		}
+		if !reflect.DeepEqual(doc.UnknownTags, tc.unknown) {
+			t.Errorf("%s: unknown tags %v, want %v", tc.name, doc.UnknownTags, tc.unknown)
+		}
+	}
+}
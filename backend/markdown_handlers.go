@@ -0,0 +1,302 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"culinary-book/backend/markdown"
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+)
+
+const (
+	maxMarkdownFileName = 100
+	markdownPersonalDir = "Мои рецепты"
+)
+
+// markdownFileName превращает название в имя файла без символов, запрещённых в Windows и Linux;
+// повторяющиеся имена в одной папке получают номер: «Борщ (2)».
+func markdownFileName(title string, used map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 32 {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(title))
+
+	name = strings.Trim(name, ". ")
+	if runes := []rune(name); len(runes) > maxMarkdownFileName {
+		name = string(runes[:maxMarkdownFileName])
+	}
+	if name == "" {
+		name = "recipe"
+	}
+
+	unique := name
+	for i := 2; used[strings.ToLower(unique)]; i++ {
+		unique = fmt.Sprintf("%s (%d)", name, i)
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
+
+// writeMarkdownRecipe добавляет в zip файл рецепта и картинку рядом с ним.
+func writeMarkdownRecipe(zw *zip.Writer, dir string, recipe models.Recipe, used map[string]bool) error {
+	name := markdownFileName(recipe.Title, used)
+
+	image := ""
+	if data, err := base64.StdEncoding.DecodeString(recipe.ImageBase64); err == nil && len(data) > 0 {
+		image = name + imageExtension(data)
+		file, err := zw.Create(path.Join(dir, image))
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(data); err != nil {
+			return err
+		}
+	}
+
+	content, err := markdown.Render(recipe, image)
+	if err != nil {
+		return err
+	}
+	file, err := zw.Create(path.Join(dir, name+markdown.Extension))
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	return err
+}
+
+// exportMarkdownHandler выгружает рецепты в Markdown zip-архивом. С recipe_id в архиве один рецепт
+// и его картинка; без него — все рецепты автора, разложенные по папкам книг.
+func exportMarkdownHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var buffer bytes.Buffer
+	zw := zip.NewWriter(&buffer)
+	fileName := fmt.Sprintf("culinary-book-markdown-%s.zip", time.Now().Format(models.DateLayout))
+
+	if idParam := r.URL.Query().Get("recipe_id"); idParam != "" {
+		recipeID, err := strconv.Atoi(idParam)
+		if err != nil {
+			http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+			return
+		}
+
+		recipe, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionRead, recipeID)
+		if !ok {
+			return
+		}
+
+		if err := writeMarkdownRecipe(zw, "", *recipe, map[string]bool{}); err != nil {
+			http.Error(w, `{"error": "Ошибка при создании архива"}`, http.StatusInternalServerError)
+			return
+		}
+		fileName = fmt.Sprintf("recipe-%d-markdown.zip", recipe.ID)
+	} else {
+		recipes, err := recipeRepo.GetAuthoredRecipes(userID)
+		if err != nil {
+			http.Error(w, `{"error": "Ошибка при выгрузке рецептов"}`, http.StatusInternalServerError)
+			return
+		}
+
+		cookbooks, err := cookbookRepo.GetUserCookbooks(userID)
+		if err != nil {
+			http.Error(w, `{"error": "Ошибка при получении книг"}`, http.StatusInternalServerError)
+			return
+		}
+
+		// Папки книг: имена тоже очищаются и не повторяются
+		usedDirs := map[string]bool{strings.ToLower(markdownPersonalDir): true}
+		dirs := make(map[int]string)
+		for _, cookbook := range cookbooks {
+			dirs[cookbook.ID] = markdownFileName(cookbook.Name, usedDirs)
+		}
+
+		root := strings.TrimSuffix(fileName, ".zip")
+		usedFiles := make(map[string]map[string]bool)
+		for _, recipe := range recipes {
+			dir := markdownPersonalDir
+			if recipe.CookbookID != nil && dirs[*recipe.CookbookID] != "" {
+				dir = dirs[*recipe.CookbookID]
+			}
+			if usedFiles[dir] == nil {
+				usedFiles[dir] = make(map[string]bool)
+			}
+
+			if err := writeMarkdownRecipe(zw, path.Join(root, dir), recipe, usedFiles[dir]); err != nil {
+				http.Error(w, `{"error": "Ошибка при создании архива"}`, http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		http.Error(w, `{"error": "Ошибка при создании архива"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	w.Write(buffer.Bytes())
+}
+
+// markdownRecipeToModel проверяет рецепт из Markdown и читает картинку, путь к которой указан относительно файла.
+func markdownRecipeToModel(name string, doc *markdown.Document, files map[string]*zip.File, result *models.ImportResult) (*models.Recipe, bool) {
+	recipe := doc.Recipe
+	recipe.AuthorName = ""
+	recipe.Title = strings.TrimSpace(recipe.Title)
+	if len([]rune(recipe.Title)) > maxRecipeTitle {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s пропущен: слишком длинное название", name))
+		return nil, false
+	}
+
+	if !models.IsValidVisibility(recipe.Visibility) {
+		recipe.Visibility = models.VisibilityPrivate
+	}
+	if recipe.Servings < 0 || recipe.Servings > maxRecipeServings {
+		recipe.Servings = 0
+	}
+	if recipe.CookingTime < 0 {
+		recipe.CookingTime = 0
+	}
+	if len(doc.UnknownTags) > 0 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("%s: теги %s не сохранены", name, strings.Join(doc.UnknownTags, ", ")))
+	}
+
+	if doc.Image != "" {
+		imagePath := path.Clean(path.Join(path.Dir(name), doc.Image))
+		data, err := readArchiveFile(files, imagePath, maxArchiveImageSize)
+		if err == nil && imageExtension(data) == ".bin" {
+			err = fmt.Errorf("это не изображение")
+		}
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: изображение %s не загружено: %v", name, doc.Image, err))
+		} else {
+			recipe.ImageBase64 = base64.StdEncoding.EncodeToString(data)
+		}
+	}
+
+	return &recipe, true
+}
+
+// importMarkdownHandler импортирует zip-архив с файлами Markdown в любой структуре папок;
+// картинки ищутся по путям из front matter относительно файла рецепта.
+func importMarkdownHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = models.ImportSkip
+	}
+	if !models.IsValidImportMode(mode) {
+		http.Error(w, `{"error": "Режим импорта должен быть skip, overwrite или duplicate"}`, http.StatusBadRequest)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxArchiveSize))
+	if err != nil {
+		http.Error(w, `{"error": "Архив больше 100 МБ или загружен не полностью"}`, http.StatusBadRequest)
+		return
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		http.Error(w, `{"error": "Файл не является zip-архивом"}`, http.StatusBadRequest)
+		return
+	}
+
+	files := make(map[string]*zip.File)
+	var names []string
+	for _, file := range zr.File {
+		files[file.Name] = file
+		base := path.Base(file.Name)
+		ext := strings.ToLower(path.Ext(base))
+		if (ext == markdown.Extension || ext == ".markdown") && !strings.HasPrefix(base, ".") &&
+			!strings.HasPrefix(file.Name, "__MACOSX/") {
+			names = append(names, file.Name)
+		}
+	}
+	if len(names) == 0 {
+		http.Error(w, `{"error": "В архиве нет файлов Markdown"}`, http.StatusBadRequest)
+		return
+	}
+	if len(names) > maxArchiveRecipes {
+		http.Error(w, `{"error": "В архиве больше 5000 рецептов"}`, http.StatusBadRequest)
+		return
+	}
+
+	byTitle, err := authoredRecipesByTitle(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении рецептов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	result := models.ImportResult{IDMap: map[int]int{}, Warnings: []string{}}
+
+	for _, name := range names {
+		content, err := readArchiveFile(files, name, maxArchiveJSONSize)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s пропущен: %v", name, err))
+			result.Skipped++
+			continue
+		}
+
+		doc, err := markdown.Parse(content)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s пропущен: %v", name, err))
+			result.Skipped++
+			continue
+		}
+
+		recipe, ok := markdownRecipeToModel(name, doc, files, &result)
+		if !ok {
+			result.Skipped++
+			continue
+		}
+		recipe.UserID = userID
+
+		if _, err := storeImportedRecipe(recipe, mode, byTitle, &result); err != nil {
+			http.Error(w, `{"error": "Ошибка при сохранении рецептов из Markdown"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Импорт завершен",
+		"result":  result,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
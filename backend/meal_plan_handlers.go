@@ -0,0 +1,343 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+)
+
+const (
+	maxMealPlanRangeDays = 62
+	maxMealPlanServings  = 100
+	maxMealPlanNote      = 200
+)
+
+// weekStart возвращает понедельник недели, в которую попадает дата.
+func weekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// validateMealPlanEntry проверяет дату, приём пищи, порции и заметку записи плана.
+func validateMealPlanEntry(w http.ResponseWriter, entry *models.MealPlanEntry) (time.Time, bool) {
+	date, err := time.Parse(models.DateLayout, entry.Date)
+	if err != nil {
+		http.Error(w, `{"error": "Дата должна быть в формате ГГГГ-ММ-ДД"}`, http.StatusBadRequest)
+		return date, false
+	}
+
+	if !models.IsValidMealSlot(entry.Slot) {
+		http.Error(w, `{"error": "Приём пищи должен быть breakfast, lunch, dinner или snack"}`, http.StatusBadRequest)
+		return date, false
+	}
+
+	if entry.Servings < 0 || entry.Servings > maxMealPlanServings {
+		http.Error(w, `{"error": "Количество порций должно быть от 1 до 100"}`, http.StatusBadRequest)
+		return date, false
+	}
+
+	entry.Note = strings.TrimSpace(entry.Note)
+	if len([]rune(entry.Note)) > maxMealPlanNote {
+		http.Error(w, `{"error": "Заметка не должна превышать 200 символов"}`, http.StatusBadRequest)
+		return date, false
+	}
+
+	return date, true
+}
+
+func mealPlanHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	from, err := time.Parse(models.DateLayout, r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверная дата from"}`, http.StatusBadRequest)
+		return
+	}
+
+	to, err := time.Parse(models.DateLayout, r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверная дата to"}`, http.StatusBadRequest)
+		return
+	}
+
+	if to.Before(from) || to.Sub(from) > maxMealPlanRangeDays*24*time.Hour {
+		http.Error(w, `{"error": "Период должен быть не длиннее 62 дней"}`, http.StatusBadRequest)
+		return
+	}
+
+	entries, err := mealPlanRepo.GetEntries(userID, from, to)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении плана"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Рецепт могли закрыть или перестать им делиться: запись остаётся, но без названия
+	principal := userPrincipal(userID)
+	readable := make(map[int]bool)
+	for i := range entries {
+		if entries[i].RecipeID == nil {
+			continue
+		}
+		recipeID := *entries[i].RecipeID
+		if _, checked := readable[recipeID]; !checked {
+			recipe, err := recipeRepo.GetRecipeByID(recipeID)
+			readable[recipeID] = err == nil && policy.Can(principal, policy.ActionRead, recipe)
+		}
+		if !readable[recipeID] {
+			entries[i].RecipeTitle = ""
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"from":    from.Format(models.DateLayout),
+		"to":      to.Format(models.DateLayout),
+		"entries": entries,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func addMealPlanEntryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var entry models.MealPlanEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	date, ok := validateMealPlanEntry(w, &entry)
+	if !ok {
+		return
+	}
+
+	if entry.RecipeID == nil && entry.Note == "" {
+		http.Error(w, `{"error": "Укажите рецепт или текст записи"}`, http.StatusBadRequest)
+		return
+	}
+
+	if entry.RecipeID != nil {
+		recipe, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionRead, *entry.RecipeID)
+		if !ok {
+			return
+		}
+		entry.RecipeTitle = recipe.Title
+		if entry.Servings == 0 {
+			entry.Servings = 1
+		}
+	}
+	entry.UserID = userID
+
+	if err := mealPlanRepo.CreateEntry(&entry, date); err != nil {
+		http.Error(w, `{"error": "Ошибка при добавлении в план"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"entry":  entry,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func updateMealPlanEntryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var entry models.MealPlanEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	date, ok := validateMealPlanEntry(w, &entry)
+	if !ok {
+		return
+	}
+
+	existing, err := mealPlanRepo.GetEntryByID(userID, entry.ID)
+	if err != nil {
+		http.Error(w, `{"error": "Запись плана не найдена"}`, http.StatusNotFound)
+		return
+	}
+
+	if existing.RecipeID == nil && entry.Note == "" {
+		http.Error(w, `{"error": "Текст записи не может быть пустым"}`, http.StatusBadRequest)
+		return
+	}
+	if existing.RecipeID != nil && entry.Servings == 0 {
+		entry.Servings = existing.Servings
+	}
+	entry.UserID = userID
+
+	if err := mealPlanRepo.UpdateEntry(&entry, date); err != nil {
+		http.Error(w, `{"error": "Запись плана не найдена"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Запись плана обновлена",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func deleteMealPlanEntryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	entryID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID записи"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := mealPlanRepo.DeleteEntry(userID, entryID); err != nil {
+		http.Error(w, `{"error": "Запись плана не найдена"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Запись удалена из плана",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func copyMealPlanWeekHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		FromWeek string `json:"from_week"`
+		ToWeek   string `json:"to_week"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	fromDate, err := time.Parse(models.DateLayout, req.FromWeek)
+	if err != nil {
+		http.Error(w, `{"error": "Неверная дата from_week"}`, http.StatusBadRequest)
+		return
+	}
+
+	toDate, err := time.Parse(models.DateLayout, req.ToWeek)
+	if err != nil {
+		http.Error(w, `{"error": "Неверная дата to_week"}`, http.StatusBadRequest)
+		return
+	}
+
+	source := weekStart(fromDate)
+	target := weekStart(toDate)
+	if source.Equal(target) {
+		http.Error(w, `{"error": "Нельзя скопировать неделю саму в себя"}`, http.StatusBadRequest)
+		return
+	}
+
+	offsetDays := int(target.Sub(source).Hours() / 24)
+	copied, err := mealPlanRepo.CopyRange(userID, source, source.AddDate(0, 0, 6), offsetDays)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при копировании недели"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"copied": copied,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func clearMealPlanWeekHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	date, err := time.Parse(models.DateLayout, r.URL.Query().Get("week"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверная дата week"}`, http.StatusBadRequest)
+		return
+	}
+
+	start := weekStart(date)
+	removed, err := mealPlanRepo.ClearRange(userID, start, start.AddDate(0, 0, 6))
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при очистке недели"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"removed": removed,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"time"
+)
+
+// Архив аккаунта — zip-файл со следующим содержимым:
+//
+//	manifest.json     ArchiveManifest: формат, версия и перечень файлов
+//	recipes.json      []ArchiveRecipe
+//	favorites.json    []ArchiveRecipeRef
+//	collections.json  []ArchiveCollection
+//	reviews.json      []ArchiveReview
+//	images/           изображения рецептов и обложки коллекций, путь указан в ArchiveRecipe.Image
+//	                  и ArchiveCollection.Cover
+//
+// Книги рецептов, ссылки доступа, подписки и комментарии в архив не входят: они связывают аккаунт
+// с другими пользователями. Планы питания, списки покупок, кладовая и профиль питания тоже
+// не выгружаются — это рабочие данные кухни, а не содержимое книги.
+//
+// Версия увеличивается при несовместимых изменениях; новые необязательные поля версию не меняют.
+const (
+	ArchiveFormat  = "culinary-book-archive"
+	ArchiveVersion = 1
+
+	ArchiveManifestFile    = "manifest.json"
+	ArchiveRecipesFile     = "recipes.json"
+	ArchiveFavoritesFile   = "favorites.json"
+	ArchiveCollectionsFile = "collections.json"
+	ArchiveReviewsFile     = "reviews.json"
+	ArchiveImagesDir       = "images/"
+)
+
+// Способы разрешения конфликтов при импорте: рецепт конфликтует с уже существующим,
+// если у пользователя есть рецепт с тем же названием.
+const (
+	ImportSkip      = "skip"
+	ImportOverwrite = "overwrite"
+	ImportDuplicate = "duplicate"
+)
+
+func IsValidImportMode(mode string) bool {
+	switch mode {
+	case ImportSkip, ImportOverwrite, ImportDuplicate:
+		return true
+	}
+	return false
+}
+
+type ArchiveManifest struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Username   string         `json:"username"`
+	Files      map[string]int `json:"files"`
+}
+
+// ArchiveRecipe — рецепт в архиве. ID — идентификатор на сервере, откуда сделана выгрузка;
+// при импорте рецепт получает новый ID, а ссылки на него из избранного, коллекций и отзывов пересчитываются.
+type ArchiveRecipe struct {
+	ID               int             `json:"id"`
+	Title            string          `json:"title"`
+	Description      string          `json:"description"`
+	Ingredients      []string        `json:"ingredients"`
+	Instructions     string          `json:"instructions"`
+	CookingTime      int             `json:"cooking_time"`
+	Servings         int             `json:"servings"`
+	Difficulty       string          `json:"difficulty"`
+	Visibility       string          `json:"visibility"`
+	Image            string          `json:"image,omitempty"`
+	Labels           []string        `json:"labels"`
+	LabelOverrides   map[string]bool `json:"label_overrides,omitempty"`
+	ForkedFromTitle  string          `json:"forked_from_title,omitempty"`
+	ForkedFromAuthor string          `json:"forked_from_author,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// ArchiveRecipeRef — ссылка на рецепт из избранного, коллекции или отзыва. Own означает, что рецепт
+// есть в recipes.json; чужие рецепты при импорте ищутся по ID на текущем сервере и проверяются по названию.
+type ArchiveRecipeRef struct {
+	RecipeID   int    `json:"recipe_id"`
+	Title      string `json:"title"`
+	AuthorName string `json:"author_name,omitempty"`
+	Own        bool   `json:"own"`
+}
+
+// ArchiveCollection — коллекция пользователя с рецептами в её ручном порядке.
+type ArchiveCollection struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Cover       string             `json:"cover,omitempty"`
+	Recipes     []ArchiveRecipeRef `json:"recipes"`
+}
+
+// ArchiveReview — оценка и отзыв пользователя на рецепт.
+type ArchiveReview struct {
+	ArchiveRecipeRef
+	Rating    int       `json:"rating"`
+	Text      string    `json:"text,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ImportResult — итог импорта архива.
+type ImportResult struct {
+	Created     int         `json:"created"`
+	Updated     int         `json:"updated"`
+	Skipped     int         `json:"skipped"`
+	Favorites   int         `json:"favorites"`
+	Collections int         `json:"collections,omitempty"`
+	Reviews     int         `json:"reviews,omitempty"`
+	IDMap       map[int]int `json:"id_map"`
+	Warnings    []string    `json:"warnings"`
+}
+
+// ImportFailure — фрагмент импортируемого файла, из которого не удалось прочитать рецепт.
+type ImportFailure struct {
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+// ImportPreviewItem — рецепт из файла другого приложения, каким он будет сохранён.
+// Exists означает, что у пользователя уже есть рецепт с таким названием.
+type ImportPreviewItem struct {
+	Index        int      `json:"index"`
+	Source       string   `json:"source"`
+	Format       string   `json:"format"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description,omitempty"`
+	Ingredients  []string `json:"ingredients"`
+	Instructions string   `json:"instructions"`
+	CookingTime  int      `json:"cooking_time"`
+	Servings     int      `json:"servings,omitempty"`
+	Difficulty   string   `json:"difficulty,omitempty"`
+	HasImage     bool     `json:"has_image"`
+	Exists       bool     `json:"exists"`
+	Warnings     []string `json:"warnings"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// FavoritesCollectionID — встроенная коллекция «Избранное», которая хранится в таблице favorites.
+const FavoritesCollectionID = 0
+
+type Collection struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CoverBase64 string    `json:"cover_base64,omitempty"`
+	Builtin     bool      `json:"builtin"`
+	RecipeCount int       `json:"recipe_count"`
+	Contains    bool      `json:"contains,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+type Comment struct {
+	ID         int       `json:"id"`
+	RecipeID   int       `json:"recipe_id"`
+	UserID     int       `json:"user_id"`
+	AuthorName string    `json:"author_name,omitempty"`
+	ParentID   *int      `json:"parent_id,omitempty"`
+	Body       string    `json:"body"`
+	Hidden     bool      `json:"hidden"`
+	Pinned     bool      `json:"pinned"`
+	Deleted    bool      `json:"deleted"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Replies    []Comment `json:"replies,omitempty"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	CookbookRoleOwner  = "owner"
+	CookbookRoleEditor = "editor"
+	CookbookRoleViewer = "viewer"
+)
+
+func IsValidCookbookRole(role string) bool {
+	switch role {
+	case CookbookRoleOwner, CookbookRoleEditor, CookbookRoleViewer:
+		return true
+	}
+	return false
+}
+
+type Cookbook struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	OwnerID     int       `json:"owner_id"`
+	Role        string    `json:"role,omitempty"`
+	MemberCount int       `json:"member_count"`
+	RecipeCount int       `json:"recipe_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CookbookMember struct {
+	UserID   int       `json:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type CookbookInvite struct {
+	ID           int       `json:"id"`
+	CookbookID   int       `json:"cookbook_id"`
+	CookbookName string    `json:"cookbook_name"`
+	InviterName  string    `json:"inviter_name"`
+	InviteeID    int       `json:"invitee_id"`
+	InviteeName  string    `json:"invitee_name,omitempty"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
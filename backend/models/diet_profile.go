@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// DietProfile — сохранённый профиль «безопасно для»: аллергены, которых нужно избегать,
+// и диеты, которым рецепт обязан соответствовать.
+type DietProfile struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	Avoid     []string  `json:"avoid"`
+	Require   []string  `json:"require"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LabelFilter объединяет условия одного или нескольких профилей для выборки рецептов.
+type LabelFilter struct {
+	Avoid   []string
+	Require []string
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FeedRecipeCreated = "recipe_created"
+	FeedRecipeUpdated = "recipe_updated"
+	FeedReview        = "review"
+)
+
+type UserSummary struct {
+	ID         int       `json:"id"`
+	Username   string    `json:"username"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+type FeedItem struct {
+	ID          int       `json:"id"`
+	Kind        string    `json:"kind"`
+	ActorID     int       `json:"actor_id"`
+	ActorName   string    `json:"actor_name"`
+	RecipeID    int       `json:"recipe_id"`
+	RecipeTitle string    `json:"recipe_title"`
+	Rating      int       `json:"rating,omitempty"`
+	ReviewText  string    `json:"review_text,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// FeedCursor — позиция в ленте: время и ID последнего показанного события. Курсор хранит сами значения,
+// а не ссылку на событие, поэтому удаление этого события не обрывает ленту.
+type FeedCursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// String записывает курсор как «микросекунды-ID».
+func (c FeedCursor) String() string {
+	return fmt.Sprintf("%d-%d", c.CreatedAt.UnixMicro(), c.ID)
+}
+
+func ParseFeedCursor(s string) (FeedCursor, error) {
+	micros, id, ok := strings.Cut(s, "-")
+	if !ok {
+		return FeedCursor{}, fmt.Errorf("неверный курсор ленты %q", s)
+	}
+	m, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return FeedCursor{}, fmt.Errorf("неверный курсор ленты %q", s)
+	}
+	n, err := strconv.Atoi(id)
+	if err != nil || n <= 0 {
+		return FeedCursor{}, fmt.Errorf("неверный курсор ленты %q", s)
+	}
+	return FeedCursor{CreatedAt: time.UnixMicro(m).UTC(), ID: n}, nil
+}
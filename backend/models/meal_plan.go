@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// DateLayout — формат дат плана питания в API и в запросах.
+const DateLayout = "2006-01-02"
+
+const (
+	MealBreakfast = "breakfast"
+	MealLunch     = "lunch"
+	MealDinner    = "dinner"
+	MealSnack     = "snack"
+)
+
+// MealSlots перечисляет приёмы пищи в порядке показа в течение дня.
+var MealSlots = []string{MealBreakfast, MealLunch, MealDinner, MealSnack}
+
+func IsValidMealSlot(slot string) bool {
+	for _, s := range MealSlots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// MealPlanEntry — рецепт или произвольная запись («ужин в гостях») в ячейке плана.
+type MealPlanEntry struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	Date        string    `json:"date"`
+	Slot        string    `json:"slot"`
+	RecipeID    *int      `json:"recipe_id,omitempty"`
+	RecipeTitle string    `json:"recipe_title,omitempty"`
+	Servings    int       `json:"servings"`
+	Note        string    `json:"note,omitempty"`
+	Position    int       `json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	NotificationSourceUpdated = "source_updated"
+)
+
+type Notification struct {
+	ID             int        `json:"id"`
+	Kind           string     `json:"kind"`
+	RecipeID       int        `json:"recipe_id"`
+	RecipeTitle    string     `json:"recipe_title"`
+	SourceRecipeID *int       `json:"source_recipe_id,omitempty"`
+	SourceTitle    string     `json:"source_title,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// FoodOverride — ручное сопоставление ингредиента продукту из таблицы пищевой ценности.
+// Grams > 0 задаёт вес ингредиента, когда количество нельзя перевести в граммы («пучок укропа»).
+type FoodOverride struct {
+	UserID     int     `json:"user_id"`
+	Ingredient string  `json:"ingredient"`
+	NameKey    string  `json:"-"`
+	FoodID     int     `json:"food_id"`
+	FoodName   string  `json:"food_name"`
+	Grams      float64 `json:"grams,omitempty"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	PantryFridge  = "fridge"
+	PantryFreezer = "freezer"
+	PantryShelf   = "shelf"
+)
+
+func IsValidPantryLocation(location string) bool {
+	switch location {
+	case PantryFridge, PantryFreezer, PantryShelf:
+		return true
+	}
+	return false
+}
+
+// PantryItem — продукт дома. Количество хранится в базовых единицах (г, мл, шт),
+// ExpiresAt — дата в формате DateLayout или nil, если срок не указан.
+type PantryItem struct {
+	ID           int       `json:"id"`
+	UserID       int       `json:"user_id"`
+	Name         string    `json:"name"`
+	NameKey      string    `json:"-"`
+	Quantity     float64   `json:"quantity"`
+	Unit         string    `json:"unit"`
+	Display      string    `json:"display,omitempty"`
+	Location     string    `json:"location"`
+	ExpiresAt    *string   `json:"expires_at,omitempty"`
+	DaysLeft     *int      `json:"days_left,omitempty"`
+	ExpiringSoon bool      `json:"expiring_soon"`
+	Expired      bool      `json:"expired"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// PantryDeduction описывает, сколько списано с продукта после приготовления рецепта.
+type PantryDeduction struct {
+	ItemID    int     `json:"item_id"`
+	Name      string  `json:"name"`
+	Deducted  float64 `json:"deducted"`
+	Remaining float64 `json:"remaining"`
+	Unit      string  `json:"unit"`
+}
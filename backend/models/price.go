@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// IngredientPrice — цена продукта из прайс-листа пользователя: Price за Quantity в базовой единице Unit
+// (например, 89 за 1000 г, если мука продаётся пачками по килограмму).
+type IngredientPrice struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	NameKey   string    `json:"-"`
+	Price     float64   `json:"price"`
+	Quantity  float64   `json:"quantity"`
+	Unit      string    `json:"unit"`
+	Display   string    `json:"display,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
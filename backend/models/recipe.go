@@ -4,20 +4,55 @@ import (
 	"time"
 )
 
+const (
+	VisibilityPrivate  = "private"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPublic   = "public"
+)
+
+func IsValidVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityPrivate, VisibilityUnlisted, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+const (
+	SortNewest = "newest"
+	SortRating = "rating"
+	SortCost   = "cost"
+)
+
 type Recipe struct {
-	ID           int                    `json:"id"`
-	UserID       int                    `json:"user_id"`
-	Title        string                 `json:"title" binding:"required"`
-	Description  string                 `json:"description,omitempty"`
-	Ingredients  []string               `json:"ingredients,omitempty"`
-	Instructions string                 `json:"instructions,omitempty"`
-	CookingTime  int                    `json:"cooking_time,omitempty"`
-	Difficulty   string                 `json:"difficulty,omitempty"`
-	ImageBase64  string                 `json:"image_base64,omitempty"`
-	CreatedAt    time.Time              `json:"created_at"`
-	UpdatedAt    time.Time              `json:"updated_at"`
-	IsFavorite   bool                   `json:"is_favorite"`
-	Extra        map[string]interface{} `json:"-"`
+	ID               int                    `json:"id"`
+	UserID           int                    `json:"user_id"`
+	Title            string                 `json:"title" binding:"required"`
+	Description      string                 `json:"description,omitempty"`
+	Ingredients      []string               `json:"ingredients,omitempty"`
+	Instructions     string                 `json:"instructions,omitempty"`
+	CookingTime      int                    `json:"cooking_time,omitempty"`
+	Servings         int                    `json:"servings,omitempty"`
+	Difficulty       string                 `json:"difficulty,omitempty"`
+	ImageBase64      string                 `json:"image_base64,omitempty"`
+	Visibility       string                 `json:"visibility"`
+	AuthorName       string                 `json:"author_name,omitempty"`
+	RatingAvg        float64                `json:"rating_avg"`
+	RatingCount      int                    `json:"rating_count"`
+	ForkedFromID     *int                   `json:"forked_from_id,omitempty"`
+	ForkedFromTitle  string                 `json:"forked_from_title,omitempty"`
+	ForkedFromAuthor string                 `json:"forked_from_author,omitempty"`
+	ForkCount        int                    `json:"fork_count"`
+	ForkNotify       bool                   `json:"fork_notify"`
+	CookbookID       *int                   `json:"cookbook_id,omitempty"`
+	Labels           []string               `json:"labels"`
+	LabelOverrides   map[string]bool        `json:"label_overrides,omitempty"`
+	CostPerServing   *float64               `json:"cost_per_serving,omitempty"`
+	CostMissing      int                    `json:"cost_missing,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	IsFavorite       bool                   `json:"is_favorite"`
+	Extra            map[string]interface{} `json:"-"`
 }
 
 type RecipeRequest struct {
@@ -26,6 +61,8 @@ type RecipeRequest struct {
 	Ingredients  []string `json:"ingredients,omitempty"`
 	Instructions string   `json:"instructions,omitempty"`
 	CookingTime  int      `json:"cooking_time,omitempty"`
+	Servings     int      `json:"servings,omitempty"`
 	Difficulty   string   `json:"difficulty,omitempty"`
 	ImageBase64  string   `json:"image_base64,omitempty"`
+	Visibility   string   `json:"visibility,omitempty"`
 }
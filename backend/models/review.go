@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+type Review struct {
+	ID         int       `json:"id"`
+	RecipeID   int       `json:"recipe_id"`
+	UserID     int       `json:"user_id"`
+	AuthorName string    `json:"author_name,omitempty"`
+	Rating     int       `json:"rating"`
+	Text       string    `json:"text,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
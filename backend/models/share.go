@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+type RecipeShare struct {
+	ID        int        `json:"id"`
+	RecipeID  int        `json:"recipe_id"`
+	UserID    int        `json:"user_id"`
+	Token     string     `json:"token,omitempty"`
+	Path      string     `json:"path,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (s *RecipeShare) IsActive() bool {
+	if s.RevokedAt != nil {
+		return false
+	}
+	return s.ExpiresAt == nil || s.ExpiresAt.After(time.Now())
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// ShoppingItem — строка списка покупок. Количество хранится в базовых единицах (г, мл, шт),
+// Quantity == 0 означает «по вкусу» или количество, которое не удалось разобрать.
+type ShoppingItem struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	NameKey   string    `json:"-"`
+	Quantity  float64   `json:"quantity"`
+	Unit      string    `json:"unit"`
+	Display   string    `json:"display,omitempty"`
+	Category  string    `json:"category"`
+	Checked   bool      `json:"checked"`
+	Manual    bool      `json:"manual"`
+	Sources   string    `json:"sources,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ShoppingRecipe — рецепт, из которого строится список, и во сколько раз увеличить его ингредиенты.
+type ShoppingRecipe struct {
+	RecipeID   int     `json:"recipe_id"`
+	Multiplier float64 `json:"multiplier"`
+}
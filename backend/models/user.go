@@ -5,11 +5,23 @@ import (
 )
 
 type User struct {
-	ID           int       `json:"id"`
-	Username     string    `json:"username"`
-	PasswordHash string    `json:"-"`
-	Email        string    `json:"email,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID                  int        `json:"id"`
+	Username            string     `json:"username"`
+	PasswordHash        string     `json:"-"`
+	Email               string     `json:"email,omitempty"`
+	DisplayName         string     `json:"display_name,omitempty"`
+	AvatarBase64        string     `json:"avatar_base64,omitempty"`
+	Bio                 string     `json:"bio,omitempty"`
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+type ProfileUpdate struct {
+	DisplayName  *string `json:"display_name"`
+	Email        *string `json:"email"`
+	AvatarBase64 *string `json:"avatar_base64"`
+	Bio          *string `json:"bio"`
 }
 
 type RegisterRequest struct {
@@ -0,0 +1,200 @@
+// Package nutrition хранит встроенную таблицу пищевой ценности продуктов
+// и пересчитывает ингредиенты рецепта в калории и БЖУ.
+package nutrition
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/ingredients"
+)
+
+//go:embed foods.csv
+var bundledFoods string
+
+// Facts — пищевая ценность: на 100 г для продукта или итог для рецепта и порции.
+type Facts struct {
+	Calories float64 `json:"calories"`
+	Protein  float64 `json:"protein"`
+	Fat      float64 `json:"fat"`
+	Carbs    float64 `json:"carbs"`
+	Fiber    float64 `json:"fiber"`
+}
+
+func (f Facts) Add(other Facts) Facts {
+	return Facts{
+		Calories: f.Calories + other.Calories,
+		Protein:  f.Protein + other.Protein,
+		Fat:      f.Fat + other.Fat,
+		Carbs:    f.Carbs + other.Carbs,
+		Fiber:    f.Fiber + other.Fiber,
+	}
+}
+
+func (f Facts) Scale(factor float64) Facts {
+	return Facts{
+		Calories: f.Calories * factor,
+		Protein:  f.Protein * factor,
+		Fat:      f.Fat * factor,
+		Carbs:    f.Carbs * factor,
+		Fiber:    f.Fiber * factor,
+	}
+}
+
+// Round округляет значения до десятых, чтобы не отдавать в API длинные дроби.
+func (f Facts) Round() Facts {
+	round := func(v float64) float64 {
+		return float64(int64(v*10+0.5)) / 10
+	}
+	return Facts{
+		Calories: round(f.Calories),
+		Protein:  round(f.Protein),
+		Fat:      round(f.Fat),
+		Carbs:    round(f.Carbs),
+		Fiber:    round(f.Fiber),
+	}
+}
+
+// Food — строка таблицы продуктов. PieceGrams — вес одной штуки,
+// Density — граммов в миллилитре; ноль означает, что перевод неизвестен.
+type Food struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Aliases    []string `json:"aliases,omitempty"`
+	Per100g    Facts    `json:"per_100g"`
+	PieceGrams float64  `json:"piece_grams,omitempty"`
+	Density    float64  `json:"density,omitempty"`
+}
+
+// Bundled возвращает таблицу продуктов, встроенную в сервер.
+func Bundled() ([]Food, error) {
+	return ParseCSV(strings.NewReader(bundledFoods))
+}
+
+// ParseCSV читает таблицу с заголовком name,aliases,kcal,protein,fat,carbs,fiber,piece_grams,density.
+// Синонимы разделяются символом «|».
+func ParseCSV(r io.Reader) ([]Food, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("пустая таблица продуктов")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "kcal", "protein", "fat", "carbs"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("в таблице нет столбца %s", required)
+		}
+	}
+
+	var foods []Food
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("строка %d: %v", line, err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		number := func(name string) (float64, error) {
+			value := field(name)
+			if value == "" {
+				return 0, nil
+			}
+			return strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+		}
+
+		food := Food{Name: strings.ToLower(field("name"))}
+		if food.Name == "" {
+			return nil, fmt.Errorf("строка %d: не указано название", line)
+		}
+		if aliases := field("aliases"); aliases != "" {
+			for _, alias := range strings.Split(aliases, "|") {
+				if alias = strings.ToLower(strings.TrimSpace(alias)); alias != "" {
+					food.Aliases = append(food.Aliases, alias)
+				}
+			}
+		}
+
+		values := []*float64{
+			&food.Per100g.Calories, &food.Per100g.Protein, &food.Per100g.Fat,
+			&food.Per100g.Carbs, &food.Per100g.Fiber, &food.PieceGrams, &food.Density,
+		}
+		for i, name := range []string{"kcal", "protein", "fat", "carbs", "fiber", "piece_grams", "density"} {
+			value, err := number(name)
+			if err != nil || value < 0 {
+				return nil, fmt.Errorf("строка %d: неверное значение %s", line, name)
+			}
+			*values[i] = value
+		}
+
+		foods = append(foods, food)
+	}
+
+	return foods, nil
+}
+
+// Match подбирает продукт по ключу названия ингредиента.
+// Выигрывает самое точное совпадение: полное совпадение ключа, затем больше общих слов.
+func Match(key string, foods []Food) *Food {
+	var best *Food
+	bestScore := 0
+
+	for i := range foods {
+		for _, name := range append([]string{foods[i].Name}, foods[i].Aliases...) {
+			nameKey := ingredients.Key(name)
+			if !ingredients.Matches(key, nameKey) {
+				continue
+			}
+
+			score := len(strings.Fields(nameKey))
+			if nameKey == key {
+				score += 100
+			}
+			if score > bestScore {
+				best, bestScore = &foods[i], score
+			}
+		}
+	}
+
+	return best
+}
+
+// Grams переводит количество ингредиента в граммы для продукта.
+func Grams(ingredient ingredients.Ingredient, food Food) (float64, bool) {
+	switch ingredient.Unit {
+	case ingredients.UnitGram:
+		return ingredient.Quantity, true
+	case ingredients.UnitMl:
+		density := food.Density
+		if density == 0 {
+			density = 1
+		}
+		return ingredient.Quantity * density, true
+	case ingredients.UnitPiece:
+		if food.PieceGrams == 0 {
+			return 0, false
+		}
+		return ingredient.Quantity * food.PieceGrams, true
+	}
+	return 0, false
+}
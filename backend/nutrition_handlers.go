@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/ingredients"
+	"culinary-book/backend/models"
+	"culinary-book/backend/nutrition"
+	"culinary-book/backend/policy"
+)
+
+const (
+	maxRecipeServings  = 100
+	maxOverrideGrams   = 10000
+	foodSearchLimit    = 20
+	foodsCSVEnvVarName = "FOODS_CSV"
+)
+
+type ingredientNutrition struct {
+	Raw      string          `json:"raw"`
+	Name     string          `json:"name"`
+	FoodID   int             `json:"food_id,omitempty"`
+	FoodName string          `json:"food_name,omitempty"`
+	Grams    float64         `json:"grams"`
+	Facts    nutrition.Facts `json:"facts"`
+	Matched  bool            `json:"matched"`
+	Override bool            `json:"override"`
+	Note     string          `json:"note,omitempty"`
+}
+
+type recipeNutrition struct {
+	RecipeID    int                   `json:"recipe_id"`
+	Servings    int                   `json:"servings"`
+	Total       nutrition.Facts       `json:"total"`
+	PerServing  *nutrition.Facts      `json:"per_serving,omitempty"`
+	Ingredients []ingredientNutrition `json:"ingredients"`
+	Unmatched   int                   `json:"unmatched"`
+}
+
+// importFoods загружает встроенную таблицу продуктов и, если задан FOODS_CSV, дополнительную таблицу из файла.
+func importFoods() {
+	foods, err := nutrition.Bundled()
+	if err != nil {
+		log.Printf("⚠️  Встроенная таблица продуктов повреждена: %v", err)
+		return
+	}
+
+	if path := os.Getenv(foodsCSVEnvVarName); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Printf("⚠️  Не удалось открыть %s: %v", path, err)
+		} else {
+			extra, err := nutrition.ParseCSV(file)
+			file.Close()
+			if err != nil {
+				log.Printf("⚠️  Ошибка в таблице продуктов %s: %v", path, err)
+			} else {
+				foods = append(foods, extra...)
+			}
+		}
+	}
+
+	count, err := foodRepo.ImportFoods(foods)
+	if err != nil {
+		log.Printf("⚠️  Не удалось загрузить таблицу продуктов: %v", err)
+		return
+	}
+
+	log.Printf("✅ Таблица продуктов загружена: %d", count)
+}
+
+// computeNutrition сопоставляет ингредиенты продуктам и складывает пищевую ценность.
+// Ингредиенты «по вкусу» и без сопоставления в сумму не входят, но перечисляются.
+func computeNutrition(recipe *models.Recipe, foods []nutrition.Food, overrides map[string]models.FoodOverride) recipeNutrition {
+	result := recipeNutrition{
+		RecipeID:    recipe.ID,
+		Servings:    recipe.Servings,
+		Ingredients: []ingredientNutrition{},
+	}
+
+	byID := make(map[int]*nutrition.Food)
+	for i := range foods {
+		byID[foods[i].ID] = &foods[i]
+	}
+
+	for _, line := range recipe.Ingredients {
+		ingredient := ingredients.Parse(line)
+		key := ingredients.Key(ingredient.Name)
+		item := ingredientNutrition{Raw: ingredient.Raw, Name: ingredient.Name}
+
+		var food *nutrition.Food
+		override, hasOverride := overrides[key]
+		if hasOverride {
+			food = byID[override.FoodID]
+			item.Override = food != nil
+		}
+		if food == nil {
+			food = nutrition.Match(key, foods)
+		}
+
+		if food == nil {
+			item.Note = "продукт не найден"
+			result.Unmatched++
+			result.Ingredients = append(result.Ingredients, item)
+			continue
+		}
+
+		item.FoodID, item.FoodName, item.Matched = food.ID, food.Name, true
+
+		// «Соль по вкусу» без количества не переводится в граммы, но и не считается несопоставленной
+		grams, ok := 0.0, true
+		if ingredient.Quantity != 0 {
+			grams, ok = nutrition.Grams(ingredient, *food)
+		}
+		if (!ok || ingredient.Quantity == 0) && item.Override && override.Grams > 0 {
+			grams, ok = override.Grams, true
+		}
+
+		switch {
+		case !ok:
+			item.Note = "не удалось перевести количество в граммы"
+			result.Unmatched++
+		case grams == 0:
+			item.Note = "количество не указано"
+		default:
+			item.Grams = grams
+			item.Facts = food.Per100g.Scale(grams / 100)
+			result.Total = result.Total.Add(item.Facts)
+		}
+
+		item.Facts = item.Facts.Round()
+		result.Ingredients = append(result.Ingredients, item)
+	}
+
+	if recipe.Servings > 0 {
+		perServing := result.Total.Scale(1 / float64(recipe.Servings)).Round()
+		result.PerServing = &perServing
+	}
+	result.Total = result.Total.Round()
+
+	return result
+}
+
+func recipeNutritionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal := principalFromRequest(r)
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, principal, policy.ActionRead, recipeID)
+	if !ok {
+		return
+	}
+
+	foods, err := foodRepo.GetFoods()
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении таблицы продуктов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	// Сопоставления автора видны всем читателям, свои сопоставления читателя их перекрывают
+	overrides, err := foodRepo.GetOverrides(recipe.UserID, principal.UserID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении сопоставлений"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":    "ok",
+		"nutrition": computeNutrition(recipe, foods, overrides),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func foodsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	search := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	if search == "" {
+		http.Error(w, `{"error": "Укажите строку поиска"}`, http.StatusBadRequest)
+		return
+	}
+
+	foods, err := foodRepo.SearchFoods(search, foodSearchLimit)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при поиске продуктов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"foods":  foods,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func setFoodOverrideHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var override models.FoodOverride
+	if err := json.NewDecoder(r.Body).Decode(&override); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	name := ingredients.Parse(override.Ingredient).Name
+	if name == "" {
+		http.Error(w, `{"error": "Укажите ингредиент"}`, http.StatusBadRequest)
+		return
+	}
+
+	if override.Grams < 0 || override.Grams > maxOverrideGrams {
+		http.Error(w, `{"error": "Вес должен быть от 0 до 10000 г"}`, http.StatusBadRequest)
+		return
+	}
+
+	food, err := foodRepo.GetFoodByID(override.FoodID)
+	if err != nil {
+		http.Error(w, `{"error": "Продукт не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	override.UserID = userID
+	override.Ingredient = name
+	override.NameKey = ingredients.Key(name)
+	override.FoodName = food.Name
+
+	if err := foodRepo.SetOverride(&override); err != nil {
+		http.Error(w, `{"error": "Ошибка при сохранении сопоставления"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":   "ok",
+		"override": override,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func deleteFoodOverrideHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	name := ingredients.Parse(r.URL.Query().Get("ingredient")).Name
+	if err := foodRepo.DeleteOverride(userID, ingredients.Key(name)); err != nil {
+		http.Error(w, `{"error": "Сопоставление не найдено"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Сопоставление удалено",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
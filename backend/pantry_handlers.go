@@ -0,0 +1,442 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"culinary-book/backend/ingredients"
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+	"culinary-book/backend/repository"
+)
+
+const (
+	pantryExpiringDays  = 3
+	maxPantryNameLength = 200
+	maxUseSoonRecipes   = 20
+)
+
+// annotatePantryItems считает дни до конца срока и помечает продукты, которые пора использовать.
+func annotatePantryItems(items []models.PantryItem) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	for i := range items {
+		items[i].Display = ingredients.FormatQuantity(items[i].Quantity, items[i].Unit)
+		if items[i].ExpiresAt == nil {
+			continue
+		}
+
+		expiresAt, err := time.Parse(models.DateLayout, *items[i].ExpiresAt)
+		if err != nil {
+			continue
+		}
+
+		daysLeft := int(expiresAt.Sub(today).Hours() / 24)
+		items[i].DaysLeft = &daysLeft
+		items[i].Expired = daysLeft < 0
+		items[i].ExpiringSoon = daysLeft >= 0 && daysLeft <= pantryExpiringDays
+	}
+}
+
+// decodePantryItem принимает строку целиком («1 л молока») или отдельные поля.
+func decodePantryItem(w http.ResponseWriter, r *http.Request, item *models.PantryItem) (*time.Time, bool) {
+	var req struct {
+		ID        int     `json:"id"`
+		Text      string  `json:"text"`
+		Name      string  `json:"name"`
+		Quantity  float64 `json:"quantity"`
+		Unit      string  `json:"unit"`
+		Location  string  `json:"location"`
+		ExpiresAt string  `json:"expires_at"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	item.ID = req.ID
+	if strings.TrimSpace(req.Text) != "" {
+		parsed := ingredients.Parse(req.Text)
+		item.Name, item.Quantity, item.Unit = parsed.Name, parsed.Quantity, parsed.Unit
+	} else {
+		quantity, unit, ok := ingredients.Normalize(req.Quantity, req.Unit)
+		if !ok {
+			http.Error(w, `{"error": "Неизвестная единица измерения"}`, http.StatusBadRequest)
+			return nil, false
+		}
+		item.Name, item.Quantity, item.Unit = strings.TrimSpace(req.Name), quantity, unit
+	}
+
+	if item.Name == "" || len([]rune(item.Name)) > maxPantryNameLength {
+		http.Error(w, `{"error": "Название продукта должно быть от 1 до 200 символов"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	if item.Quantity < 0 {
+		http.Error(w, `{"error": "Количество не может быть отрицательным"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	item.NameKey = ingredients.Key(item.Name)
+	item.Location = req.Location
+	if item.Location == "" {
+		item.Location = models.PantryFridge
+	}
+	if !models.IsValidPantryLocation(item.Location) {
+		http.Error(w, `{"error": "Место хранения должно быть fridge, freezer или shelf"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	if req.ExpiresAt == "" {
+		return nil, true
+	}
+
+	expiresAt, err := time.Parse(models.DateLayout, req.ExpiresAt)
+	if err != nil {
+		http.Error(w, `{"error": "Срок годности должен быть в формате ГГГГ-ММ-ДД"}`, http.StatusBadRequest)
+		return nil, false
+	}
+
+	return &expiresAt, true
+}
+
+func pantryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	items, err := pantryRepo.GetItems(userID, r.URL.Query().Get("sort"))
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении запасов"}`, http.StatusInternalServerError)
+		return
+	}
+	annotatePantryItems(items)
+
+	expiring := 0
+	for _, item := range items {
+		if item.ExpiringSoon || item.Expired {
+			expiring++
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":   "ok",
+		"count":    len(items),
+		"expiring": expiring,
+		"items":    items,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func addPantryItemHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var item models.PantryItem
+	expiresAt, ok := decodePantryItem(w, r, &item)
+	if !ok {
+		return
+	}
+	item.UserID = userID
+
+	if err := pantryRepo.CreateItem(&item, expiresAt); err != nil {
+		http.Error(w, `{"error": "Ошибка при добавлении продукта"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"item":   item,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func updatePantryItemHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var item models.PantryItem
+	expiresAt, ok := decodePantryItem(w, r, &item)
+	if !ok {
+		return
+	}
+	item.UserID = userID
+
+	if err := pantryRepo.UpdateItem(&item, expiresAt); err != nil {
+		http.Error(w, `{"error": "Продукт не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Продукт обновлен",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func deletePantryItemHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	itemID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID продукта"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := pantryRepo.DeleteItem(userID, itemID); err != nil {
+		http.Error(w, `{"error": "Продукт не найден"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Продукт удален",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// planDeductions подбирает, с каких продуктов списать ингредиенты рецепта.
+// Сначала расходуются продукты с ближайшим сроком; единицы должны совпадать.
+func planDeductions(items []models.PantryItem, recipe *models.Recipe, multiplier float64) ([]models.PantryDeduction, []string) {
+	remaining := make(map[int]float64)
+	for _, item := range items {
+		remaining[item.ID] = item.Quantity
+	}
+
+	var deductions []models.PantryDeduction
+	var missing []string
+	deducted := make(map[int]int)
+
+	for _, line := range recipe.Ingredients {
+		ingredient := ingredients.Parse(line).Scale(multiplier)
+		if ingredient.Name == "" || ingredient.Quantity == 0 {
+			continue
+		}
+
+		need := ingredient.Quantity
+		key := ingredients.Key(ingredient.Name)
+		for _, item := range items {
+			if need <= 0 {
+				break
+			}
+			if item.Unit != ingredient.Unit || remaining[item.ID] <= 0 || !ingredients.Matches(key, item.NameKey) {
+				continue
+			}
+
+			take := need
+			if take > remaining[item.ID] {
+				take = remaining[item.ID]
+			}
+			remaining[item.ID] -= take
+			need -= take
+
+			if at, ok := deducted[item.ID]; ok {
+				deductions[at].Deducted += take
+				deductions[at].Remaining = remaining[item.ID]
+				continue
+			}
+			deducted[item.ID] = len(deductions)
+			deductions = append(deductions, models.PantryDeduction{
+				ItemID:    item.ID,
+				Name:      item.Name,
+				Deducted:  take,
+				Remaining: remaining[item.ID],
+				Unit:      item.Unit,
+			})
+		}
+
+		if need > 0 {
+			missing = append(missing, ingredient.Raw)
+		}
+	}
+
+	return deductions, missing
+}
+
+func cookedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req models.ShoppingRecipe
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.Multiplier == 0 {
+		req.Multiplier = 1
+	}
+	if req.Multiplier < 0 || req.Multiplier > maxShoppingMultiplier {
+		http.Error(w, `{"error": "Множитель должен быть от 0 до 20"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionRead, req.RecipeID)
+	if !ok {
+		return
+	}
+
+	items, err := pantryRepo.GetItems(userID, repository.PantrySortExpiry)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении запасов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	deductions, missing := planDeductions(items, recipe, req.Multiplier)
+	if err := pantryRepo.ApplyDeductions(userID, deductions); err != nil {
+		http.Error(w, `{"error": "Ошибка при списании продуктов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":     "ok",
+		"deductions": deductions,
+		"missing":    missing,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+type useSoonSuggestion struct {
+	Recipe models.Recipe `json:"recipe"`
+	Uses   []string      `json:"uses"`
+}
+
+// useSoonHandler предлагает свои и избранные рецепты, в которых есть продукты с истекающим сроком.
+func useSoonHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	items, err := pantryRepo.GetItems(userID, repository.PantrySortExpiry)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении запасов"}`, http.StatusInternalServerError)
+		return
+	}
+	annotatePantryItems(items)
+
+	var expiring []models.PantryItem
+	for _, item := range items {
+		if item.ExpiringSoon {
+			expiring = append(expiring, item)
+		}
+	}
+
+	candidates, err := recipeRepo.GetAuthoredRecipes(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении рецептов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	seen := make(map[int]bool)
+	for _, recipe := range candidates {
+		seen[recipe.ID] = true
+	}
+
+	principal := userPrincipal(userID)
+	favoriteIDs, _ := favoriteRepo.GetFavoriteRecipes(userID)
+	for _, recipeID := range favoriteIDs {
+		if seen[recipeID] {
+			continue
+		}
+		recipe, err := recipeRepo.GetRecipeByID(recipeID)
+		if err == nil && policy.Can(principal, policy.ActionRead, recipe) {
+			seen[recipeID] = true
+			candidates = append(candidates, *recipe)
+		}
+	}
+
+	suggestions := []useSoonSuggestion{}
+	for _, recipe := range candidates {
+		var uses []string
+		for _, item := range expiring {
+			for _, line := range recipe.Ingredients {
+				if ingredients.Matches(ingredients.Key(ingredients.Parse(line).Name), item.NameKey) {
+					uses = append(uses, item.Name)
+					break
+				}
+			}
+		}
+		if len(uses) > 0 {
+			suggestions = append(suggestions, useSoonSuggestion{Recipe: recipe, Uses: uses})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return len(suggestions[i].Uses) > len(suggestions[j].Uses)
+	})
+	if len(suggestions) > maxUseSoonRecipes {
+		suggestions = suggestions[:maxUseSoonRecipes]
+	}
+
+	response := map[string]interface{}{
+		"status":   "ok",
+		"expiring": expiring,
+		"recipes":  suggestions,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"culinary-book/backend/models"
+	"culinary-book/backend/pdfbook"
+	"culinary-book/backend/policy"
+)
+
+const (
+	maxPDFRecipes   = 300
+	maxPDFTitle     = 200
+	defaultPDFTitle = "Кулинарная книга"
+)
+
+// pdfBookRequest — что положить в книгу: коллекцию (0 — избранное) или рецепты по списку.
+type pdfBookRequest struct {
+	Title        string `json:"title"`
+	Subtitle     string `json:"subtitle"`
+	RecipeIDs    []int  `json:"recipe_ids"`
+	CollectionID *int   `json:"collection_id"`
+}
+
+// pdfBookHandler собирает печатную книгу в PDF из выбранных рецептов, коллекции или избранного.
+// Рецепты, которые пользователь не может читать, в книгу не попадают.
+func pdfBookHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req pdfBookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	book := pdfbook.Book{
+		Title:    strings.TrimSpace(req.Title),
+		Subtitle: strings.TrimSpace(req.Subtitle),
+		Date:     time.Now(),
+	}
+	if len([]rune(book.Title)) > maxPDFTitle || len([]rune(book.Subtitle)) > maxPDFTitle {
+		http.Error(w, `{"error": "Название книги не должно превышать 200 символов"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipeIDs := req.RecipeIDs
+	if req.CollectionID != nil {
+		collectionID := *req.CollectionID
+		if !checkCollectionOwner(w, userID, collectionID) {
+			return
+		}
+
+		if collectionID == models.FavoritesCollectionID {
+			recipeIDs, err = favoriteRepo.GetFavoriteRecipes(userID)
+			if book.Title == "" {
+				book.Title = "Избранное"
+			}
+		} else {
+			recipeIDs, err = collectionRepo.GetRecipeIDs(collectionID)
+			if err == nil {
+				pdfCollectionCover(&book, userID, collectionID)
+			}
+		}
+		if err != nil {
+			http.Error(w, `{"error": "Ошибка при получении рецептов коллекции"}`, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	seen := make(map[int]bool)
+	var unique []int
+	for _, recipeID := range recipeIDs {
+		if !seen[recipeID] {
+			seen[recipeID] = true
+			unique = append(unique, recipeID)
+		}
+	}
+	if len(unique) > maxPDFRecipes {
+		http.Error(w, `{"error": "В книге может быть не больше 300 рецептов"}`, http.StatusBadRequest)
+		return
+	}
+
+	principal := userPrincipal(userID)
+	for _, recipeID := range unique {
+		recipe, err := recipeRepo.GetRecipeByID(recipeID)
+		if err == nil && policy.Can(principal, policy.ActionRead, recipe) {
+			book.Recipes = append(book.Recipes, *recipe)
+		}
+	}
+	if len(book.Recipes) == 0 {
+		http.Error(w, `{"error": "Нет рецептов для книги"}`, http.StatusBadRequest)
+		return
+	}
+
+	if book.Title == "" {
+		book.Title = defaultPDFTitle
+		if len(book.Recipes) == 1 {
+			book.Title = book.Recipes[0].Title
+		}
+	}
+	if user, err := userRepo.GetUserByID(userID); err == nil {
+		book.Author = user.DisplayName
+		if book.Author == "" {
+			book.Author = user.Username
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := pdfbook.Render(book, &buffer); err != nil {
+		log.Printf("Ошибка создания PDF: %v", err)
+		http.Error(w, `{"error": "Ошибка при создании PDF"}`, http.StatusInternalServerError)
+		return
+	}
+
+	fileName := fmt.Sprintf("cookbook-%s.pdf", book.Date.Format(models.DateLayout))
+	if len(book.Recipes) == 1 {
+		fileName = fmt.Sprintf("recipe-%d.pdf", book.Recipes[0].ID)
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	w.Write(buffer.Bytes())
+}
+
+// pdfCollectionCover берёт для книги название и обложку коллекции, если их не задали явно.
+func pdfCollectionCover(book *pdfbook.Book, userID, collectionID int) {
+	collections, err := collectionRepo.GetCollections(userID, 0)
+	if err != nil {
+		return
+	}
+	for _, collection := range collections {
+		if collection.ID != collectionID {
+			continue
+		}
+		if book.Title == "" {
+			book.Title = collection.Name
+		}
+		if book.Subtitle == "" {
+			book.Subtitle = collection.Description
+		}
+		if data, err := base64.StdEncoding.DecodeString(collection.CoverBase64); err == nil {
+			book.Cover = data
+		}
+		return
+	}
+}
@@ -0,0 +1,309 @@
+// Package pdfbook собирает печатную кулинарную книгу в PDF: обложку, содержание
+// со ссылками и рецепты, каждый с новой страницы. Шрифт DejaVu Sans встроен в программу
+// и в документ, поэтому кириллица печатается без установленных в системе шрифтов.
+package pdfbook
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"strings"
+	"time"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"culinary-book/backend/models"
+	"culinary-book/backend/schemaorg"
+)
+
+//go:embed fonts/DejaVuSans.ttf
+var regularFont []byte
+
+//go:embed fonts/DejaVuSans-Bold.ttf
+var boldFont []byte
+
+const (
+	fontFamily = "DejaVu"
+
+	// Размеры в миллиметрах, страница A4
+	margin         = 20.0
+	contentWidth   = 210 - 2*margin
+	maxPhotoHeight = 90.0
+	maxCoverHeight = 110.0
+	lineHeight     = 6.0
+	numberWidth    = 8.0
+
+	// Строк содержания на странице: по ним заранее известно, сколько страниц оставить под содержание
+	contentsPerPage = 30
+	contentsLine    = 7.5
+)
+
+// Book — что печатать. Обложка и содержание добавляются, только если рецептов больше одного.
+type Book struct {
+	Title    string
+	Subtitle string
+	Author   string
+	Cover    []byte
+	Date     time.Time
+	Recipes  []models.Recipe
+}
+
+type builder struct {
+	pdf *gofpdf.Fpdf
+}
+
+// Render записывает книгу в w.
+func Render(book Book, w io.Writer) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", regularFont)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", boldFont)
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetTitle(book.Title, true)
+	pdf.SetAuthor(book.Author, true)
+	pdf.SetCreator("KonKi", true)
+	pdf.SetCreationDate(book.Date)
+
+	b := &builder{pdf: pdf}
+	withContents := len(book.Recipes) > 1
+
+	pdf.SetFooterFunc(func() {
+		if withContents && pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(-margin + 5)
+		pdf.SetFont(fontFamily, "", 9)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 5, fmt.Sprint(pdf.PageNo()), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	var contentsPages int
+	if withContents {
+		b.cover(book)
+		contentsPages = (len(book.Recipes) + contentsPerPage - 1) / contentsPerPage
+		for i := 0; i < contentsPages; i++ {
+			pdf.AddPage()
+		}
+	}
+
+	links := make([]int, len(book.Recipes))
+	pages := make([]int, len(book.Recipes))
+	for i, recipe := range book.Recipes {
+		links[i] = pdf.AddLink()
+		pages[i] = b.recipe(recipe, links[i])
+	}
+
+	if withContents {
+		last := pdf.PageNo()
+		b.contents(book.Recipes, links, pages)
+		// Возврат на последнюю страницу: при закрытии документа нижний колонтитул рисуется на текущей
+		pdf.SetPage(last)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+func (b *builder) cover(book Book) {
+	pdf := b.pdf
+	pdf.AddPage()
+
+	pdf.SetY(60)
+	pdf.SetFont(fontFamily, "B", 30)
+	pdf.MultiCell(0, 13, book.Title, "", "C", false)
+	if book.Subtitle != "" {
+		pdf.Ln(4)
+		pdf.SetFont(fontFamily, "", 14)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.MultiCell(0, 7, book.Subtitle, "", "C", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+
+	if name, width, height, ok := b.registerImage("cover", book.Cover, contentWidth, maxCoverHeight); ok {
+		pdf.Ln(12)
+		pdf.ImageOptions(name, margin+(contentWidth-width)/2, pdf.GetY(), width, height, false,
+			gofpdf.ImageOptions{ImageType: "JPG"}, 0, "")
+	}
+
+	pdf.SetY(-margin - 20)
+	pdf.SetFont(fontFamily, "", 11)
+	pdf.SetTextColor(90, 90, 90)
+	if book.Author != "" {
+		pdf.CellFormat(0, 6, "Составитель: "+book.Author, "", 1, "C", false, 0, "")
+	}
+	pdf.CellFormat(0, 6, fmt.Sprintf("Рецептов: %d · %s", len(book.Recipes), book.Date.Format("02.01.2006")), "", 1, "C", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// contents заполняет страницы, оставленные под содержание, когда номера страниц рецептов уже известны.
+func (b *builder) contents(recipes []models.Recipe, links, pages []int) {
+	pdf := b.pdf
+	for i, recipe := range recipes {
+		if i%contentsPerPage == 0 {
+			pdf.SetPage(2 + i/contentsPerPage)
+			// Страница уже закрыта, и шрифт в её потоке — от колонтитула: заставляем gofpdf выбрать шрифт заново
+			pdf.SetFontSize(1)
+			pdf.SetY(margin)
+			if i == 0 {
+				pdf.SetFont(fontFamily, "B", 20)
+				pdf.CellFormat(0, 12, "Содержание", "", 1, "L", false, 0, "")
+				pdf.Ln(4)
+			}
+			pdf.SetFont(fontFamily, "", 12)
+		}
+
+		number := fmt.Sprint(pages[i])
+		numberW := pdf.GetStringWidth(number) + 2
+		title := fitText(pdf, recipe.Title, contentWidth-numberW-10)
+		dots := strings.Repeat(".", int((contentWidth-numberW-pdf.GetStringWidth(title)-2)/pdf.GetStringWidth(".")))
+
+		y := pdf.GetY()
+		pdf.CellFormat(contentWidth-numberW, contentsLine, title+" "+dots, "", 0, "L", false, links[i], "")
+		pdf.CellFormat(numberW, contentsLine, number, "", 1, "R", false, links[i], "")
+		pdf.SetY(y + contentsLine)
+	}
+}
+
+// recipe печатает рецепт с новой страницы и возвращает номер этой страницы.
+func (b *builder) recipe(recipe models.Recipe, link int) int {
+	pdf := b.pdf
+	pdf.AddPage()
+	page := pdf.PageNo()
+	pdf.SetLink(link, 0, page)
+
+	pdf.SetFont(fontFamily, "B", 20)
+	pdf.MultiCell(0, 9, recipe.Title, "", "L", false)
+
+	var meta []string
+	if recipe.CookingTime > 0 {
+		meta = append(meta, fmt.Sprintf("Время: %d мин", recipe.CookingTime))
+	}
+	if recipe.Servings > 0 {
+		meta = append(meta, fmt.Sprintf("Порций: %d", recipe.Servings))
+	}
+	if recipe.Difficulty != "" {
+		meta = append(meta, "Сложность: "+recipe.Difficulty)
+	}
+	if recipe.AuthorName != "" {
+		meta = append(meta, "Автор: "+recipe.AuthorName)
+	}
+	if len(meta) > 0 {
+		pdf.Ln(1)
+		pdf.SetFont(fontFamily, "", 10)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.MultiCell(0, 5, strings.Join(meta, " · "), "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+
+	if data, err := base64.StdEncoding.DecodeString(recipe.ImageBase64); err == nil && len(data) > 0 {
+		name := fmt.Sprintf("recipe-%d-%d", recipe.ID, page)
+		if name, width, height, ok := b.registerImage(name, data, contentWidth, maxPhotoHeight); ok {
+			pdf.Ln(5)
+			if pdf.GetY()+height > 297-margin {
+				pdf.AddPage()
+			}
+			pdf.ImageOptions(name, margin+(contentWidth-width)/2, pdf.GetY(), width, height, false,
+				gofpdf.ImageOptions{ImageType: "JPG"}, 0, "")
+			pdf.SetY(pdf.GetY() + height)
+		}
+	}
+
+	if description := strings.TrimSpace(recipe.Description); description != "" {
+		pdf.Ln(5)
+		pdf.SetFont(fontFamily, "", 11)
+		pdf.MultiCell(0, lineHeight, description, "", "L", false)
+	}
+
+	if len(recipe.Ingredients) > 0 {
+		b.heading("Ингредиенты")
+		pdf.SetFont(fontFamily, "", 11)
+		for _, line := range recipe.Ingredients {
+			b.listItem("•", line)
+		}
+	}
+
+	if steps := schemaorg.Steps(recipe.Instructions); len(steps) > 0 {
+		b.heading("Приготовление")
+		pdf.SetFont(fontFamily, "", 11)
+		for i, step := range steps {
+			b.listItem(fmt.Sprintf("%d.", i+1), step)
+			pdf.Ln(1.5)
+		}
+	}
+
+	return page
+}
+
+func (b *builder) heading(text string) {
+	pdf := b.pdf
+	pdf.Ln(6)
+	// Заголовок не остаётся последней строкой страницы
+	if pdf.GetY() > 297-margin-25 {
+		pdf.AddPage()
+	}
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.CellFormat(0, 8, text, "", 1, "L", false, 0, "")
+	pdf.Ln(1)
+}
+
+// listItem печатает пункт списка с висячим отступом: перенесённые строки выровнены по тексту, а не по маркеру.
+func (b *builder) listItem(marker, text string) {
+	pdf := b.pdf
+	pdf.SetX(margin)
+	pdf.CellFormat(numberWidth, lineHeight, marker, "", 0, "L", false, 0, "")
+	pdf.SetLeftMargin(margin + numberWidth)
+	pdf.MultiCell(0, lineHeight, text, "", "L", false)
+	pdf.SetLeftMargin(margin)
+}
+
+// registerImage перекодирует картинку в JPEG (парсер PNG в gofpdf поддерживает не все варианты формата)
+// и возвращает размеры, вписанные в maxWidth × maxHeight. Нераспознанная картинка пропускается.
+func (b *builder) registerImage(name string, data []byte, maxWidth, maxHeight float64) (string, float64, float64, bool) {
+	if len(data) == 0 {
+		return "", 0, 0, false
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", 0, 0, false
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return "", 0, 0, false
+	}
+
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: 85}); err != nil {
+		return "", 0, 0, false
+	}
+	b.pdf.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: "JPG"}, &buffer)
+
+	width := maxWidth
+	height := width * float64(bounds.Dy()) / float64(bounds.Dx())
+	if height > maxHeight {
+		height = maxHeight
+		width = height * float64(bounds.Dx()) / float64(bounds.Dy())
+	}
+	return name, width, height, true
+}
+
+// fitText укорачивает строку с многоточием, чтобы она поместилась в ширину width.
+func fitText(pdf *gofpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "…"
+}
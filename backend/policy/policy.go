@@ -0,0 +1,149 @@
+package policy
+
+import (
+	"culinary-book/backend/models"
+)
+
+type Action string
+
+const (
+	ActionRead     Action = "read"
+	ActionWrite    Action = "write"
+	ActionDelete   Action = "delete"
+	ActionFavorite Action = "favorite"
+	ActionShare    Action = "share"
+	ActionReview   Action = "review"
+	ActionComment  Action = "comment"
+	ActionModerate Action = "moderate"
+	ActionFork     Action = "fork"
+)
+
+// Principal описывает, от чьего имени выполняется запрос:
+// вошедший пользователь, аноним или владелец ссылки на конкретный рецепт.
+// CookbookRoles хранит роли пользователя в общих книгах: cookbook_id → роль.
+type Principal struct {
+	UserID        int
+	ShareRecipeID int
+	CookbookRoles map[int]string
+}
+
+func Anonymous() Principal {
+	return Principal{}
+}
+
+func User(userID int) Principal {
+	return Principal{UserID: userID}
+}
+
+func ShareLink(recipeID int) Principal {
+	return Principal{ShareRecipeID: recipeID}
+}
+
+func (p Principal) WithCookbookRoles(roles map[int]string) Principal {
+	p.CookbookRoles = roles
+	return p
+}
+
+func (p Principal) IsAuthenticated() bool {
+	return p.UserID != 0
+}
+
+func (p Principal) owns(recipe *models.Recipe) bool {
+	return p.IsAuthenticated() && recipe.UserID == p.UserID
+}
+
+// CookbookRole возвращает роль в книге или пустую строку, если пользователь в ней не состоит.
+func (p Principal) CookbookRole(cookbookID int) string {
+	if !p.IsAuthenticated() {
+		return ""
+	}
+	return p.CookbookRoles[cookbookID]
+}
+
+func (p Principal) recipeRole(recipe *models.Recipe) string {
+	if recipe.CookbookID == nil {
+		return ""
+	}
+	return p.CookbookRole(*recipe.CookbookID)
+}
+
+func canEditInRole(role string) bool {
+	return role == models.CookbookRoleOwner || role == models.CookbookRoleEditor
+}
+
+func Can(p Principal, action Action, recipe *models.Recipe) bool {
+	if recipe == nil {
+		return false
+	}
+
+	switch action {
+	case ActionRead:
+		return canRead(p, recipe)
+	case ActionFavorite, ActionReview, ActionComment:
+		return p.IsAuthenticated() && canRead(p, recipe)
+	case ActionFork:
+		return p.IsAuthenticated() && !p.owns(recipe) && canRead(p, recipe)
+	case ActionWrite, ActionDelete:
+		return p.owns(recipe) || canEditInRole(p.recipeRole(recipe))
+	case ActionShare, ActionModerate:
+		return p.owns(recipe) || p.recipeRole(recipe) == models.CookbookRoleOwner
+	}
+
+	return false
+}
+
+func canRead(p Principal, recipe *models.Recipe) bool {
+	if p.owns(recipe) {
+		return true
+	}
+
+	if p.ShareRecipeID != 0 && p.ShareRecipeID == recipe.ID {
+		return true
+	}
+
+	if p.recipeRole(recipe) != "" {
+		return true
+	}
+
+	switch recipe.Visibility {
+	case models.VisibilityPublic, models.VisibilityUnlisted:
+		return true
+	}
+
+	return false
+}
+
+func CanEditComment(p Principal, comment *models.Comment) bool {
+	return p.IsAuthenticated() && !comment.Deleted && comment.UserID == p.UserID
+}
+
+// CanDeleteComment разрешает удаление автору комментария и владельцу рецепта.
+func CanDeleteComment(p Principal, comment *models.Comment, recipe *models.Recipe) bool {
+	if !p.IsAuthenticated() || comment.Deleted {
+		return false
+	}
+	return comment.UserID == p.UserID || Can(p, ActionModerate, recipe)
+}
+
+func FilterReadable(p Principal, recipes []models.Recipe) []models.Recipe {
+	readable := make([]models.Recipe, 0, len(recipes))
+	for i := range recipes {
+		if Can(p, ActionRead, &recipes[i]) {
+			readable = append(readable, recipes[i])
+		}
+	}
+	return readable
+}
+
+func CanViewCookbook(p Principal, cookbookID int) bool {
+	return p.CookbookRole(cookbookID) != ""
+}
+
+// CanAddToCookbook разрешает добавлять и переносить рецепты в книгу владельцу и редакторам.
+func CanAddToCookbook(p Principal, cookbookID int) bool {
+	return canEditInRole(p.CookbookRole(cookbookID))
+}
+
+func CanManageCookbook(p Principal, cookbookID int) bool {
+	return p.CookbookRole(cookbookID) == models.CookbookRoleOwner
+}
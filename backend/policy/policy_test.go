@@ -0,0 +1,186 @@
+package policy
+
+import (
+	"testing"
+
+	"culinary-book/backend/models"
+)
+
+const (
+	ownerID    = 1
+	otherID    = 2
+	recipeID   = 10
+	cookbookID = 5
+)
+
+var allActions = []Action{
+	ActionRead, ActionWrite, ActionDelete, ActionFavorite, ActionShare,
+	ActionReview, ActionComment, ActionFork, ActionModerate,
+}
+
+func testRecipe(visibility string) *models.Recipe {
+	id := cookbookID
+	return &models.Recipe{ID: recipeID, UserID: ownerID, Visibility: visibility, CookbookID: &id}
+}
+
+func cookbookMember(userID int, role string) Principal {
+	return User(userID).WithCookbookRoles(map[int]string{cookbookID: role})
+}
+
+func actions(list ...Action) map[Action]bool {
+	set := make(map[Action]bool)
+	for _, action := range list {
+		set[action] = true
+	}
+	return set
+}
+
+func TestCanMatrix(t *testing.T) {
+	var (
+		none   = actions()
+		owner  = actions(ActionRead, ActionWrite, ActionDelete, ActionFavorite, ActionShare, ActionReview, ActionComment, ActionModerate)
+		reader = actions(ActionRead, ActionFavorite, ActionReview, ActionComment, ActionFork)
+		anon   = actions(ActionRead)
+		editor = actions(ActionRead, ActionWrite, ActionDelete, ActionFavorite, ActionReview, ActionComment, ActionFork)
+		all    = actions(allActions...)
+	)
+
+	principals := []struct {
+		name      string
+		principal Principal
+		// Разрешённые действия для private, unlisted и public
+		allowed [3]map[Action]bool
+	}{
+		{"owner", User(ownerID), [3]map[Action]bool{owner, owner, owner}},
+		{"other user", User(otherID), [3]map[Action]bool{none, reader, reader}},
+		{"anonymous", Anonymous(), [3]map[Action]bool{none, anon, anon}},
+		{"share link", ShareLink(recipeID), [3]map[Action]bool{anon, anon, anon}},
+		{"share link to another recipe", ShareLink(recipeID + 1), [3]map[Action]bool{none, anon, anon}},
+		{"cookbook owner", cookbookMember(3, models.CookbookRoleOwner), [3]map[Action]bool{all, all, all}},
+		{"cookbook editor", cookbookMember(4, models.CookbookRoleEditor), [3]map[Action]bool{editor, editor, editor}},
+		{"cookbook viewer", cookbookMember(5, models.CookbookRoleViewer), [3]map[Action]bool{reader, reader, reader}},
+		{"member of another cookbook", User(otherID).WithCookbookRoles(map[int]string{cookbookID + 1: models.CookbookRoleOwner}),
+			[3]map[Action]bool{none, reader, reader}},
+	}
+	visibilities := [3]string{models.VisibilityPrivate, models.VisibilityUnlisted, models.VisibilityPublic}
+
+	for _, tc := range principals {
+		for i, visibility := range visibilities {
+			recipe := testRecipe(visibility)
+			for _, action := range allActions {
+				want := tc.allowed[i][action]
+				if got := Can(tc.principal, action, recipe); got != want {
+					t.Errorf("%s, %s recipe, %s: got %v, want %v", tc.name, visibility, action, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestCanNilRecipe(t *testing.T) {
+	for _, action := range allActions {
+		if Can(User(ownerID), action, nil) {
+			t.Errorf("%s on nil recipe must be denied", action)
+		}
+	}
+}
+
+func TestCanRecipeOutsideCookbook(t *testing.T) {
+	recipe := &models.Recipe{ID: recipeID, UserID: ownerID, Visibility: models.VisibilityPrivate}
+	member := cookbookMember(3, models.CookbookRoleOwner)
+	for _, action := range allActions {
+		if Can(member, action, recipe) {
+			t.Errorf("cookbook role must not grant %s on a recipe outside the cookbook", action)
+		}
+	}
+}
+
+func TestFilterReadable(t *testing.T) {
+	recipes := []models.Recipe{
+		{ID: 1, UserID: ownerID, Visibility: models.VisibilityPrivate},
+		{ID: 2, UserID: ownerID, Visibility: models.VisibilityUnlisted},
+		{ID: 3, UserID: ownerID, Visibility: models.VisibilityPublic},
+		{ID: 4, UserID: otherID, Visibility: models.VisibilityPrivate},
+	}
+
+	tests := []struct {
+		name      string
+		principal Principal
+		want      []int
+	}{
+		{"owner", User(ownerID), []int{1, 2, 3}},
+		{"other user", User(otherID), []int{2, 3, 4}},
+		{"anonymous", Anonymous(), []int{2, 3}},
+		{"share link", ShareLink(1), []int{1, 2, 3}},
+	}
+
+	for _, tc := range tests {
+		got := FilterReadable(tc.principal, recipes)
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: got %d recipes, want %v", tc.name, len(got), tc.want)
+			continue
+		}
+		for i, recipe := range got {
+			if recipe.ID != tc.want[i] {
+				t.Errorf("%s: got recipe %d at %d, want %d", tc.name, recipe.ID, i, tc.want[i])
+			}
+		}
+	}
+}
+
+func TestCookbookPermissions(t *testing.T) {
+	tests := []struct {
+		name                 string
+		principal            Principal
+		view, add, canManage bool
+	}{
+		{"owner", cookbookMember(3, models.CookbookRoleOwner), true, true, true},
+		{"editor", cookbookMember(4, models.CookbookRoleEditor), true, true, false},
+		{"viewer", cookbookMember(5, models.CookbookRoleViewer), true, false, false},
+		{"not a member", User(otherID), false, false, false},
+		{"anonymous with roles", Anonymous().WithCookbookRoles(map[int]string{cookbookID: models.CookbookRoleOwner}), false, false, false},
+	}
+
+	for _, tc := range tests {
+		if got := CanViewCookbook(tc.principal, cookbookID); got != tc.view {
+			t.Errorf("%s: CanViewCookbook = %v, want %v", tc.name, got, tc.view)
+		}
+		if got := CanAddToCookbook(tc.principal, cookbookID); got != tc.add {
+			t.Errorf("%s: CanAddToCookbook = %v, want %v", tc.name, got, tc.add)
+		}
+		if got := CanManageCookbook(tc.principal, cookbookID); got != tc.canManage {
+			t.Errorf("%s: CanManageCookbook = %v, want %v", tc.name, got, tc.canManage)
+		}
+	}
+}
+
+func TestCommentPermissions(t *testing.T) {
+	recipe := testRecipe(models.VisibilityPublic)
+	comment := &models.Comment{UserID: otherID}
+	deleted := &models.Comment{UserID: otherID, Deleted: true}
+
+	tests := []struct {
+		name         string
+		principal    Principal
+		comment      *models.Comment
+		edit, remove bool
+	}{
+		{"author", User(otherID), comment, true, true},
+		{"recipe owner", User(ownerID), comment, false, true},
+		{"cookbook owner", cookbookMember(3, models.CookbookRoleOwner), comment, false, true},
+		{"cookbook editor", cookbookMember(4, models.CookbookRoleEditor), comment, false, false},
+		{"stranger", User(6), comment, false, false},
+		{"anonymous", Anonymous(), comment, false, false},
+		{"author of deleted comment", User(otherID), deleted, false, false},
+		{"recipe owner, deleted comment", User(ownerID), deleted, false, false},
+	}
+
+	for _, tc := range tests {
+		if got := CanEditComment(tc.principal, tc.comment); got != tc.edit {
+			t.Errorf("%s: CanEditComment = %v, want %v", tc.name, got, tc.edit)
+		}
+		if got := CanDeleteComment(tc.principal, tc.comment, recipe); got != tc.remove {
+			t.Errorf("%s: CanDeleteComment = %v, want %v", tc.name, got, tc.remove)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type CollectionRepository struct {
+	db *pgx.Conn
+}
+
+func NewCollectionRepository(db *pgx.Conn) *CollectionRepository {
+	return &CollectionRepository{db: db}
+}
+
+func (r *CollectionRepository) CreateCollection(collection *models.Collection) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO collections (user_id, name, description, cover_base64, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4,
+		        (SELECT COALESCE(MAX(position) + 1, 0) FROM collections WHERE user_id = $1), $5, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		collection.UserID,
+		collection.Name,
+		collection.Description,
+		collection.CoverBase64,
+		time.Now(),
+	).Scan(&collection.ID, &collection.CreatedAt, &collection.UpdatedAt)
+}
+
+func (r *CollectionRepository) UpdateCollection(collection *models.Collection) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE collections
+		SET name = $1, description = $2, cover_base64 = $3, updated_at = $4
+		WHERE id = $5 AND user_id = $6
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		collection.Name,
+		collection.Description,
+		collection.CoverBase64,
+		time.Now(),
+		collection.ID,
+		collection.UserID,
+	).Scan(&collection.CreatedAt, &collection.UpdatedAt)
+
+	if err == pgx.ErrNoRows {
+		return errors.New("коллекция не найдена")
+	}
+	return err
+}
+
+func (r *CollectionRepository) DeleteCollection(userID, collectionID int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `DELETE FROM collections WHERE id = $1 AND user_id = $2`, collectionID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("коллекция не найдена")
+	}
+
+	return nil
+}
+
+// GetCollections возвращает коллекции пользователя в ручном порядке.
+// Если передан recipeID, у каждой коллекции отмечается, входит ли в неё рецепт.
+func (r *CollectionRepository) GetCollections(userID, recipeID int) ([]models.Collection, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT c.id, c.user_id, c.name, COALESCE(c.description, ''), COALESCE(c.cover_base64, ''),
+		       (SELECT COUNT(*) FROM collection_recipes cr WHERE cr.collection_id = c.id),
+		       EXISTS(SELECT 1 FROM collection_recipes cr WHERE cr.collection_id = c.id AND cr.recipe_id = $2),
+		       c.created_at, c.updated_at
+		FROM collections c
+		WHERE c.user_id = $1
+		ORDER BY c.position, c.id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := []models.Collection{}
+	for rows.Next() {
+		var c models.Collection
+		err := rows.Scan(
+			&c.ID,
+			&c.UserID,
+			&c.Name,
+			&c.Description,
+			&c.CoverBase64,
+			&c.RecipeCount,
+			&c.Contains,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, c)
+	}
+
+	return collections, nil
+}
+
+func (r *CollectionRepository) IsOwner(userID, collectionID int) (bool, error) {
+	ctx := context.Background()
+
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM collections WHERE id = $1 AND user_id = $2
+	`, collectionID, userID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// SetCollectionsOrder сохраняет порядок коллекций в том виде, в каком его прислал клиент.
+func (r *CollectionRepository) SetCollectionsOrder(userID int, collectionIDs []int) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for position, collectionID := range collectionIDs {
+		_, err := tx.Exec(ctx, `
+			UPDATE collections SET position = $1 WHERE id = $2 AND user_id = $3
+		`, position, collectionID, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *CollectionRepository) AddRecipe(collectionID, recipeID int) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO collection_recipes (collection_id, recipe_id, added_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (collection_id, recipe_id) DO NOTHING
+	`
+
+	_, err := r.db.Exec(ctx, query, collectionID, recipeID, time.Now())
+	return err
+}
+
+func (r *CollectionRepository) RemoveRecipe(collectionID, recipeID int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM collection_recipes WHERE collection_id = $1 AND recipe_id = $2
+	`, collectionID, recipeID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("рецепт не найден в коллекции")
+	}
+
+	return nil
+}
+
+// GetRecipeIDs отдаёт рецепты коллекции: новые без позиции сверху, дальше в ручном порядке.
+func (r *CollectionRepository) GetRecipeIDs(collectionID int) ([]int, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT recipe_id FROM collection_recipes
+		WHERE collection_id = $1
+		ORDER BY position ASC NULLS FIRST, added_at DESC
+	`, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipeIDs []int
+	for rows.Next() {
+		var recipeID int
+		if err := rows.Scan(&recipeID); err != nil {
+			return nil, err
+		}
+		recipeIDs = append(recipeIDs, recipeID)
+	}
+
+	return recipeIDs, nil
+}
+
+func (r *CollectionRepository) SetRecipesOrder(collectionID int, recipeIDs []int) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for position, recipeID := range recipeIDs {
+		_, err := tx.Exec(ctx, `
+			UPDATE collection_recipes SET position = $1 WHERE collection_id = $2 AND recipe_id = $3
+		`, position, collectionID, recipeID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
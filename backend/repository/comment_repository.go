@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type CommentRepository struct {
+	db *pgx.Conn
+}
+
+func NewCommentRepository(db *pgx.Conn) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) CreateComment(comment *models.Comment) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO recipe_comments (recipe_id, user_id, parent_id, body, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		comment.RecipeID,
+		comment.UserID,
+		comment.ParentID,
+		comment.Body,
+		time.Now(),
+	).Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt)
+
+	return err
+}
+
+func (r *CommentRepository) GetCommentByID(commentID int) (*models.Comment, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT c.id, c.recipe_id, c.user_id, u.username, c.parent_id, c.body,
+		       c.hidden, c.pinned, c.deleted_at IS NOT NULL, c.created_at, c.updated_at
+		FROM recipe_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1
+	`
+
+	var comment models.Comment
+	err := r.db.QueryRow(ctx, query, commentID).Scan(
+		&comment.ID,
+		&comment.RecipeID,
+		&comment.UserID,
+		&comment.AuthorName,
+		&comment.ParentID,
+		&comment.Body,
+		&comment.Hidden,
+		&comment.Pinned,
+		&comment.Deleted,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("комментарий не найден")
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (r *CommentRepository) UpdateComment(commentID int, body string) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE recipe_comments
+		SET body = $1, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, body, time.Now(), commentID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("комментарий не найден")
+	}
+
+	return nil
+}
+
+// DeleteComment помечает комментарий удалённым и стирает текст,
+// чтобы ответы на него остались на своих местах в ветке.
+func (r *CommentRepository) DeleteComment(commentID int) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE recipe_comments
+		SET body = '', pinned = FALSE, deleted_at = $1
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, time.Now(), commentID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("комментарий не найден")
+	}
+
+	return nil
+}
+
+func (r *CommentRepository) SetModeration(commentID int, hidden, pinned *bool) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE recipe_comments
+		SET hidden = COALESCE($1, hidden), pinned = COALESCE($2, pinned)
+		WHERE id = $3
+	`
+
+	result, err := r.db.Exec(ctx, query, hidden, pinned, commentID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("комментарий не найден")
+	}
+
+	return nil
+}
+
+// GetThreads возвращает страницу веток: корневые комментарии (закреплённые первыми)
+// вместе со всеми ответами на них, собранными в дерево.
+func (r *CommentRepository) GetThreads(recipeID, limit, offset int) ([]models.Comment, int, error) {
+	ctx := context.Background()
+
+	var total int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM recipe_comments
+		WHERE recipe_id = $1 AND parent_id IS NULL
+	`, recipeID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		WITH RECURSIVE roots AS (
+			SELECT id FROM recipe_comments
+			WHERE recipe_id = $1 AND parent_id IS NULL
+			ORDER BY pinned DESC, created_at ASC, id ASC
+			LIMIT $2 OFFSET $3
+		), thread AS (
+			SELECT c.* FROM recipe_comments c JOIN roots ON roots.id = c.id
+			UNION ALL
+			SELECT c.* FROM recipe_comments c JOIN thread t ON c.parent_id = t.id
+		)
+		SELECT t.id, t.recipe_id, t.user_id, u.username, t.parent_id, t.body,
+		       t.hidden, t.pinned, t.deleted_at IS NOT NULL, t.created_at, t.updated_at
+		FROM thread t
+		JOIN users u ON u.id = t.user_id
+		ORDER BY t.pinned DESC, t.created_at ASC, t.id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, recipeID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var flat []models.Comment
+	for rows.Next() {
+		var comment models.Comment
+		err := rows.Scan(
+			&comment.ID,
+			&comment.RecipeID,
+			&comment.UserID,
+			&comment.AuthorName,
+			&comment.ParentID,
+			&comment.Body,
+			&comment.Hidden,
+			&comment.Pinned,
+			&comment.Deleted,
+			&comment.CreatedAt,
+			&comment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		flat = append(flat, comment)
+	}
+
+	return buildCommentTree(flat), total, nil
+}
+
+func buildCommentTree(flat []models.Comment) []models.Comment {
+	children := make(map[int][]models.Comment)
+	var roots []models.Comment
+	for _, comment := range flat {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+		} else {
+			children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+		}
+	}
+
+	var attach func(comment *models.Comment)
+	attach = func(comment *models.Comment) {
+		comment.Replies = children[comment.ID]
+		for i := range comment.Replies {
+			attach(&comment.Replies[i])
+		}
+	}
+
+	for i := range roots {
+		attach(&roots[i])
+	}
+
+	return roots
+}
+
+func (r *CommentRepository) MarkRead(userID, recipeID int) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO comment_reads (user_id, recipe_id, last_read_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, recipe_id) DO UPDATE SET last_read_at = EXCLUDED.last_read_at
+	`
+
+	_, err := r.db.Exec(ctx, query, userID, recipeID, time.Now())
+	return err
+}
+
+// GetUnreadCounts считает чужие новые комментарии к своим и избранным рецептам.
+// Скрытые комментарии считаются только для тех, кто может модерировать: остальные их не видят.
+func (r *CommentRepository) GetUnreadCounts(userID int) (map[int]int, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT c.recipe_id, COUNT(*)
+		FROM recipe_comments c
+		JOIN recipes rc ON rc.id = c.recipe_id
+		LEFT JOIN comment_reads cr ON cr.recipe_id = c.recipe_id AND cr.user_id = $1
+		WHERE c.user_id <> $1
+		  AND c.deleted_at IS NULL
+		  AND c.created_at > COALESCE(cr.last_read_at, 'epoch'::timestamp)
+		  AND (NOT c.hidden OR rc.user_id = $1 OR EXISTS (
+		      SELECT 1 FROM cookbook_members m
+		      WHERE m.cookbook_id = rc.cookbook_id AND m.user_id = $1 AND m.role = 'owner'))
+		  AND (rc.user_id = $1 OR (rc.visibility <> 'private' AND EXISTS (
+		      SELECT 1 FROM favorites f WHERE f.user_id = $1 AND f.recipe_id = c.recipe_id)))
+		GROUP BY c.recipe_id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var recipeID, count int
+		if err := rows.Scan(&recipeID, &count); err != nil {
+			return nil, err
+		}
+		counts[recipeID] = count
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type CookbookRepository struct {
+	db *pgx.Conn
+}
+
+func NewCookbookRepository(db *pgx.Conn) *CookbookRepository {
+	return &CookbookRepository{db: db}
+}
+
+// CreateCookbook создаёт книгу и сразу делает создателя её владельцем.
+func (r *CookbookRepository) CreateCookbook(cookbook *models.Cookbook) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO cookbooks (name, owner_id, created_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, cookbook.Name, cookbook.OwnerID, time.Now()).Scan(&cookbook.ID, &cookbook.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO cookbook_members (cookbook_id, user_id, role, joined_at)
+		VALUES ($1, $2, $3, $4)
+	`, cookbook.ID, cookbook.OwnerID, models.CookbookRoleOwner, cookbook.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	cookbook.Role = models.CookbookRoleOwner
+	cookbook.MemberCount = 1
+
+	return tx.Commit(ctx)
+}
+
+func (r *CookbookRepository) RenameCookbook(cookbookID int, name string) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `UPDATE cookbooks SET name = $1 WHERE id = $2`, name, cookbookID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("книга не найдена")
+	}
+
+	return nil
+}
+
+// DeleteCookbook удаляет книгу; её рецепты возвращаются авторам как личные.
+// DeleteCookbook удаляет книгу; её рецепты остаются у авторов (cookbook_id = NULL).
+// Возвращает ID этих рецептов.
+func (r *CookbookRepository) DeleteCookbook(cookbookID int) ([]int, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT id FROM recipes WHERE cookbook_id = $1`, cookbookID)
+	if err != nil {
+		return nil, err
+	}
+	var recipeIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		recipeIDs = append(recipeIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM cookbooks WHERE id = $1`, cookbookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, errors.New("книга не найдена")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return recipeIDs, nil
+}
+
+func (r *CookbookRepository) GetUserCookbooks(userID int) ([]models.Cookbook, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT c.id, c.name, c.owner_id, m.role,
+		       (SELECT COUNT(*) FROM cookbook_members cm WHERE cm.cookbook_id = c.id),
+		       (SELECT COUNT(*) FROM recipes rc WHERE rc.cookbook_id = c.id),
+		       c.created_at
+		FROM cookbooks c
+		JOIN cookbook_members m ON m.cookbook_id = c.id AND m.user_id = $1
+		ORDER BY c.name
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cookbooks := []models.Cookbook{}
+	for rows.Next() {
+		var c models.Cookbook
+		err := rows.Scan(&c.ID, &c.Name, &c.OwnerID, &c.Role, &c.MemberCount, &c.RecipeCount, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		cookbooks = append(cookbooks, c)
+	}
+
+	return cookbooks, nil
+}
+
+// GetUserRoles возвращает роли пользователя во всех его книгах: cookbook_id → роль.
+func (r *CookbookRepository) GetUserRoles(userID int) (map[int]string, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT cookbook_id, role FROM cookbook_members WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make(map[int]string)
+	for rows.Next() {
+		var cookbookID int
+		var role string
+		if err := rows.Scan(&cookbookID, &role); err != nil {
+			return nil, err
+		}
+		roles[cookbookID] = role
+	}
+
+	return roles, nil
+}
+
+func (r *CookbookRepository) GetMembers(cookbookID int) ([]models.CookbookMember, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT m.user_id, u.username, m.role, m.joined_at
+		FROM cookbook_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.cookbook_id = $1
+		ORDER BY m.joined_at
+	`
+
+	rows, err := r.db.Query(ctx, query, cookbookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.CookbookMember{}
+	for rows.Next() {
+		var m models.CookbookMember
+		if err := rows.Scan(&m.UserID, &m.Username, &m.Role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	return members, nil
+}
+
+func (r *CookbookRepository) SetMemberRole(cookbookID, userID int, role string) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE cookbook_members SET role = $1
+		WHERE cookbook_id = $2 AND user_id = $3 AND role <> $4
+	`, role, cookbookID, userID, models.CookbookRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("участник не найден")
+	}
+
+	return nil
+}
+
+func (r *CookbookRepository) RemoveMember(cookbookID, userID int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM cookbook_members
+		WHERE cookbook_id = $1 AND user_id = $2 AND role <> $3
+	`, cookbookID, userID, models.CookbookRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("участник не найден")
+	}
+
+	return nil
+}
+
+func (r *CookbookRepository) CreateInvite(invite *models.CookbookInvite, inviterID int) error {
+	ctx := context.Background()
+
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM cookbook_members WHERE cookbook_id = $1 AND user_id = $2)
+	`, invite.CookbookID, invite.InviteeID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("пользователь уже участвует в книге")
+	}
+
+	query := `
+		INSERT INTO cookbook_invites (cookbook_id, inviter_id, invitee_id, role, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (cookbook_id, invitee_id) DO UPDATE SET role = EXCLUDED.role, inviter_id = EXCLUDED.inviter_id
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		invite.CookbookID,
+		inviterID,
+		invite.InviteeID,
+		invite.Role,
+		time.Now(),
+	).Scan(&invite.ID, &invite.CreatedAt)
+}
+
+func (r *CookbookRepository) GetPendingInvites(userID int) ([]models.CookbookInvite, error) {
+	return r.listInvites(`
+		SELECT i.id, i.cookbook_id, c.name, u.username, i.invitee_id, '', i.role, i.created_at
+		FROM cookbook_invites i
+		JOIN cookbooks c ON c.id = i.cookbook_id
+		JOIN users u ON u.id = i.inviter_id
+		WHERE i.invitee_id = $1
+		ORDER BY i.created_at DESC
+	`, userID)
+}
+
+func (r *CookbookRepository) GetCookbookInvites(cookbookID int) ([]models.CookbookInvite, error) {
+	return r.listInvites(`
+		SELECT i.id, i.cookbook_id, c.name, u.username, i.invitee_id, iu.username, i.role, i.created_at
+		FROM cookbook_invites i
+		JOIN cookbooks c ON c.id = i.cookbook_id
+		JOIN users u ON u.id = i.inviter_id
+		JOIN users iu ON iu.id = i.invitee_id
+		WHERE i.cookbook_id = $1
+		ORDER BY i.created_at DESC
+	`, cookbookID)
+}
+
+func (r *CookbookRepository) listInvites(query string, id int) ([]models.CookbookInvite, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invites := []models.CookbookInvite{}
+	for rows.Next() {
+		var i models.CookbookInvite
+		err := rows.Scan(&i.ID, &i.CookbookID, &i.CookbookName, &i.InviterName,
+			&i.InviteeID, &i.InviteeName, &i.Role, &i.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, i)
+	}
+
+	return invites, nil
+}
+
+func (r *CookbookRepository) GetInviteByID(inviteID int) (*models.CookbookInvite, error) {
+	ctx := context.Background()
+
+	var i models.CookbookInvite
+	err := r.db.QueryRow(ctx, `
+		SELECT i.id, i.cookbook_id, c.name, i.invitee_id, i.role, i.created_at
+		FROM cookbook_invites i
+		JOIN cookbooks c ON c.id = i.cookbook_id
+		WHERE i.id = $1
+	`, inviteID).Scan(&i.ID, &i.CookbookID, &i.CookbookName, &i.InviteeID, &i.Role, &i.CreatedAt)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("приглашение не найдено")
+		}
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+// RespondInvite принимает или отклоняет приглашение; в обоих случаях оно удаляется.
+func (r *CookbookRepository) RespondInvite(invite *models.CookbookInvite, accept bool) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if accept {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO cookbook_members (cookbook_id, user_id, role, joined_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (cookbook_id, user_id) DO NOTHING
+		`, invite.CookbookID, invite.InviteeID, invite.Role, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM cookbook_invites WHERE id = $1`, invite.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *CookbookRepository) CancelInvite(cookbookID, inviteID int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM cookbook_invites WHERE id = $1 AND cookbook_id = $2
+	`, inviteID, cookbookID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("приглашение не найдено")
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type DietProfileRepository struct {
+	db *pgx.Conn
+}
+
+func NewDietProfileRepository(db *pgx.Conn) *DietProfileRepository {
+	return &DietProfileRepository{db: db}
+}
+
+func (r *DietProfileRepository) GetProfiles(userID int) ([]models.DietProfile, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, user_id, name, avoid_labels, require_labels, created_at
+		FROM diet_profiles
+		WHERE user_id = $1
+		ORDER BY name, id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles := []models.DietProfile{}
+	for rows.Next() {
+		var profile models.DietProfile
+		if err := rows.Scan(&profile.ID, &profile.UserID, &profile.Name,
+			&profile.Avoid, &profile.Require, &profile.CreatedAt); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+func (r *DietProfileRepository) CreateProfile(profile *models.DietProfile) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO diet_profiles (user_id, name, avoid_labels, require_labels, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		profile.UserID,
+		profile.Name,
+		nonNilLabels(profile.Avoid),
+		nonNilLabels(profile.Require),
+		time.Now(),
+	).Scan(&profile.ID, &profile.CreatedAt)
+}
+
+func (r *DietProfileRepository) UpdateProfile(profile *models.DietProfile) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE diet_profiles
+		SET name = $1, avoid_labels = $2, require_labels = $3
+		WHERE id = $4 AND user_id = $5
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		profile.Name,
+		nonNilLabels(profile.Avoid),
+		nonNilLabels(profile.Require),
+		profile.ID,
+		profile.UserID,
+	).Scan(&profile.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("профиль не найден")
+	}
+
+	return err
+}
+
+func (r *DietProfileRepository) DeleteProfile(userID, profileID int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM diet_profiles WHERE id = $1 AND user_id = $2
+	`, profileID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("профиль не найден")
+	}
+
+	return nil
+}
+
+// nonNilLabels нужен, чтобы пустой список сохранялся как '{}', а не NULL.
+func nonNilLabels(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
@@ -55,7 +55,7 @@ func (r *FavoriteRepository) GetFavoriteRecipes(userID int) ([]int, error) {
 	query := `
 		SELECT recipe_id FROM favorites
 		WHERE user_id = $1
-		ORDER BY created_at DESC
+		ORDER BY position ASC NULLS FIRST, created_at DESC
 	`
 
 	rows, err := r.db.Query(ctx, query, userID)
@@ -92,3 +92,33 @@ func (r *FavoriteRepository) IsFavorite(userID, recipeID int) (bool, error) {
 
 	return count > 0, nil
 }
+
+// SetOrder задаёт ручной порядок избранного; рецепты вне списка остаются наверху как новые.
+func (r *FavoriteRepository) SetOrder(userID int, recipeIDs []int) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for position, recipeID := range recipeIDs {
+		_, err := tx.Exec(ctx, `
+			UPDATE favorites SET position = $1 WHERE user_id = $2 AND recipe_id = $3
+		`, position, userID, recipeID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *FavoriteRepository) CountFavorites(userID int) (int, error) {
+	ctx := context.Background()
+
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM favorites WHERE user_id = $1`, userID).Scan(&count)
+	return count, err
+}
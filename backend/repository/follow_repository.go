@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// feedBackfillSize — сколько последних публичных рецептов автора
+// попадает в ленту сразу после подписки.
+const feedBackfillSize = 20
+
+type FollowRepository struct {
+	db *pgx.Conn
+}
+
+func NewFollowRepository(db *pgx.Conn) *FollowRepository {
+	return &FollowRepository{db: db}
+}
+
+func (r *FollowRepository) Follow(followerID, followeeID int) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		INSERT INTO follows (follower_id, followee_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+	`, followerID, followeeID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("вы уже подписаны на этого пользователя")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO feed_items (user_id, actor_id, kind, recipe_id, created_at)
+		SELECT $1, r.user_id, $3, r.id, r.created_at
+		FROM recipes r
+		WHERE r.user_id = $2 AND r.visibility = 'public'
+		ORDER BY r.created_at DESC
+		LIMIT $4
+	`, followerID, followeeID, models.FeedRecipeCreated, feedBackfillSize)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *FollowRepository) Unfollow(followerID, followeeID int) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2
+	`, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("вы не подписаны на этого пользователя")
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM feed_items WHERE user_id = $1 AND actor_id = $2
+	`, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *FollowRepository) GetFollowers(userID int) ([]models.UserSummary, error) {
+	return r.listUsers(`
+		SELECT u.id, u.username, f.created_at
+		FROM follows f
+		JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = $1
+		ORDER BY f.created_at DESC
+	`, userID)
+}
+
+func (r *FollowRepository) GetFollowing(userID int) ([]models.UserSummary, error) {
+	return r.listUsers(`
+		SELECT u.id, u.username, f.created_at
+		FROM follows f
+		JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1
+		ORDER BY f.created_at DESC
+	`, userID)
+}
+
+func (r *FollowRepository) listUsers(query string, userID int) ([]models.UserSummary, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.UserSummary{}
+	for rows.Next() {
+		var user models.UserSummary
+		if err := rows.Scan(&user.ID, &user.Username, &user.FollowedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// Publish раскладывает событие по лентам всех подписчиков автора (fan-out on write).
+// Повторные правки и отзывы к тому же рецепту заменяют прежнюю запись,
+// чтобы частые сохранения не засоряли ленту.
+func (r *FollowRepository) Publish(actorID int, kind string, recipeID int) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if kind != models.FeedRecipeCreated {
+		_, err = tx.Exec(ctx, `
+			DELETE FROM feed_items WHERE actor_id = $1 AND recipe_id = $2 AND kind = $3
+		`, actorID, recipeID, kind)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO feed_items (user_id, actor_id, kind, recipe_id, created_at)
+		SELECT follower_id, $1, $2, $3, $4
+		FROM follows
+		WHERE followee_id = $1
+	`, actorID, kind, recipeID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetFeed отдаёт ленту в обратном хронологическом порядке.
+// Курсор before — время и id последней полученной записи: выдаются записи строго раньше
+// неё по (created_at, id), даже если сама запись уже удалена. Нулевой курсор означает начало ленты.
+func (r *FollowRepository) GetFeed(userID int, before models.FeedCursor, limit int) ([]models.FeedItem, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT fi.id, fi.kind, fi.actor_id, u.username, fi.recipe_id, rc.title,
+		       COALESCE(rv.rating, 0), COALESCE(rv.text, ''), fi.created_at
+		FROM feed_items fi
+		JOIN users u ON u.id = fi.actor_id
+		JOIN recipes rc ON rc.id = fi.recipe_id
+		LEFT JOIN recipe_reviews rv ON fi.kind = $4 AND rv.recipe_id = fi.recipe_id AND rv.user_id = fi.actor_id
+		WHERE fi.user_id = $1
+		  AND ($5 = 0 OR (fi.created_at, fi.id) < ($2::timestamp, $5))
+		  AND rc.visibility = 'public'
+		  AND (fi.kind <> $4 OR rv.id IS NOT NULL)
+		ORDER BY fi.created_at DESC, fi.id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, before.CreatedAt, limit, models.FeedReview, before.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.FeedItem{}
+	for rows.Next() {
+		var item models.FeedItem
+		err := rows.Scan(
+			&item.ID,
+			&item.Kind,
+			&item.ActorID,
+			&item.ActorName,
+			&item.RecipeID,
+			&item.RecipeTitle,
+			&item.Rating,
+			&item.ReviewText,
+			&item.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
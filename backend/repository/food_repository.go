@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"culinary-book/backend/models"
+	"culinary-book/backend/nutrition"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type FoodRepository struct {
+	db *pgx.Conn
+}
+
+func NewFoodRepository(db *pgx.Conn) *FoodRepository {
+	return &FoodRepository{db: db}
+}
+
+const foodColumns = `id, name, COALESCE(aliases, ''), kcal, protein, fat, carbs, fiber, piece_grams, density`
+
+func scanFood(row pgx.Row) (nutrition.Food, error) {
+	var food nutrition.Food
+	var aliases string
+
+	err := row.Scan(
+		&food.ID,
+		&food.Name,
+		&aliases,
+		&food.Per100g.Calories,
+		&food.Per100g.Protein,
+		&food.Per100g.Fat,
+		&food.Per100g.Carbs,
+		&food.Per100g.Fiber,
+		&food.PieceGrams,
+		&food.Density,
+	)
+	if aliases != "" {
+		food.Aliases = strings.Split(aliases, "|")
+	}
+
+	return food, err
+}
+
+// ImportFoods добавляет продукты из таблицы; продукты с тем же названием обновляются.
+func (r *FoodRepository) ImportFoods(foods []nutrition.Food) (int, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO foods (name, aliases, kcal, protein, fat, carbs, fiber, piece_grams, density, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (name) DO UPDATE
+		SET aliases = EXCLUDED.aliases, kcal = EXCLUDED.kcal, protein = EXCLUDED.protein,
+		    fat = EXCLUDED.fat, carbs = EXCLUDED.carbs, fiber = EXCLUDED.fiber,
+		    piece_grams = EXCLUDED.piece_grams, density = EXCLUDED.density, updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	for _, food := range foods {
+		_, err := tx.Exec(ctx, query,
+			food.Name,
+			strings.Join(food.Aliases, "|"),
+			food.Per100g.Calories,
+			food.Per100g.Protein,
+			food.Per100g.Fat,
+			food.Per100g.Carbs,
+			food.Per100g.Fiber,
+			food.PieceGrams,
+			food.Density,
+			now,
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(foods), tx.Commit(ctx)
+}
+
+func (r *FoodRepository) GetFoods() ([]nutrition.Food, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, `SELECT `+foodColumns+` FROM foods ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	foods := []nutrition.Food{}
+	for rows.Next() {
+		food, err := scanFood(rows)
+		if err != nil {
+			return nil, err
+		}
+		foods = append(foods, food)
+	}
+
+	return foods, nil
+}
+
+func (r *FoodRepository) SearchFoods(search string, limit int) ([]nutrition.Food, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + foodColumns + `
+		FROM foods
+		WHERE name ILIKE '%' || $1 || '%' OR aliases ILIKE '%' || $1 || '%'
+		ORDER BY POSITION(LOWER($1) IN name), name
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, search, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	foods := []nutrition.Food{}
+	for rows.Next() {
+		food, err := scanFood(rows)
+		if err != nil {
+			return nil, err
+		}
+		foods = append(foods, food)
+	}
+
+	return foods, nil
+}
+
+func (r *FoodRepository) GetFoodByID(foodID int) (*nutrition.Food, error) {
+	ctx := context.Background()
+
+	food, err := scanFood(r.db.QueryRow(ctx, `SELECT `+foodColumns+` FROM foods WHERE id = $1`, foodID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("продукт не найден")
+		}
+		return nil, err
+	}
+
+	return &food, nil
+}
+
+// GetOverrides собирает сопоставления нескольких пользователей по ключу ингредиента;
+// сопоставления пользователей, идущих позже в списке, перекрывают более ранние.
+func (r *FoodRepository) GetOverrides(userIDs ...int) (map[string]models.FoodOverride, error) {
+	ctx := context.Background()
+
+	overrides := make(map[string]models.FoodOverride)
+	for _, userID := range userIDs {
+		if userID == 0 {
+			continue
+		}
+
+		rows, err := r.db.Query(ctx, `
+			SELECT o.user_id, o.ingredient, o.name_key, o.food_id, f.name, o.grams
+			FROM food_overrides o
+			JOIN foods f ON f.id = o.food_id
+			WHERE o.user_id = $1
+		`, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var override models.FoodOverride
+			err := rows.Scan(
+				&override.UserID,
+				&override.Ingredient,
+				&override.NameKey,
+				&override.FoodID,
+				&override.FoodName,
+				&override.Grams,
+			)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			overrides[override.NameKey] = override
+		}
+		rows.Close()
+	}
+
+	return overrides, nil
+}
+
+func (r *FoodRepository) SetOverride(override *models.FoodOverride) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO food_overrides (user_id, ingredient, name_key, food_id, grams, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, name_key) DO UPDATE
+		SET ingredient = EXCLUDED.ingredient, food_id = EXCLUDED.food_id, grams = EXCLUDED.grams
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		override.UserID,
+		override.Ingredient,
+		override.NameKey,
+		override.FoodID,
+		override.Grams,
+		time.Now(),
+	)
+	return err
+}
+
+func (r *FoodRepository) DeleteOverride(userID int, nameKey string) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `DELETE FROM food_overrides WHERE user_id = $1 AND name_key = $2`, userID, nameKey)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("сопоставление не найдено")
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type MealPlanRepository struct {
+	db *pgx.Conn
+}
+
+func NewMealPlanRepository(db *pgx.Conn) *MealPlanRepository {
+	return &MealPlanRepository{db: db}
+}
+
+const mealPlanColumns = `
+	m.id, m.user_id, m.plan_date, m.slot, m.recipe_id, COALESCE(r.title, ''),
+	m.servings, COALESCE(m.note, ''), m.position, m.created_at
+`
+
+func scanMealPlanEntry(row pgx.Row) (models.MealPlanEntry, error) {
+	var entry models.MealPlanEntry
+	var date time.Time
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.UserID,
+		&date,
+		&entry.Slot,
+		&entry.RecipeID,
+		&entry.RecipeTitle,
+		&entry.Servings,
+		&entry.Note,
+		&entry.Position,
+		&entry.CreatedAt,
+	)
+	entry.Date = date.Format(models.DateLayout)
+
+	return entry, err
+}
+
+// GetEntries возвращает план за период включительно, упорядоченный по дням и приёмам пищи.
+func (r *MealPlanRepository) GetEntries(userID int, from, to time.Time) ([]models.MealPlanEntry, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + mealPlanColumns + `
+		FROM meal_plan_entries m
+		LEFT JOIN recipes r ON r.id = m.recipe_id
+		WHERE m.user_id = $1 AND m.plan_date BETWEEN $2 AND $3
+		ORDER BY m.plan_date,
+		         CASE m.slot WHEN 'breakfast' THEN 0 WHEN 'lunch' THEN 1 WHEN 'dinner' THEN 2 ELSE 3 END,
+		         m.position, m.id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.MealPlanEntry{}
+	for rows.Next() {
+		entry, err := scanMealPlanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (r *MealPlanRepository) GetEntryByID(userID, entryID int) (*models.MealPlanEntry, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + mealPlanColumns + `
+		FROM meal_plan_entries m
+		LEFT JOIN recipes r ON r.id = m.recipe_id
+		WHERE m.id = $1 AND m.user_id = $2
+	`
+
+	entry, err := scanMealPlanEntry(r.db.QueryRow(ctx, query, entryID, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("запись плана не найдена")
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *MealPlanRepository) CreateEntry(entry *models.MealPlanEntry, date time.Time) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO meal_plan_entries (user_id, plan_date, slot, recipe_id, servings, note, position, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6,
+		        (SELECT COALESCE(MAX(position) + 1, 0) FROM meal_plan_entries
+		         WHERE user_id = $1 AND plan_date = $2 AND slot = $3), $7)
+		RETURNING id, position, created_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		entry.UserID,
+		date,
+		entry.Slot,
+		entry.RecipeID,
+		entry.Servings,
+		entry.Note,
+		time.Now(),
+	).Scan(&entry.ID, &entry.Position, &entry.CreatedAt)
+}
+
+// UpdateEntry переносит запись в другой день или приём пищи и меняет порции и заметку.
+func (r *MealPlanRepository) UpdateEntry(entry *models.MealPlanEntry, date time.Time) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE meal_plan_entries
+		SET plan_date = $1, slot = $2, servings = $3, note = $4
+		WHERE id = $5 AND user_id = $6
+	`
+
+	result, err := r.db.Exec(ctx, query,
+		date,
+		entry.Slot,
+		entry.Servings,
+		entry.Note,
+		entry.ID,
+		entry.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("запись плана не найдена")
+	}
+
+	return nil
+}
+
+func (r *MealPlanRepository) DeleteEntry(userID, entryID int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `DELETE FROM meal_plan_entries WHERE id = $1 AND user_id = $2`, entryID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("запись плана не найдена")
+	}
+
+	return nil
+}
+
+// CopyRange копирует записи периода [from, to] со сдвигом на offsetDays дней, добавляя их к уже запланированному.
+func (r *MealPlanRepository) CopyRange(userID int, from, to time.Time, offsetDays int) (int64, error) {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO meal_plan_entries (user_id, plan_date, slot, recipe_id, servings, note, position, created_at)
+		SELECT m.user_id, m.plan_date + $4::int, m.slot, m.recipe_id, m.servings, m.note,
+		       m.position + (SELECT COALESCE(MAX(t.position) + 1, 0) FROM meal_plan_entries t
+		                     WHERE t.user_id = m.user_id AND t.plan_date = m.plan_date + $4::int AND t.slot = m.slot),
+		       $5
+		FROM meal_plan_entries m
+		WHERE m.user_id = $1 AND m.plan_date BETWEEN $2 AND $3
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, from, to, offsetDays, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *MealPlanRepository) ClearRange(userID int, from, to time.Time) (int64, error) {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM meal_plan_entries WHERE user_id = $1 AND plan_date BETWEEN $2 AND $3
+	`, userID, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
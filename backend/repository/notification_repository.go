@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type NotificationRepository struct {
+	db *pgx.Conn
+}
+
+func NewNotificationRepository(db *pgx.Conn) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// NotifyForkOwners уведомляет владельцев копий, включивших слежение за оригиналом.
+// Пока прошлое уведомление о том же рецепте не прочитано, новое не создаётся.
+func (r *NotificationRepository) NotifyForkOwners(sourceID int) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO notifications (user_id, kind, recipe_id, source_recipe_id, created_at)
+		SELECT f.user_id, $2, f.id, $1, $3
+		FROM recipes f
+		WHERE f.forked_from_id = $1 AND f.fork_notify
+		  AND NOT EXISTS (
+		      SELECT 1 FROM notifications n
+		      WHERE n.recipe_id = f.id AND n.kind = $2 AND n.read_at IS NULL)
+	`
+
+	_, err := r.db.Exec(ctx, query, sourceID, models.NotificationSourceUpdated, time.Now())
+	return err
+}
+
+func (r *NotificationRepository) GetNotifications(userID, limit int) ([]models.Notification, int, error) {
+	ctx := context.Background()
+
+	var unread int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL
+	`, userID).Scan(&unread)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT n.id, n.kind, n.recipe_id, rc.title, n.source_recipe_id,
+		       COALESCE(src.title, rc.forked_from_title, ''), n.created_at, n.read_at
+		FROM notifications n
+		JOIN recipes rc ON rc.id = n.recipe_id
+		LEFT JOIN recipes src ON src.id = n.source_recipe_id
+		WHERE n.user_id = $1
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(
+			&n.ID,
+			&n.Kind,
+			&n.RecipeID,
+			&n.RecipeTitle,
+			&n.SourceRecipeID,
+			&n.SourceTitle,
+			&n.CreatedAt,
+			&n.ReadAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, unread, nil
+}
+
+// MarkRead отмечает прочитанными указанные уведомления, а при пустом списке — все.
+func (r *NotificationRepository) MarkRead(userID int, ids []int) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE notifications SET read_at = $2
+		WHERE user_id = $1 AND read_at IS NULL
+		  AND (cardinality($3::int[]) = 0 OR id = ANY($3))
+	`
+
+	if ids == nil {
+		ids = []int{}
+	}
+
+	_, err := r.db.Exec(ctx, query, userID, time.Now(), ids)
+	return err
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PantryRepository struct {
+	db *pgx.Conn
+}
+
+func NewPantryRepository(db *pgx.Conn) *PantryRepository {
+	return &PantryRepository{db: db}
+}
+
+const (
+	PantrySortExpiry = "expiry"
+	PantrySortName   = "name"
+)
+
+func scanPantryItem(row pgx.Row) (models.PantryItem, error) {
+	var item models.PantryItem
+	var expiresAt *time.Time
+
+	err := row.Scan(
+		&item.ID,
+		&item.UserID,
+		&item.Name,
+		&item.NameKey,
+		&item.Quantity,
+		&item.Unit,
+		&item.Location,
+		&expiresAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if expiresAt != nil {
+		date := expiresAt.Format(models.DateLayout)
+		item.ExpiresAt = &date
+	}
+
+	return item, err
+}
+
+// GetItems возвращает запасы; при сортировке по сроку продукты без срока идут в конце.
+func (r *PantryRepository) GetItems(userID int, sort string) ([]models.PantryItem, error) {
+	ctx := context.Background()
+
+	orderBy := "expires_at ASC NULLS LAST, name"
+	if sort == PantrySortName {
+		orderBy = "name, expires_at ASC NULLS LAST"
+	}
+
+	query := `
+		SELECT id, user_id, name, name_key, quantity, unit, location, expires_at, created_at, updated_at
+		FROM pantry_items
+		WHERE user_id = $1
+		ORDER BY ` + orderBy
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.PantryItem{}
+	for rows.Next() {
+		item, err := scanPantryItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (r *PantryRepository) CreateItem(item *models.PantryItem, expiresAt *time.Time) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO pantry_items (user_id, name, name_key, quantity, unit, location, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		item.UserID,
+		item.Name,
+		item.NameKey,
+		item.Quantity,
+		item.Unit,
+		item.Location,
+		expiresAt,
+		time.Now(),
+	).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
+}
+
+func (r *PantryRepository) UpdateItem(item *models.PantryItem, expiresAt *time.Time) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE pantry_items
+		SET name = $1, name_key = $2, quantity = $3, unit = $4, location = $5, expires_at = $6, updated_at = $7
+		WHERE id = $8 AND user_id = $9
+	`
+
+	result, err := r.db.Exec(ctx, query,
+		item.Name,
+		item.NameKey,
+		item.Quantity,
+		item.Unit,
+		item.Location,
+		expiresAt,
+		time.Now(),
+		item.ID,
+		item.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("продукт не найден")
+	}
+
+	return nil
+}
+
+func (r *PantryRepository) DeleteItem(userID, itemID int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `DELETE FROM pantry_items WHERE id = $1 AND user_id = $2`, itemID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("продукт не найден")
+	}
+
+	return nil
+}
+
+// ApplyDeductions списывает продукты после приготовления и записывает в Remaining фактические остатки;
+// закончившиеся продукты удаляются. Списание относительное, поэтому одновременные списания
+// и правки запасов не затирают друг друга.
+func (r *PantryRepository) ApplyDeductions(userID int, deductions []models.PantryDeduction) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	for i := range deductions {
+		deduction := &deductions[i]
+		err := tx.QueryRow(ctx, `
+			UPDATE pantry_items SET quantity = quantity - $1, updated_at = $2
+			WHERE id = $3 AND user_id = $4
+			RETURNING quantity
+		`, deduction.Deducted, now, deduction.ItemID, userID).Scan(&deduction.Remaining)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Продукт успели удалить: списывать больше нечего
+			deduction.Remaining = 0
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if deduction.Remaining <= 0 {
+			deduction.Remaining = 0
+			if _, err := tx.Exec(ctx, `DELETE FROM pantry_items WHERE id = $1 AND user_id = $2`, deduction.ItemID, userID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PriceRepository struct {
+	db *pgx.Conn
+}
+
+func NewPriceRepository(db *pgx.Conn) *PriceRepository {
+	return &PriceRepository{db: db}
+}
+
+func (r *PriceRepository) GetPrices(userID int) ([]models.IngredientPrice, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, user_id, name, name_key, price, quantity, unit, updated_at
+		FROM ingredient_prices
+		WHERE user_id = $1
+		ORDER BY name, unit
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := []models.IngredientPrice{}
+	for rows.Next() {
+		var price models.IngredientPrice
+		if err := rows.Scan(&price.ID, &price.UserID, &price.Name, &price.NameKey,
+			&price.Price, &price.Quantity, &price.Unit, &price.UpdatedAt); err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+
+	return prices, nil
+}
+
+// SetPrice добавляет цену; цена того же продукта в той же единице заменяется.
+func (r *PriceRepository) SetPrice(price *models.IngredientPrice) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO ingredient_prices (user_id, name, name_key, price, quantity, unit, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, name_key, unit) DO UPDATE
+		SET name = EXCLUDED.name, price = EXCLUDED.price, quantity = EXCLUDED.quantity,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING id, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		price.UserID,
+		price.Name,
+		price.NameKey,
+		price.Price,
+		price.Quantity,
+		price.Unit,
+		time.Now(),
+	).Scan(&price.ID, &price.UpdatedAt)
+}
+
+func (r *PriceRepository) UpdatePrice(price *models.IngredientPrice) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE ingredient_prices
+		SET name = $1, name_key = $2, price = $3, quantity = $4, unit = $5, updated_at = $6
+		WHERE id = $7 AND user_id = $8
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		price.Name,
+		price.NameKey,
+		price.Price,
+		price.Quantity,
+		price.Unit,
+		time.Now(),
+		price.ID,
+		price.UserID,
+	).Scan(&price.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("цена не найдена")
+	}
+
+	return err
+}
+
+func (r *PriceRepository) DeletePrice(userID, priceID int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM ingredient_prices WHERE id = $1 AND user_id = $2
+	`, priceID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("цена не найдена")
+	}
+
+	return nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
+	"culinary-book/backend/dietary"
 	"culinary-book/backend/models"
 
 	"github.com/jackc/pgx/v5"
@@ -19,16 +21,87 @@ func NewRecipeRepository(db *pgx.Conn) *RecipeRepository {
 	return &RecipeRepository{db: db}
 }
 
+const ratingAvgColumn = `CASE WHEN r.rating_count > 0 THEN r.rating_sum::float8 / r.rating_count ELSE 0 END`
+
+func recipeOrderClause(sort string) string {
+	switch sort {
+	case models.SortRating:
+		return "ORDER BY " + ratingAvgColumn + " DESC, r.rating_count DESC, r.created_at DESC"
+	}
+	return "ORDER BY r.created_at DESC, r.id DESC"
+}
+
+// recipeColumns и scanRecipe задают общий набор полей рецепта для всех выборок;
+// запрос должен соединять recipes r с users u.
+const recipeColumns = `r.id, r.user_id, r.title, r.description, r.ingredients, r.instructions,
+		       r.cooking_time, r.servings, r.difficulty, r.image_base64, r.visibility,
+		       COALESCE(u.username, ''), ` + ratingAvgColumn + `, r.rating_count,
+		       r.forked_from_id, COALESCE(r.forked_from_title, ''), COALESCE(r.forked_from_author, ''),
+		       r.fork_count, r.fork_notify, r.cookbook_id, r.labels, r.label_overrides,
+		       r.created_at, r.updated_at`
+
+func scanRecipe(row pgx.Row, extra ...interface{}) (models.Recipe, error) {
+	var recipe models.Recipe
+	var ingredientsJSON, overridesJSON []byte
+
+	dest := []interface{}{
+		&recipe.ID,
+		&recipe.UserID,
+		&recipe.Title,
+		&recipe.Description,
+		&ingredientsJSON,
+		&recipe.Instructions,
+		&recipe.CookingTime,
+		&recipe.Servings,
+		&recipe.Difficulty,
+		&recipe.ImageBase64,
+		&recipe.Visibility,
+		&recipe.AuthorName,
+		&recipe.RatingAvg,
+		&recipe.RatingCount,
+		&recipe.ForkedFromID,
+		&recipe.ForkedFromTitle,
+		&recipe.ForkedFromAuthor,
+		&recipe.ForkCount,
+		&recipe.ForkNotify,
+		&recipe.CookbookID,
+		&recipe.Labels,
+		&overridesJSON,
+		&recipe.CreatedAt,
+		&recipe.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return recipe, err
+	}
+
+	json.Unmarshal(ingredientsJSON, &recipe.Ingredients)
+	json.Unmarshal(overridesJSON, &recipe.LabelOverrides)
+	return recipe, nil
+}
+
 func (r *RecipeRepository) CreateRecipe(recipe *models.Recipe) error {
 	ctx := context.Background()
 
 	ingredientsJSON, _ := json.Marshal(recipe.Ingredients)
+	overridesJSON := labelOverridesJSON(recipe.LabelOverrides)
+	recipe.Labels = dietary.Labels(recipe.Ingredients, recipe.LabelOverrides)
+
+	// Даты задаются заранее только при восстановлении рецептов из архива
+	createdAt, updatedAt := time.Now(), time.Now()
+	if !recipe.CreatedAt.IsZero() {
+		createdAt = recipe.CreatedAt
+	}
+	if !recipe.UpdatedAt.IsZero() {
+		updatedAt = recipe.UpdatedAt
+	}
 
 	query := `
 		INSERT INTO recipes
 		(user_id, title, description, ingredients, instructions,
-		 cooking_time, difficulty, image_base64, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		 cooking_time, servings, difficulty, image_base64, visibility, cookbook_id,
+		 labels, label_overrides, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -39,16 +112,21 @@ func (r *RecipeRepository) CreateRecipe(recipe *models.Recipe) error {
 		ingredientsJSON,
 		recipe.Instructions,
 		recipe.CookingTime,
+		recipe.Servings,
 		recipe.Difficulty,
 		recipe.ImageBase64,
-		time.Now(),
-		time.Now(),
+		recipe.Visibility,
+		recipe.CookbookID,
+		recipe.Labels,
+		overridesJSON,
+		createdAt,
+		updatedAt,
 	).Scan(&recipe.ID, &recipe.CreatedAt, &recipe.UpdatedAt)
 
 	return err
 }
 
-func (r *RecipeRepository) GetRecipesByUserID(userID int) ([]models.Recipe, error) {
+func (r *RecipeRepository) GetRecipesByUserID(userID int, sort string) ([]models.Recipe, error) {
 	ctx := context.Background()
 
 	favoriteRepo := NewFavoriteRepository(r.db)
@@ -63,12 +141,11 @@ func (r *RecipeRepository) GetRecipesByUserID(userID int) ([]models.Recipe, erro
 	}
 
 	query := `
-		SELECT id, user_id, title, description, ingredients, instructions,
-		       cooking_time, difficulty, image_base64, created_at, updated_at
-		FROM recipes
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-	`
+		SELECT ` + recipeColumns + `
+		FROM recipes r
+		LEFT JOIN users u ON u.id = r.user_id
+		WHERE r.user_id = $1 AND r.cookbook_id IS NULL
+		` + recipeOrderClause(sort)
 
 	rows, err := r.db.Query(ctx, query, userID)
 	if err != nil {
@@ -78,31 +155,75 @@ func (r *RecipeRepository) GetRecipesByUserID(userID int) ([]models.Recipe, erro
 
 	var recipes []models.Recipe
 	for rows.Next() {
-		var recipe models.Recipe
-		var ingredientsJSON []byte
+		recipe, err := scanRecipe(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		recipe.IsFavorite = favoriteMap[recipe.ID]
+
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, nil
+}
 
-		err := rows.Scan(
-			&recipe.ID,
-			&recipe.UserID,
-			&recipe.Title,
-			&recipe.Description,
-			&ingredientsJSON,
-			&recipe.Instructions,
-			&recipe.CookingTime,
-			&recipe.Difficulty,
-			&recipe.ImageBase64,
-			&recipe.CreatedAt,
-			&recipe.UpdatedAt,
-		)
+// GetAuthoredRecipes возвращает все рецепты автора, включая лежащие в общих книгах.
+func (r *RecipeRepository) GetAuthoredRecipes(userID int) ([]models.Recipe, error) {
+	ctx := context.Background()
 
+	query := `
+		SELECT ` + recipeColumns + `
+		FROM recipes r
+		LEFT JOIN users u ON u.id = r.user_id
+		WHERE r.user_id = $1
+		ORDER BY r.created_at, r.id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := []models.Recipe{}
+	for rows.Next() {
+		recipe, err := scanRecipe(rows)
 		if err != nil {
 			return nil, err
 		}
+		recipes = append(recipes, recipe)
+	}
 
-		json.Unmarshal(ingredientsJSON, &recipe.Ingredients)
+	return recipes, nil
+}
 
-		recipe.IsFavorite = favoriteMap[recipe.ID]
+func (r *RecipeRepository) GetRecipesByCookbook(cookbookID, viewerID int, sort string) ([]models.Recipe, error) {
+	ctx := context.Background()
 
+	query := `
+		SELECT ` + recipeColumns + `, f.id IS NOT NULL
+		FROM recipes r
+		LEFT JOIN users u ON u.id = r.user_id
+		LEFT JOIN favorites f ON f.recipe_id = r.id AND f.user_id = $2
+		WHERE r.cookbook_id = $1
+		` + recipeOrderClause(sort)
+
+	rows, err := r.db.Query(ctx, query, cookbookID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipes []models.Recipe
+	for rows.Next() {
+		var isFavorite bool
+		recipe, err := scanRecipe(rows, &isFavorite)
+		if err != nil {
+			return nil, err
+		}
+
+		recipe.IsFavorite = isFavorite
 		recipes = append(recipes, recipe)
 	}
 
@@ -113,29 +234,13 @@ func (r *RecipeRepository) GetRecipeByID(recipeID int) (*models.Recipe, error) {
 	ctx := context.Background()
 
 	query := `
-		SELECT id, user_id, title, description, ingredients, instructions,
-		       cooking_time, difficulty, image_base64, created_at, updated_at
-		FROM recipes
-		WHERE id = $1
+		SELECT ` + recipeColumns + `
+		FROM recipes r
+		LEFT JOIN users u ON u.id = r.user_id
+		WHERE r.id = $1
 	`
 
-	var recipe models.Recipe
-	var ingredientsJSON []byte
-
-	err := r.db.QueryRow(ctx, query, recipeID).Scan(
-		&recipe.ID,
-		&recipe.UserID,
-		&recipe.Title,
-		&recipe.Description,
-		&ingredientsJSON,
-		&recipe.Instructions,
-		&recipe.CookingTime,
-		&recipe.Difficulty,
-		&recipe.ImageBase64,
-		&recipe.CreatedAt,
-		&recipe.UpdatedAt,
-	)
-
+	recipe, err := scanRecipe(r.db.QueryRow(ctx, query, recipeID))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, errors.New("рецепт не найден")
@@ -143,20 +248,104 @@ func (r *RecipeRepository) GetRecipeByID(recipeID int) (*models.Recipe, error) {
 		return nil, err
 	}
 
-	json.Unmarshal(ingredientsJSON, &recipe.Ingredients)
 	return &recipe, nil
 }
 
+// GetRecipesByIDs загружает рецепты одним запросом, порядок не гарантируется;
+// viewerID нужен для отметки избранного. Отсутствующие рецепты пропускаются.
+func (r *RecipeRepository) GetRecipesByIDs(recipeIDs []int, viewerID int) ([]models.Recipe, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + recipeColumns + `, f.id IS NOT NULL
+		FROM recipes r
+		LEFT JOIN users u ON u.id = r.user_id
+		LEFT JOIN favorites f ON f.recipe_id = r.id AND f.user_id = $2
+		WHERE r.id = ANY($1)
+	`
+
+	rows, err := r.db.Query(ctx, query, recipeIDs, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipes []models.Recipe
+	for rows.Next() {
+		var isFavorite bool
+		recipe, err := scanRecipe(rows, &isFavorite)
+		if err != nil {
+			return nil, err
+		}
+
+		recipe.IsFavorite = isFavorite
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, rows.Err()
+}
+
+// GetPublicRecipes возвращает публичную ленту; filter отбрасывает рецепты
+// с запрещёнными аллергенами и без обязательных диет.
+func (r *RecipeRepository) GetPublicRecipes(viewerID int, search, sort string, filter models.LabelFilter, limit, offset int) ([]models.Recipe, int, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + recipeColumns + `, f.id IS NOT NULL, COUNT(*) OVER()
+		FROM recipes r
+		JOIN users u ON u.id = r.user_id
+		LEFT JOIN favorites f ON f.recipe_id = r.id AND f.user_id = $1
+		WHERE r.visibility = 'public'
+		  AND r.user_id <> $1
+		  AND ($2 = '' OR r.title ILIKE '%' || $2 || '%'
+		       OR r.description ILIKE '%' || $2 || '%'
+		       OR r.ingredients::text ILIKE '%' || $2 || '%')
+		  AND NOT (r.labels && $5::text[])
+		  AND r.labels @> $6::text[]
+		` + recipeOrderClause(sort) + `
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, viewerID, escapeLike(search), limit, offset,
+		nonNilLabels(filter.Avoid), nonNilLabels(filter.Require))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var recipes []models.Recipe
+	total := 0
+	for rows.Next() {
+		var isFavorite bool
+		recipe, err := scanRecipe(rows, &isFavorite, &total)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		recipe.IsFavorite = isFavorite
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, total, nil
+}
+
+// escapeLike экранирует % и _ в строке поиска, чтобы они искались как обычные символы.
+func escapeLike(search string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(search)
+}
+
 func (r *RecipeRepository) UpdateRecipe(recipe *models.Recipe) error {
 	ctx := context.Background()
 
 	ingredientsJSON, _ := json.Marshal(recipe.Ingredients)
+	recipe.Labels = dietary.Labels(recipe.Ingredients, recipe.LabelOverrides)
 
 	query := `
 		UPDATE recipes
 		SET title = $1, description = $2, ingredients = $3, instructions = $4,
-		    cooking_time = $5, difficulty = $6, image_base64 = $7, updated_at = $8
-		WHERE id = $9 AND user_id = $10
+		    cooking_time = $5, servings = $6, difficulty = $7, image_base64 = $8, visibility = $9,
+		    labels = $10, updated_at = $11
+		WHERE id = $12
 		RETURNING updated_at
 	`
 
@@ -166,32 +355,272 @@ func (r *RecipeRepository) UpdateRecipe(recipe *models.Recipe) error {
 		ingredientsJSON,
 		recipe.Instructions,
 		recipe.CookingTime,
+		recipe.Servings,
 		recipe.Difficulty,
 		recipe.ImageBase64,
+		recipe.Visibility,
+		recipe.Labels,
 		time.Now(),
 		recipe.ID,
-		recipe.UserID,
 	).Scan(&recipe.UpdatedAt)
 
 	return err
 }
 
-func (r *RecipeRepository) DeleteRecipe(recipeID, userID int) error {
+// DeleteRecipe удаляет рецепт; если это копия, у оригинала уменьшается счётчик копий.
+func (r *RecipeRepository) DeleteRecipe(recipeID int) error {
 	ctx := context.Background()
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		DELETE FROM recipes
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1
+		RETURNING forked_from_id
+	`
+
+	var forkedFromID *int
+	err = tx.QueryRow(ctx, query, recipeID).Scan(&forkedFromID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("рецепт не найден")
+	}
+	if err != nil {
+		return err
+	}
+
+	if forkedFromID != nil {
+		_, err = tx.Exec(ctx, `UPDATE recipes SET fork_count = GREATEST(fork_count - 1, 0) WHERE id = $1`, *forkedFromID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ForkRecipe создаёт личную копию чужого рецепта, запоминает источник
+// и увеличивает счётчик копий у оригинала.
+func (r *RecipeRepository) ForkRecipe(source *models.Recipe, userID int, notify bool) (*models.Recipe, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	ingredientsJSON, _ := json.Marshal(source.Ingredients)
+
+	fork := &models.Recipe{
+		UserID:           userID,
+		Title:            source.Title,
+		Description:      source.Description,
+		Ingredients:      source.Ingredients,
+		Instructions:     source.Instructions,
+		CookingTime:      source.CookingTime,
+		Servings:         source.Servings,
+		Difficulty:       source.Difficulty,
+		ImageBase64:      source.ImageBase64,
+		Visibility:       models.VisibilityPrivate,
+		ForkedFromID:     &source.ID,
+		ForkedFromTitle:  source.Title,
+		ForkedFromAuthor: source.AuthorName,
+		ForkNotify:       notify,
+		LabelOverrides:   source.LabelOverrides,
+	}
+	fork.Labels = dietary.Labels(fork.Ingredients, fork.LabelOverrides)
+
+	query := `
+		INSERT INTO recipes
+		(user_id, title, description, ingredients, instructions,
+		 cooking_time, servings, difficulty, image_base64, visibility,
+		 forked_from_id, forked_from_title, forked_from_author, fork_notify,
+		 labels, label_overrides, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $17)
+		RETURNING id, created_at, updated_at
 	`
 
-	result, err := r.db.Exec(ctx, query, recipeID, userID)
+	err = tx.QueryRow(ctx, query,
+		fork.UserID,
+		fork.Title,
+		fork.Description,
+		ingredientsJSON,
+		fork.Instructions,
+		fork.CookingTime,
+		fork.Servings,
+		fork.Difficulty,
+		fork.ImageBase64,
+		fork.Visibility,
+		fork.ForkedFromID,
+		fork.ForkedFromTitle,
+		fork.ForkedFromAuthor,
+		fork.ForkNotify,
+		fork.Labels,
+		labelOverridesJSON(fork.LabelOverrides),
+		time.Now(),
+	).Scan(&fork.ID, &fork.CreatedAt, &fork.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE recipes SET fork_count = fork_count + 1 WHERE id = $1`, source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return fork, nil
+}
+
+func (r *RecipeRepository) SetForkNotify(recipeID int, notify bool) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE recipes SET fork_notify = $1
+		WHERE id = $2 AND forked_from_id IS NOT NULL
+	`, notify, recipeID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("рецепт не является копией")
+	}
+
+	return nil
+}
+
+// MoveToCookbook переносит рецепт в общую книгу; nil возвращает его в личные.
+func (r *RecipeRepository) MoveToCookbook(recipeID int, cookbookID *int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE recipes SET cookbook_id = $1, updated_at = $2 WHERE id = $3
+	`, cookbookID, time.Now(), recipeID)
 	if err != nil {
 		return err
 	}
 
 	if result.RowsAffected() == 0 {
-		return errors.New("рецепт не найден или нет прав на удаление")
+		return errors.New("рецепт не найден")
 	}
 
 	return nil
 }
+
+func labelOverridesJSON(overrides map[string]bool) []byte {
+	if overrides == nil {
+		overrides = map[string]bool{}
+	}
+	data, _ := json.Marshal(overrides)
+	return data
+}
+
+// SetLabelOverrides сохраняет ручные исправления меток и пересчитывает итоговые метки.
+func (r *RecipeRepository) SetLabelOverrides(recipe *models.Recipe, overrides map[string]bool) error {
+	ctx := context.Background()
+
+	labels := dietary.Labels(recipe.Ingredients, overrides)
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE recipes SET labels = $1, label_overrides = $2 WHERE id = $3
+	`, labels, labelOverridesJSON(overrides), recipe.ID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("рецепт не найден")
+	}
+
+	recipe.Labels = labels
+	recipe.LabelOverrides = overrides
+	return nil
+}
+
+// RelabelAll пересчитывает метки всех рецептов, чтобы изменения базы знаний
+// применялись и к рецептам, сохранённым раньше. Возвращает число изменённых рецептов.
+func (r *RecipeRepository) RelabelAll() (int, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, `SELECT id, ingredients, labels, label_overrides FROM recipes`)
+	if err != nil {
+		return 0, err
+	}
+
+	type relabel struct {
+		id     int
+		labels []string
+	}
+
+	var changes []relabel
+	for rows.Next() {
+		var id int
+		var ingredientsJSON, overridesJSON []byte
+		var current []string
+		if err := rows.Scan(&id, &ingredientsJSON, &current, &overridesJSON); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		var lines []string
+		var overrides map[string]bool
+		json.Unmarshal(ingredientsJSON, &lines)
+		json.Unmarshal(overridesJSON, &overrides)
+
+		labels := dietary.Labels(lines, overrides)
+		if strings.Join(labels, ",") != strings.Join(current, ",") {
+			changes = append(changes, relabel{id: id, labels: labels})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, change := range changes {
+		if _, err := r.db.Exec(ctx, `UPDATE recipes SET labels = $1 WHERE id = $2`, change.labels, change.id); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(changes), nil
+}
+
+// GetIndexRecipes возвращает все рецепты без изображений для построения индекса похожих рецептов;
+// автор, видимость и книга нужны, чтобы разложить соседей по кругам читателей.
+func (r *RecipeRepository) GetIndexRecipes() ([]models.Recipe, error) {
+	ctx := context.Background()
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, title, COALESCE(description, ''), ingredients, COALESCE(instructions, ''),
+		       COALESCE(cooking_time, 0), COALESCE(difficulty, ''), labels, visibility, cookbook_id
+		FROM recipes
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipes []models.Recipe
+	for rows.Next() {
+		var recipe models.Recipe
+		var ingredientsJSON []byte
+		if err := rows.Scan(&recipe.ID, &recipe.UserID, &recipe.Title, &recipe.Description, &ingredientsJSON,
+			&recipe.Instructions, &recipe.CookingTime, &recipe.Difficulty, &recipe.Labels,
+			&recipe.Visibility, &recipe.CookbookID); err != nil {
+			return nil, err
+		}
+		json.Unmarshal(ingredientsJSON, &recipe.Ingredients)
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, rows.Err()
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ReviewRepository struct {
+	db *pgx.Conn
+}
+
+func NewReviewRepository(db *pgx.Conn) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// SaveReview создаёт или обновляет отзыв пользователя и в той же транзакции
+// поправляет сумму и количество оценок в recipes, чтобы не пересчитывать средний балл по всем отзывам.
+func (r *ReviewRepository) SaveReview(review *models.Review) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var oldRating int
+	err = tx.QueryRow(ctx, `
+		SELECT rating FROM recipe_reviews
+		WHERE recipe_id = $1 AND user_id = $2
+		FOR UPDATE
+	`, review.RecipeID, review.UserID).Scan(&oldRating)
+
+	now := time.Now()
+
+	switch {
+	case err == pgx.ErrNoRows:
+		err = tx.QueryRow(ctx, `
+			INSERT INTO recipe_reviews (recipe_id, user_id, rating, text, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $5)
+			RETURNING id, created_at, updated_at
+		`, review.RecipeID, review.UserID, review.Rating, review.Text, now,
+		).Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE recipes
+			SET rating_sum = rating_sum + $1, rating_count = rating_count + 1
+			WHERE id = $2
+		`, review.Rating, review.RecipeID)
+		if err != nil {
+			return err
+		}
+
+	case err == nil:
+		err = tx.QueryRow(ctx, `
+			UPDATE recipe_reviews
+			SET rating = $1, text = $2, updated_at = $3
+			WHERE recipe_id = $4 AND user_id = $5
+			RETURNING id, created_at, updated_at
+		`, review.Rating, review.Text, now, review.RecipeID, review.UserID,
+		).Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE recipes
+			SET rating_sum = rating_sum + $1
+			WHERE id = $2
+		`, review.Rating-oldRating, review.RecipeID)
+		if err != nil {
+			return err
+		}
+
+	default:
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ReviewRepository) DeleteReview(recipeID, userID int) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var rating int
+	err = tx.QueryRow(ctx, `
+		DELETE FROM recipe_reviews
+		WHERE recipe_id = $1 AND user_id = $2
+		RETURNING rating
+	`, recipeID, userID).Scan(&rating)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return errors.New("отзыв не найден")
+		}
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE recipes
+		SET rating_sum = rating_sum - $1, rating_count = rating_count - 1
+		WHERE id = $2
+	`, rating, recipeID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ReviewRepository) GetReviewsByRecipe(recipeID, limit, offset int) ([]models.Review, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT rv.id, rv.recipe_id, rv.user_id, u.username, rv.rating,
+		       COALESCE(rv.text, ''), rv.created_at, rv.updated_at
+		FROM recipe_reviews rv
+		JOIN users u ON u.id = rv.user_id
+		WHERE rv.recipe_id = $1
+		ORDER BY rv.updated_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, recipeID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.Review
+	for rows.Next() {
+		var review models.Review
+		err := rows.Scan(
+			&review.ID,
+			&review.RecipeID,
+			&review.UserID,
+			&review.AuthorName,
+			&review.Rating,
+			&review.Text,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, nil
+}
+
+func (r *ReviewRepository) GetUserReview(recipeID, userID int) (*models.Review, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, recipe_id, user_id, rating, COALESCE(text, ''), created_at, updated_at
+		FROM recipe_reviews
+		WHERE recipe_id = $1 AND user_id = $2
+	`
+
+	var review models.Review
+	err := r.db.QueryRow(ctx, query, recipeID, userID).Scan(
+		&review.ID,
+		&review.RecipeID,
+		&review.UserID,
+		&review.Rating,
+		&review.Text,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("отзыв не найден")
+		}
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (r *ReviewRepository) GetReviewsByUser(userID int) ([]models.Review, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, recipe_id, user_id, rating, COALESCE(text, ''), created_at, updated_at
+		FROM recipe_reviews
+		WHERE user_id = $1
+		ORDER BY updated_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		var review models.Review
+		err := rows.Scan(
+			&review.ID,
+			&review.RecipeID,
+			&review.UserID,
+			&review.Rating,
+			&review.Text,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ShareRepository struct {
+	db *pgx.Conn
+}
+
+func NewShareRepository(db *pgx.Conn) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+func (r *ShareRepository) CreateShare(share *models.RecipeShare) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO recipe_shares (recipe_id, user_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		share.RecipeID,
+		share.UserID,
+		time.Now(),
+		share.ExpiresAt,
+	).Scan(&share.ID, &share.CreatedAt)
+
+	return err
+}
+
+func (r *ShareRepository) GetShareByID(shareID int) (*models.RecipeShare, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, recipe_id, user_id, created_at, expires_at, revoked_at
+		FROM recipe_shares
+		WHERE id = $1
+	`
+
+	var share models.RecipeShare
+	err := r.db.QueryRow(ctx, query, shareID).Scan(
+		&share.ID,
+		&share.RecipeID,
+		&share.UserID,
+		&share.CreatedAt,
+		&share.ExpiresAt,
+		&share.RevokedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.New("ссылка не найдена")
+		}
+		return nil, err
+	}
+
+	return &share, nil
+}
+
+func (r *ShareRepository) GetActiveSharesByRecipe(recipeID int) ([]models.RecipeShare, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, recipe_id, user_id, created_at, expires_at, revoked_at
+		FROM recipe_shares
+		WHERE recipe_id = $1
+		  AND revoked_at IS NULL
+		  AND (expires_at IS NULL OR expires_at > $2)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, recipeID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []models.RecipeShare
+	for rows.Next() {
+		var share models.RecipeShare
+		err := rows.Scan(
+			&share.ID,
+			&share.RecipeID,
+			&share.UserID,
+			&share.CreatedAt,
+			&share.ExpiresAt,
+			&share.RevokedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// RevokeShare отзывает ссылку; права на рецепт проверяет вызывающий.
+func (r *ShareRepository) RevokeShare(shareID int) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE recipe_shares
+		SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, time.Now(), shareID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("ссылка не найдена или уже отозвана")
+	}
+
+	return nil
+}
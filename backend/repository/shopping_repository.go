@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"culinary-book/backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ShoppingRepository struct {
+	db *pgx.Conn
+}
+
+func NewShoppingRepository(db *pgx.Conn) *ShoppingRepository {
+	return &ShoppingRepository{db: db}
+}
+
+func (r *ShoppingRepository) GetItems(userID int) ([]models.ShoppingItem, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, user_id, name, name_key, quantity, unit, category, checked, manual,
+		       COALESCE(sources, ''), updated_at
+		FROM shopping_items
+		WHERE user_id = $1
+		ORDER BY checked, name
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.ShoppingItem{}
+	for rows.Next() {
+		var item models.ShoppingItem
+		err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.Name,
+			&item.NameKey,
+			&item.Quantity,
+			&item.Unit,
+			&item.Category,
+			&item.Checked,
+			&item.Manual,
+			&item.Sources,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// MergeItems добавляет сгенерированные строки в список. Каждая складывается с одной ещё не купленной
+// строкой с тем же названием и единицей, причём ручные строки — только с ручными, иначе при replace
+// удалялось бы то, что пользователь вписал сам; при replace прежние сгенерированные строки удаляются.
+func (r *ShoppingRepository) MergeItems(userID int, items []models.ShoppingItem, replace bool) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if replace {
+		if _, err := tx.Exec(ctx, `DELETE FROM shopping_items WHERE user_id = $1 AND manual = FALSE`, userID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	for _, item := range items {
+		result, err := tx.Exec(ctx, `
+			UPDATE shopping_items
+			SET quantity = quantity + $1,
+			    sources = CASE WHEN COALESCE(sources, '') = '' THEN $2
+			                   WHEN $2 = '' OR POSITION($2 IN sources) > 0 THEN sources
+			                   ELSE sources || ', ' || $2 END,
+			    updated_at = $3
+			WHERE id = (
+				SELECT id FROM shopping_items
+				WHERE user_id = $4 AND name_key = $5 AND unit = $6 AND checked = FALSE AND manual = $7
+				ORDER BY id
+				LIMIT 1
+			)
+		`, item.Quantity, item.Sources, now, userID, item.NameKey, item.Unit, item.Manual)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() > 0 {
+			continue
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO shopping_items (user_id, name, name_key, quantity, unit, category, manual, sources, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, userID, item.Name, item.NameKey, item.Quantity, item.Unit, item.Category, item.Manual, item.Sources, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ShoppingRepository) UpdateItem(item *models.ShoppingItem) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE shopping_items
+		SET name = $1, name_key = $2, quantity = $3, unit = $4, category = $5, updated_at = $6
+		WHERE id = $7 AND user_id = $8
+	`
+
+	result, err := r.db.Exec(ctx, query,
+		item.Name,
+		item.NameKey,
+		item.Quantity,
+		item.Unit,
+		item.Category,
+		time.Now(),
+		item.ID,
+		item.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("покупка не найдена")
+	}
+
+	return nil
+}
+
+func (r *ShoppingRepository) SetChecked(userID, itemID int, checked bool) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE shopping_items SET checked = $1, updated_at = $2 WHERE id = $3 AND user_id = $4
+	`, checked, time.Now(), itemID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("покупка не найдена")
+	}
+
+	return nil
+}
+
+func (r *ShoppingRepository) DeleteItem(userID, itemID int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `DELETE FROM shopping_items WHERE id = $1 AND user_id = $2`, itemID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("покупка не найдена")
+	}
+
+	return nil
+}
+
+// Clear удаляет купленные строки или весь список.
+func (r *ShoppingRepository) Clear(userID int, checkedOnly bool) (int64, error) {
+	ctx := context.Background()
+
+	query := `DELETE FROM shopping_items WHERE user_id = $1`
+	if checkedOnly {
+		query += ` AND checked = TRUE`
+	}
+
+	result, err := r.db.Exec(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -41,7 +41,8 @@ func (r *UserRepository) GetUserByUsername(username string) (*models.User, error
 	ctx := context.Background()
 
 	query := `
-		SELECT id, username, password_hash, email, created_at
+		SELECT id, username, password_hash, COALESCE(email, ''), COALESCE(display_name, ''),
+		       COALESCE(avatar_base64, ''), COALESCE(bio, ''), deletion_requested_at, created_at
 		FROM users
 		WHERE username = $1
 	`
@@ -52,6 +53,10 @@ func (r *UserRepository) GetUserByUsername(username string) (*models.User, error
 		&user.Username,
 		&user.PasswordHash,
 		&user.Email,
+		&user.DisplayName,
+		&user.AvatarBase64,
+		&user.Bio,
+		&user.DeletionRequestedAt,
 		&user.CreatedAt,
 	)
 
@@ -69,7 +74,8 @@ func (r *UserRepository) GetUserByID(userID int) (*models.User, error) {
 	ctx := context.Background()
 
 	query := `
-		SELECT id, username, email, created_at
+		SELECT id, username, password_hash, COALESCE(email, ''), COALESCE(display_name, ''),
+		       COALESCE(avatar_base64, ''), COALESCE(bio, ''), deletion_requested_at, created_at
 		FROM users
 		WHERE id = $1
 	`
@@ -78,7 +84,12 @@ func (r *UserRepository) GetUserByID(userID int) (*models.User, error) {
 	err := r.db.QueryRow(ctx, query, userID).Scan(
 		&user.ID,
 		&user.Username,
+		&user.PasswordHash,
 		&user.Email,
+		&user.DisplayName,
+		&user.AvatarBase64,
+		&user.Bio,
+		&user.DeletionRequestedAt,
 		&user.CreatedAt,
 	)
 
@@ -107,3 +118,168 @@ func (r *UserRepository) UsernameExists(username string) (bool, error) {
 
 	return count > 0, nil
 }
+
+func (r *UserRepository) EmailTaken(email string, exceptUserID int) (bool, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT COUNT(*) FROM users WHERE LOWER(email) = LOWER($1) AND id <> $2
+	`
+
+	var count int
+	err := r.db.QueryRow(ctx, query, email, exceptUserID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// UpdateProfile меняет только переданные поля профиля.
+func (r *UserRepository) UpdateProfile(userID int, update *models.ProfileUpdate) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE users
+		SET display_name = COALESCE($1, display_name),
+		    email = COALESCE($2, email),
+		    avatar_base64 = COALESCE($3, avatar_base64),
+		    bio = COALESCE($4, bio)
+		WHERE id = $5
+	`
+
+	result, err := r.db.Exec(ctx, query,
+		update.DisplayName,
+		update.Email,
+		update.AvatarBase64,
+		update.Bio,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("пользователь не найден")
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdatePassword(userID int, passwordHash string) error {
+	ctx := context.Background()
+
+	_, err := r.db.Exec(ctx, `UPDATE users SET password_hash = $1 WHERE id = $2`, passwordHash, userID)
+	return err
+}
+
+func (r *UserRepository) RequestDeletion(userID int) (time.Time, error) {
+	ctx := context.Background()
+
+	var requestedAt time.Time
+	err := r.db.QueryRow(ctx, `
+		UPDATE users SET deletion_requested_at = COALESCE(deletion_requested_at, $1)
+		WHERE id = $2
+		RETURNING deletion_requested_at
+	`, time.Now(), userID).Scan(&requestedAt)
+
+	return requestedAt, err
+}
+
+func (r *UserRepository) CancelDeletion(userID int) error {
+	ctx := context.Background()
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE users SET deletion_requested_at = NULL
+		WHERE id = $1 AND deletion_requested_at IS NOT NULL
+	`, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("удаление аккаунта не запрошено")
+	}
+
+	return nil
+}
+
+// PurgeDeletedAccounts окончательно удаляет аккаунты, запросившие удаление раньше before.
+// Оценки удаляемых пользователей предварительно вычитаются из рейтингов рецептов,
+// а их копии — из счётчиков копий оригиналов. Возвращает число аккаунтов и удалённые вместе с ними рецепты.
+func (r *UserRepository) PurgeDeletedAccounts(before time.Time) (int, []int, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT r.id
+		FROM recipes r
+		JOIN users u ON u.id = r.user_id
+		WHERE u.deletion_requested_at < $1
+	`, before)
+	if err != nil {
+		return 0, nil, err
+	}
+	var recipeIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		recipeIDs = append(recipeIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE recipes rc
+		SET rating_sum = rc.rating_sum - agg.rating_sum,
+		    rating_count = rc.rating_count - agg.rating_count
+		FROM (
+			SELECT rv.recipe_id, SUM(rv.rating) AS rating_sum, COUNT(*) AS rating_count
+			FROM recipe_reviews rv
+			JOIN users u ON u.id = rv.user_id
+			WHERE u.deletion_requested_at < $1
+			GROUP BY rv.recipe_id
+		) agg
+		WHERE rc.id = agg.recipe_id
+	`, before)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE recipes rc
+		SET fork_count = GREATEST(rc.fork_count - agg.forks, 0)
+		FROM (
+			SELECT f.forked_from_id, COUNT(*) AS forks
+			FROM recipes f
+			JOIN users u ON u.id = f.user_id
+			WHERE u.deletion_requested_at < $1 AND f.forked_from_id IS NOT NULL
+			GROUP BY f.forked_from_id
+		) agg
+		WHERE rc.id = agg.forked_from_id
+	`, before)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM users WHERE deletion_requested_at < $1`, before)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, nil, err
+	}
+
+	return int(result.RowsAffected()), recipeIDs, nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+)
+
+const reviewsPageSize = 20
+
+func reviewsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal := principalFromRequest(r)
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, principal, policy.ActionRead, recipeID)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	reviews, err := reviewRepo.GetReviewsByRecipe(recipeID, reviewsPageSize, (page-1)*reviewsPageSize)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении отзывов"}`, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"page":    page,
+		"count":   len(reviews),
+		"reviews": reviews,
+		"summary": models.RatingSummary{
+			Average: recipe.RatingAvg,
+			Count:   recipe.RatingCount,
+		},
+	}
+
+	if principal.IsAuthenticated() {
+		if myReview, err := reviewRepo.GetUserReview(recipeID, principal.UserID); err == nil {
+			response["my_review"] = myReview
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func saveReviewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		RecipeID int    `json:"recipe_id"`
+		Rating   int    `json:"rating"`
+		Text     string `json:"text"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.Rating < 1 || req.Rating > 5 {
+		http.Error(w, `{"error": "Оценка должна быть от 1 до 5"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionReview, req.RecipeID)
+	if !ok {
+		return
+	}
+
+	review := &models.Review{
+		RecipeID: req.RecipeID,
+		UserID:   userID,
+		Rating:   req.Rating,
+		Text:     strings.TrimSpace(req.Text),
+	}
+
+	if err := reviewRepo.SaveReview(review); err != nil {
+		http.Error(w, `{"error": "Ошибка при сохранении отзыва"}`, http.StatusInternalServerError)
+		return
+	}
+
+	if recipe.Visibility == models.VisibilityPublic {
+		publishToFeed(userID, models.FeedReview, recipe.ID)
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Отзыв сохранён",
+		"review":  review,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func deleteReviewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := reviewRepo.DeleteReview(recipeID, userID); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Отзыв удалён",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
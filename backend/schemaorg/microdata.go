@@ -0,0 +1,178 @@
+package schemaorg
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// page — то, что нужно импортёру из HTML-страницы: тексты блоков JSON-LD
+// и рецепты из микроданных, уже собранные в объекты того же вида, что и JSON-LD.
+type page struct {
+	scripts []string
+	items   []map[string]interface{}
+}
+
+func parsePage(content string) (*page, error) {
+	root, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return nil, errors.New("не удалось разобрать HTML")
+	}
+
+	p := &page{}
+	p.walk(root)
+	return p, nil
+}
+
+func (p *page) walk(node *html.Node) {
+	if node.Type == html.ElementNode {
+		if node.DataAtom == atom.Script && strings.EqualFold(strings.TrimSpace(attr(node, "type")), "application/ld+json") {
+			p.scripts = append(p.scripts, textContent(node))
+			return
+		}
+		// Рецепт, вложенный в другой элемент с itemscope, тоже находится:
+		// обход не останавливается на чужих itemscope
+		if hasAttr(node, "itemscope") && isRecipeType(attr(node, "itemtype")) {
+			p.items = append(p.items, microdataItem(node))
+			return
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		p.walk(child)
+	}
+}
+
+func isRecipeType(itemtype string) bool {
+	for _, t := range strings.Fields(itemtype) {
+		if strings.HasSuffix(strings.TrimSuffix(t, "/"), "schema.org/Recipe") {
+			return true
+		}
+	}
+	return false
+}
+
+// microdataItem собирает свойства itemprop элемента с itemscope. Повторяющиеся свойства
+// превращаются в массив; вложенные itemscope (HowToStep, ImageObject) становятся объектами.
+func microdataItem(scope *html.Node) map[string]interface{} {
+	item := map[string]interface{}{}
+	if itemtype := attr(scope, "itemtype"); itemtype != "" {
+		item["@type"] = strings.Fields(itemtype)[0]
+	}
+
+	var collect func(node *html.Node)
+	collect = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+
+			nested := hasAttr(child, "itemscope")
+			if names := strings.Fields(attr(child, "itemprop")); len(names) > 0 {
+				var value interface{}
+				if nested {
+					value = microdataItem(child)
+				} else {
+					value = propertyValue(child)
+				}
+				for _, name := range names {
+					addProperty(item, name, value)
+				}
+			}
+			if !nested {
+				collect(child)
+			}
+		}
+	}
+	collect(scope)
+
+	return item
+}
+
+func addProperty(item map[string]interface{}, name string, value interface{}) {
+	switch existing := item[name].(type) {
+	case nil:
+		item[name] = value
+	case []interface{}:
+		item[name] = append(existing, value)
+	default:
+		item[name] = []interface{}{existing, value}
+	}
+}
+
+// propertyValue — значение свойства по правилам микроданных HTML: атрибут content,
+// ссылка для img и a, datetime для time, иначе текст элемента.
+func propertyValue(node *html.Node) string {
+	if hasAttr(node, "content") {
+		return attr(node, "content")
+	}
+	switch node.DataAtom {
+	case atom.Img, atom.Audio, atom.Video, atom.Source, atom.Embed, atom.Iframe:
+		return attr(node, "src")
+	case atom.A, atom.Link, atom.Area:
+		return attr(node, "href")
+	case atom.Object:
+		return attr(node, "data")
+	case atom.Time:
+		if hasAttr(node, "datetime") {
+			return attr(node, "datetime")
+		}
+	case atom.Data, atom.Meter:
+		return attr(node, "value")
+	}
+	return textContent(node)
+}
+
+// textContent возвращает текст элемента; блочные элементы и <br> разделяются переводом строки,
+// чтобы инструкция из нескольких абзацев не слилась в одну строку.
+func textContent(node *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			switch n.DataAtom {
+			case atom.Br:
+				b.WriteString("\n")
+				return
+			case atom.Script, atom.Style:
+				if n != node {
+					return
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.P, atom.Div, atom.Li, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				b.WriteString("\n")
+			}
+		}
+	}
+	walk(node)
+	return b.String()
+}
+
+func hasAttr(node *html.Node, name string) bool {
+	for _, a := range node.Attr {
+		if a.Key == name {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(node *html.Node, name string) string {
+	for _, a := range node.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,478 @@
+// Package schemaorg переводит рецепты из разметки schema.org Recipe в модель приложения и обратно.
+// Разметка ищется в JSON-LD (<script type="application/ld+json">) и в микроданных (itemscope/itemprop);
+// вставленный текст может быть и целой HTML-страницей, и одним JSON-LD документом.
+package schemaorg
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"culinary-book/backend/dietary"
+	"culinary-book/backend/models"
+)
+
+const (
+	Context = "https://schema.org"
+
+	maxServings = 100
+	maxMinutes  = 7 * 24 * 60
+)
+
+var (
+	ErrNoRecipes = errors.New("разметка schema.org Recipe не найдена")
+
+	tagPattern        = regexp.MustCompile(`<[^>]*>`)
+	lineBreakPattern  = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</li>|</div>`)
+	spacePattern      = regexp.MustCompile(`[ \t\r\f\v]+`)
+	numberPattern     = regexp.MustCompile(`\d+`)
+	durationPattern   = regexp.MustCompile(`(?i)^P(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+	stepNumberPattern = regexp.MustCompile(`(?i)^\s*(?:шаг\s*)?\d+\s*[.):-]\s*`)
+)
+
+// Imported — рецепт, найденный в разметке. Изображение не скачивается: ImageURL может быть
+// абсолютной ссылкой или путём относительно страницы, его загружает клиент. Картинки
+// в data: URI сразу попадают в Recipe.ImageBase64.
+type Imported struct {
+	Recipe   models.Recipe `json:"recipe"`
+	ImageURL string        `json:"image_url,omitempty"`
+	Source   string        `json:"source"`
+}
+
+const (
+	SourceJSONLD    = "json-ld"
+	SourceMicrodata = "microdata"
+)
+
+// Parse находит все рецепты в HTML-странице или JSON-LD документе.
+func Parse(content string) ([]Imported, error) {
+	content = strings.TrimSpace(strings.TrimPrefix(content, "\ufeff"))
+
+	var items []map[string]interface{}
+	var sources []string
+	if strings.HasPrefix(content, "{") || strings.HasPrefix(content, "[") {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(content), &doc); err != nil {
+			return nil, fmt.Errorf("неверный JSON-LD: %v", err)
+		}
+		for _, item := range findRecipes(doc) {
+			items = append(items, item)
+			sources = append(sources, SourceJSONLD)
+		}
+	} else {
+		page, err := parsePage(content)
+		if err != nil {
+			return nil, err
+		}
+		for _, script := range page.scripts {
+			var doc interface{}
+			// Некорректные блоки JSON-LD на страницах встречаются часто; они просто пропускаются
+			if err := json.Unmarshal([]byte(script), &doc); err != nil {
+				continue
+			}
+			for _, item := range findRecipes(doc) {
+				items = append(items, item)
+				sources = append(sources, SourceJSONLD)
+			}
+		}
+		// Микроданные используются, только если на странице нет JSON-LD с рецептом:
+		// многие сайты дублируют один рецепт в обоих форматах
+		if len(items) == 0 {
+			for _, item := range page.items {
+				items = append(items, item)
+				sources = append(sources, SourceMicrodata)
+			}
+		}
+	}
+
+	var result []Imported
+	for i, item := range items {
+		imported := toRecipe(item)
+		if imported.Recipe.Title == "" && len(imported.Recipe.Ingredients) == 0 {
+			continue
+		}
+		imported.Source = sources[i]
+		result = append(result, imported)
+	}
+
+	if len(result) == 0 {
+		return nil, ErrNoRecipes
+	}
+	return result, nil
+}
+
+// findRecipes обходит JSON-LD документ: массивы, @graph и вложенные объекты
+// (например, рецепт внутри WebPage.mainEntity).
+func findRecipes(doc interface{}) []map[string]interface{} {
+	var result []map[string]interface{}
+	switch value := doc.(type) {
+	case []interface{}:
+		for _, item := range value {
+			result = append(result, findRecipes(item)...)
+		}
+	case map[string]interface{}:
+		if hasType(value, "Recipe") {
+			return []map[string]interface{}{value}
+		}
+		for _, key := range []string{"@graph", "mainEntity", "mainEntityOfPage", "itemListElement", "item"} {
+			if nested, ok := value[key]; ok {
+				result = append(result, findRecipes(nested)...)
+			}
+		}
+	}
+	return result
+}
+
+// hasType проверяет @type, который может быть строкой, массивом или полной ссылкой https://schema.org/Recipe.
+func hasType(item map[string]interface{}, name string) bool {
+	for _, t := range values(item["@type"]) {
+		if t == name || strings.HasSuffix(t, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// values приводит значение JSON к списку строк без очистки от разметки.
+func values(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			result = append(result, values(item)...)
+		}
+		return result
+	case map[string]interface{}:
+		for _, key := range []string{"@value", "text", "name", "url", "@id"} {
+			if nested, ok := v[key]; ok {
+				return values(nested)
+			}
+		}
+	}
+	return nil
+}
+
+func first(value interface{}) string {
+	for _, s := range values(value) {
+		if s = cleanText(s); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// cleanText убирает HTML-теги и сущности и сжимает пробелы; переводы строк сохраняются.
+func cleanText(s string) string {
+	s = lineBreakPattern.ReplaceAllString(s, "\n")
+	s = html.UnescapeString(tagPattern.ReplaceAllString(s, ""))
+	s = strings.ReplaceAll(s, "\u00a0", " ")
+
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(spacePattern.ReplaceAllString(line, " ")); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func toRecipe(item map[string]interface{}) Imported {
+	recipe := models.Recipe{
+		Title:       first(item["name"]),
+		Description: first(item["description"]),
+		Servings:    parseYield(item["recipeYield"]),
+	}
+
+	ingredientsValue, ok := item["recipeIngredient"]
+	if !ok {
+		// Устаревшее свойство, его до сих пор используют некоторые сайты
+		ingredientsValue = item["ingredients"]
+	}
+	for _, line := range values(ingredientsValue) {
+		if line = strings.ReplaceAll(cleanText(line), "\n", " "); line != "" {
+			recipe.Ingredients = append(recipe.Ingredients, line)
+		}
+	}
+
+	// Шаги нумеруются, заголовки разделов («Соус:») остаются без номера
+	var lines []string
+	number := 0
+	for _, line := range instructionLines(item["recipeInstructions"]) {
+		if !strings.HasSuffix(line, ":") {
+			number++
+			line = fmt.Sprintf("%d. %s", number, line)
+		}
+		lines = append(lines, line)
+	}
+	recipe.Instructions = strings.Join(lines, "\n")
+
+	if minutes := ParseDuration(first(item["totalTime"])); minutes > 0 {
+		recipe.CookingTime = minutes
+	} else {
+		recipe.CookingTime = ParseDuration(first(item["prepTime"])) + ParseDuration(first(item["cookTime"]))
+	}
+	if recipe.CookingTime > maxMinutes {
+		recipe.CookingTime = 0
+	}
+
+	imported := Imported{Recipe: recipe}
+	image := imageURL(item["image"])
+	if data, ok := decodeDataURI(image); ok {
+		imported.Recipe.ImageBase64 = data
+	} else {
+		imported.ImageURL = image
+	}
+	return imported
+}
+
+// instructionLines разворачивает recipeInstructions: текст, список строк, HowToStep
+// и HowToSection с вложенными шагами. Название раздела становится отдельной строкой.
+func instructionLines(value interface{}) []string {
+	var result []string
+	switch v := value.(type) {
+	case string:
+		for _, line := range strings.Split(cleanText(v), "\n") {
+			if line = strings.TrimSpace(stepNumberPattern.ReplaceAllString(line, "")); line != "" {
+				result = append(result, line)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			result = append(result, instructionLines(item)...)
+		}
+	case map[string]interface{}:
+		if steps, ok := v["itemListElement"]; ok {
+			if name := first(v["name"]); name != "" {
+				result = append(result, strings.TrimSuffix(name, ":")+":")
+			}
+			return append(result, instructionLines(steps)...)
+		}
+		for _, key := range []string{"text", "description", "name"} {
+			if text, ok := v[key]; ok {
+				return instructionLines(first(text))
+			}
+		}
+	}
+	return result
+}
+
+// parseYield берёт первое число из recipeYield: «4», «4 порции», ["4", "4 servings"].
+func parseYield(value interface{}) int {
+	for _, s := range values(value) {
+		match := numberPattern.FindString(s)
+		if match == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(match); err == nil && n >= 1 && n <= maxServings {
+			return n
+		}
+	}
+	return 0
+}
+
+// ParseDuration переводит длительность ISO 8601 (PT1H30M, P0DT45M) в минуты.
+// Секунды округляются вверх до минуты; неверная строка даёт 0.
+func ParseDuration(value string) int {
+	match := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0
+	}
+
+	var minutes float64
+	for i, factor := range []float64{24 * 60, 60, 1, 1.0 / 60} {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.Replace(match[i+1], ",", ".", 1), 64)
+		if err != nil {
+			return 0
+		}
+		minutes += n * factor
+	}
+	if minutes > 0 && minutes < 1 {
+		return 1
+	}
+	return int(minutes + 0.5)
+}
+
+// FormatDuration записывает минуты в формате ISO 8601: 90 → PT1H30M.
+func FormatDuration(minutes int) string {
+	if minutes <= 0 {
+		return ""
+	}
+	result := "PT"
+	if minutes >= 60 {
+		result += fmt.Sprintf("%dH", minutes/60)
+	}
+	if minutes%60 != 0 {
+		result += fmt.Sprintf("%dM", minutes%60)
+	}
+	return result
+}
+
+// imageURL выбирает первую картинку: строку, ImageObject или массив из них.
+func imageURL(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		for _, item := range v {
+			if url := imageURL(item); url != "" {
+				return url
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"url", "contentUrl", "@id"} {
+			if url := imageURL(v[key]); url != "" {
+				return url
+			}
+		}
+	}
+	return ""
+}
+
+func decodeDataURI(uri string) (string, bool) {
+	if !strings.HasPrefix(uri, "data:image/") {
+		return "", false
+	}
+	comma := strings.Index(uri, ",")
+	if comma < 0 || !strings.HasSuffix(uri[:comma], ";base64") {
+		return "", false
+	}
+	data := uri[comma+1:]
+	if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+		return "", false
+	}
+	return data, true
+}
+
+// Recipe — рецепт в формате schema.org JSON-LD.
+type Recipe struct {
+	Context            string      `json:"@context"`
+	Type               string      `json:"@type"`
+	Name               string      `json:"name"`
+	Description        string      `json:"description,omitempty"`
+	Image              []string    `json:"image,omitempty"`
+	Author             *Person     `json:"author,omitempty"`
+	DatePublished      string      `json:"datePublished,omitempty"`
+	DateModified       string      `json:"dateModified,omitempty"`
+	TotalTime          string      `json:"totalTime,omitempty"`
+	RecipeYield        string      `json:"recipeYield,omitempty"`
+	RecipeIngredient   []string    `json:"recipeIngredient"`
+	RecipeInstructions []HowToStep `json:"recipeInstructions"`
+	SuitableForDiet    []string    `json:"suitableForDiet,omitempty"`
+	Keywords           string      `json:"keywords,omitempty"`
+	AggregateRating    *Rating     `json:"aggregateRating,omitempty"`
+	URL                string      `json:"url,omitempty"`
+}
+
+type Person struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type HowToStep struct {
+	Type string `json:"@type"`
+	Text string `json:"text"`
+}
+
+type Rating struct {
+	Type        string  `json:"@type"`
+	RatingValue float64 `json:"ratingValue"`
+	RatingCount int     `json:"ratingCount"`
+	BestRating  int     `json:"bestRating"`
+	WorstRating int     `json:"worstRating"`
+}
+
+// Диеты из перечня schema.org RestrictedDiet
+var diets = []struct {
+	label string
+	url   string
+}{
+	{dietary.Vegan, Context + "/VeganDiet"},
+	{dietary.Vegetarian, Context + "/VegetarianDiet"},
+}
+
+// FromRecipe записывает рецепт в JSON-LD. Если image пустой, а у рецепта есть картинка,
+// она встраивается как data: URI; url — адрес страницы рецепта, если он есть.
+// Метки аллергенов и диет уходят в keywords; для сложности в schema.org Recipe свойства нет.
+func FromRecipe(recipe models.Recipe, image, url string) Recipe {
+	result := Recipe{
+		Context:            Context,
+		Type:               "Recipe",
+		Name:               recipe.Title,
+		Description:        recipe.Description,
+		TotalTime:          FormatDuration(recipe.CookingTime),
+		RecipeIngredient:   append([]string{}, recipe.Ingredients...),
+		RecipeInstructions: []HowToStep{},
+		Keywords:           strings.Join(recipe.Labels, ", "),
+		URL:                url,
+	}
+
+	if recipe.AuthorName != "" {
+		result.Author = &Person{Type: "Person", Name: recipe.AuthorName}
+	}
+	if !recipe.CreatedAt.IsZero() {
+		result.DatePublished = recipe.CreatedAt.Format(models.DateLayout)
+	}
+	if !recipe.UpdatedAt.IsZero() {
+		result.DateModified = recipe.UpdatedAt.Format(models.DateLayout)
+	}
+	if recipe.Servings > 0 {
+		result.RecipeYield = strconv.Itoa(recipe.Servings)
+	}
+
+	if image == "" && recipe.ImageBase64 != "" {
+		if data, err := base64.StdEncoding.DecodeString(recipe.ImageBase64); err == nil {
+			image = "data:" + http.DetectContentType(data) + ";base64," + recipe.ImageBase64
+		}
+	}
+	if image != "" {
+		result.Image = []string{image}
+	}
+
+	for _, step := range Steps(recipe.Instructions) {
+		result.RecipeInstructions = append(result.RecipeInstructions, HowToStep{Type: "HowToStep", Text: step})
+	}
+
+	for _, diet := range diets {
+		for _, label := range recipe.Labels {
+			if label == diet.label {
+				result.SuitableForDiet = append(result.SuitableForDiet, diet.url)
+			}
+		}
+	}
+
+	if recipe.RatingCount > 0 {
+		result.AggregateRating = &Rating{
+			Type:        "AggregateRating",
+			RatingValue: recipe.RatingAvg,
+			RatingCount: recipe.RatingCount,
+			BestRating:  5,
+			WorstRating: 1,
+		}
+	}
+
+	return result
+}
+
+// Steps делит инструкцию на шаги по строкам и убирает нумерацию «1.», «Шаг 2)».
+func Steps(instructions string) []string {
+	var steps []string
+	for _, line := range strings.Split(instructions, "\n") {
+		if line = strings.TrimSpace(stepNumberPattern.ReplaceAllString(line, "")); line != "" {
+			steps = append(steps, line)
+		}
+	}
+	return steps
+}
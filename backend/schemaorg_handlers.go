@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"culinary-book/backend/policy"
+	"culinary-book/backend/schemaorg"
+)
+
+const maxSchemaOrgContentSize = 10 << 20
+
+// schemaOrgRecipeHandler отдаёт рецепт в формате schema.org JSON-LD; изображение встраивается как data: URI.
+func schemaOrgRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, principalFromRequest(r), policy.ActionRead, recipeID)
+	if !ok {
+		return
+	}
+
+	data, err := json.MarshalIndent(schemaorg.FromRecipe(*recipe, "", ""), "", "  ")
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при формировании JSON-LD"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/ld+json; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="recipe-%d.jsonld"`, recipe.ID))
+	w.Write(data)
+}
+
+// schemaOrgParseHandler находит рецепты в HTML-странице или JSON-LD и возвращает их для предпросмотра;
+// сохраняет рецепты клиент через /api/create-recipe, предварительно загрузив картинки по image_url.
+func schemaOrgParseHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, err := getUserIDFromRequest(r); err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Content string `json:"content"`
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSchemaOrgContentSize))
+	if err != nil {
+		http.Error(w, `{"error": "Страница слишком большая"}`, http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	imported, err := schemaorg.Parse(req.Content)
+	if errors.Is(err, schemaorg.ErrNoRecipes) {
+		http.Error(w, `{"error": "Рецепт в разметке schema.org не найден"}`, http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		http.Error(w, `{"error": "Не удалось разобрать страницу"}`, http.StatusBadRequest)
+		return
+	}
+
+	for i := range imported {
+		if title := []rune(imported[i].Recipe.Title); len(title) > maxRecipeTitle {
+			imported[i].Recipe.Title = string(title[:maxRecipeTitle])
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"count":   len(imported),
+		"recipes": imported,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"culinary-book/backend/auth"
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+	"culinary-book/backend/schemaorg"
+)
+
+var sharedRecipeTemplate = template.Must(template.New("shared").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Recipe.Title}} — KonKi</title>
+<script type="application/ld+json">{{.JSONLD}}</script>
+<style>
+body { font-family: sans-serif; max-width: 720px; margin: 2em auto; padding: 0 1em; color: #222; }
+img { max-width: 100%; border-radius: 8px; }
+.meta { color: #666; }
+.instructions { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>🍳 {{.Recipe.Title}}</h1>
+<p class="meta">👤 {{.Recipe.AuthorName}} · 🕐 {{.Recipe.CookingTime}} мин · {{.Recipe.Difficulty}}</p>
+{{if .Image}}<img src="{{.Image}}" alt="{{.Recipe.Title}}">{{end}}
+{{if .Recipe.Description}}<p>{{.Recipe.Description}}</p>{{end}}
+<h2>Ингредиенты</h2>
+<ul>{{range .Recipe.Ingredients}}<li>{{.}}</li>{{end}}</ul>
+<h2>Приготовление</h2>
+<p class="instructions">{{.Recipe.Instructions}}</p>
+<p class="meta">Кулинарная книга KonKi</p>
+</body>
+</html>
+`))
+
+func sharePath(share *models.RecipeShare) string {
+	return "/share/" + share.Token
+}
+
+func createShareHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		RecipeID       int `json:"recipe_id"`
+		ExpiresInHours int `json:"expires_in_hours"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionShare, req.RecipeID)
+	if !ok {
+		return
+	}
+
+	share := &models.RecipeShare{
+		RecipeID: recipe.ID,
+		UserID:   userID,
+	}
+	if req.ExpiresInHours > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		share.ExpiresAt = &expiresAt
+	}
+
+	if err := shareRepo.CreateShare(share); err != nil {
+		http.Error(w, `{"error": "Ошибка при создании ссылки"}`, http.StatusInternalServerError)
+		return
+	}
+
+	share.Token = auth.GenerateShareToken(share.ID, share.ExpiresAt)
+	share.Path = sharePath(share)
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Ссылка создана",
+		"share":   share,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func sharesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionShare, recipeID); !ok {
+		return
+	}
+
+	shares, err := shareRepo.GetActiveSharesByRecipe(recipeID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении ссылок"}`, http.StatusInternalServerError)
+		return
+	}
+
+	for i := range shares {
+		shares[i].Token = auth.GenerateShareToken(shares[i].ID, shares[i].ExpiresAt)
+		shares[i].Path = sharePath(&shares[i])
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"count":  len(shares),
+		"shares": shares,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func revokeShareHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	shareID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID ссылки"}`, http.StatusBadRequest)
+		return
+	}
+
+	// Отозвать ссылку может любой, кому разрешено делиться рецептом, а не только её автор:
+	// список ссылок показывается им всем
+	share, err := shareRepo.GetShareByID(shareID)
+	if err != nil {
+		http.Error(w, `{"error": "Ссылка не найдена"}`, http.StatusNotFound)
+		return
+	}
+	if _, ok := loadRecipeForAction(w, userPrincipal(userID), policy.ActionShare, share.RecipeID); !ok {
+		return
+	}
+
+	if err := shareRepo.RevokeShare(share.ID); err != nil {
+		http.Error(w, `{"error": "`+err.Error()+`"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"message": "Ссылка отозвана",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func sharedRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := strings.TrimPrefix(r.URL.Path, "/share/")
+	shareID, err := auth.ParseShareToken(token)
+	if err != nil {
+		http.Error(w, `{"error": "Ссылка недействительна"}`, http.StatusNotFound)
+		return
+	}
+
+	share, err := shareRepo.GetShareByID(shareID)
+	if err != nil || !share.IsActive() {
+		http.Error(w, `{"error": "Ссылка недействительна"}`, http.StatusNotFound)
+		return
+	}
+
+	recipe, ok := loadRecipeForAction(w, policy.ShareLink(share.RecipeID), policy.ActionRead, share.RecipeID)
+	if !ok {
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		response := map[string]interface{}{
+			"status": "ok",
+			"recipe": recipe,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var image template.URL
+	if imgData, err := base64.StdEncoding.DecodeString(recipe.ImageBase64); err == nil && len(imgData) > 0 {
+		image = template.URL("data:" + http.DetectContentType(imgData) + ";base64," + recipe.ImageBase64)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	sharedRecipeTemplate.Execute(w, map[string]interface{}{
+		"Recipe": recipe,
+		"Image":  image,
+		"JSONLD": schemaorg.FromRecipe(*recipe, string(image), ""),
+	})
+}
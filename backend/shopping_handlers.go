@@ -0,0 +1,410 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"culinary-book/backend/ingredients"
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+)
+
+const (
+	maxShoppingRecipes    = 50
+	maxShoppingMultiplier = 20
+	maxShoppingNameLength = 200
+)
+
+type shoppingGroup struct {
+	Category string                `json:"category"`
+	Items    []models.ShoppingItem `json:"items"`
+}
+
+// groupShoppingItems раскладывает строки по отделам магазина в порядке ingredients.Categories.
+func groupShoppingItems(items []models.ShoppingItem) []shoppingGroup {
+	byCategory := make(map[string][]models.ShoppingItem)
+	for _, item := range items {
+		item.Display = ingredients.FormatQuantity(item.Quantity, item.Unit)
+		category := item.Category
+		if !ingredients.IsValidCategory(category) {
+			category = ingredients.CategoryOther
+		}
+		byCategory[category] = append(byCategory[category], item)
+	}
+
+	groups := []shoppingGroup{}
+	for _, category := range ingredients.Categories {
+		if len(byCategory[category]) > 0 {
+			groups = append(groups, shoppingGroup{Category: category, Items: byCategory[category]})
+		}
+	}
+	return groups
+}
+
+// aggregateIngredients складывает одинаковые ингредиенты из разных рецептов с учётом множителя.
+func aggregateIngredients(recipes []*models.Recipe, multipliers []float64) []models.ShoppingItem {
+	var items []models.ShoppingItem
+	index := make(map[string]int)
+	sources := make(map[string][]string)
+
+	for i, recipe := range recipes {
+		for _, line := range recipe.Ingredients {
+			ingredient := ingredients.Parse(line).Scale(multipliers[i])
+			if ingredient.Name == "" {
+				continue
+			}
+
+			key := ingredients.Key(ingredient.Name) + "|" + ingredient.Unit
+			sources[key] = append(sources[key], recipe.Title)
+
+			if at, ok := index[key]; ok {
+				items[at].Quantity += ingredient.Quantity
+				continue
+			}
+
+			index[key] = len(items)
+			items = append(items, models.ShoppingItem{
+				Name:     ingredient.Name,
+				NameKey:  ingredients.Key(ingredient.Name),
+				Quantity: ingredient.Quantity,
+				Unit:     ingredient.Unit,
+				Category: ingredients.Category(ingredient.Name),
+			})
+		}
+	}
+
+	for key, at := range index {
+		items[at].Sources = joinSources(sources[key])
+	}
+
+	return items
+}
+
+func joinSources(titles []string) string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, title := range titles {
+		if !seen[title] {
+			seen[title] = true
+			unique = append(unique, title)
+		}
+	}
+	return strings.Join(unique, ", ")
+}
+
+func writeShoppingList(w http.ResponseWriter, userID int) {
+	items, err := shoppingRepo.GetItems(userID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении списка покупок"}`, http.StatusInternalServerError)
+		return
+	}
+
+	checked := 0
+	for _, item := range items {
+		if item.Checked {
+			checked++
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"count":   len(items),
+		"checked": checked,
+		"groups":  groupShoppingItems(items),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func shoppingListHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	writeShoppingList(w, userID)
+}
+
+func generateShoppingListHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Recipes []models.ShoppingRecipe `json:"recipes"`
+		From    string                  `json:"from"`
+		To      string                  `json:"to"`
+		Replace bool                    `json:"replace"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	principal := userPrincipal(userID)
+
+	var recipes []*models.Recipe
+	var multipliers []float64
+
+	for _, selected := range req.Recipes {
+		if selected.Multiplier == 0 {
+			selected.Multiplier = 1
+		}
+		if selected.Multiplier < 0 || selected.Multiplier > maxShoppingMultiplier {
+			http.Error(w, `{"error": "Множитель должен быть от 0 до 20"}`, http.StatusBadRequest)
+			return
+		}
+
+		recipe, ok := loadRecipeForAction(w, principal, policy.ActionRead, selected.RecipeID)
+		if !ok {
+			return
+		}
+		recipes = append(recipes, recipe)
+		multipliers = append(multipliers, selected.Multiplier)
+	}
+
+	// Рецепты из плана питания: каждое появление в плане добавляет рецепт ещё раз
+	if req.From != "" || req.To != "" {
+		from, errFrom := time.Parse(models.DateLayout, req.From)
+		to, errTo := time.Parse(models.DateLayout, req.To)
+		if errFrom != nil || errTo != nil || to.Before(from) || to.Sub(from) > maxMealPlanRangeDays*24*time.Hour {
+			http.Error(w, `{"error": "Неверный период плана питания"}`, http.StatusBadRequest)
+			return
+		}
+
+		entries, err := mealPlanRepo.GetEntries(userID, from, to)
+		if err != nil {
+			http.Error(w, `{"error": "Ошибка при получении плана"}`, http.StatusInternalServerError)
+			return
+		}
+
+		for _, entry := range entries {
+			if entry.RecipeID == nil {
+				continue
+			}
+			recipe, err := recipeRepo.GetRecipeByID(*entry.RecipeID)
+			if err != nil || !policy.Can(principal, policy.ActionRead, recipe) {
+				continue
+			}
+			recipes = append(recipes, recipe)
+			multipliers = append(multipliers, 1)
+		}
+	}
+
+	if len(recipes) == 0 || len(recipes) > maxShoppingRecipes {
+		http.Error(w, `{"error": "Выберите от 1 до 50 рецептов"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := shoppingRepo.MergeItems(userID, aggregateIngredients(recipes, multipliers), req.Replace); err != nil {
+		http.Error(w, `{"error": "Ошибка при составлении списка покупок"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeShoppingList(w, userID)
+}
+
+// decodeShoppingItem принимает строку целиком («2 кг картошки») или отдельные поля.
+func decodeShoppingItem(w http.ResponseWriter, r *http.Request, item *models.ShoppingItem) bool {
+	var req struct {
+		ID       int     `json:"id"`
+		Text     string  `json:"text"`
+		Name     string  `json:"name"`
+		Quantity float64 `json:"quantity"`
+		Unit     string  `json:"unit"`
+		Category string  `json:"category"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return false
+	}
+
+	item.ID = req.ID
+	if strings.TrimSpace(req.Text) != "" {
+		parsed := ingredients.Parse(req.Text)
+		item.Name, item.Quantity, item.Unit = parsed.Name, parsed.Quantity, parsed.Unit
+	} else {
+		quantity, unit, ok := ingredients.Normalize(req.Quantity, req.Unit)
+		if !ok {
+			http.Error(w, `{"error": "Неизвестная единица измерения"}`, http.StatusBadRequest)
+			return false
+		}
+		item.Name, item.Quantity, item.Unit = strings.TrimSpace(req.Name), quantity, unit
+	}
+
+	if item.Name == "" || len([]rune(item.Name)) > maxShoppingNameLength {
+		http.Error(w, `{"error": "Название покупки должно быть от 1 до 200 символов"}`, http.StatusBadRequest)
+		return false
+	}
+
+	if item.Quantity < 0 {
+		http.Error(w, `{"error": "Количество не может быть отрицательным"}`, http.StatusBadRequest)
+		return false
+	}
+
+	item.NameKey = ingredients.Key(item.Name)
+	item.Category = req.Category
+	if item.Category == "" {
+		item.Category = ingredients.Category(item.Name)
+	}
+	if !ingredients.IsValidCategory(item.Category) {
+		http.Error(w, `{"error": "Неизвестный отдел"}`, http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+func addShoppingItemHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var item models.ShoppingItem
+	if !decodeShoppingItem(w, r, &item) {
+		return
+	}
+	item.Manual = true
+
+	if err := shoppingRepo.MergeItems(userID, []models.ShoppingItem{item}, false); err != nil {
+		http.Error(w, `{"error": "Ошибка при добавлении покупки"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeShoppingList(w, userID)
+}
+
+func updateShoppingItemHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var item models.ShoppingItem
+	if !decodeShoppingItem(w, r, &item) {
+		return
+	}
+	item.UserID = userID
+
+	if err := shoppingRepo.UpdateItem(&item); err != nil {
+		http.Error(w, `{"error": "Покупка не найдена"}`, http.StatusNotFound)
+		return
+	}
+
+	writeShoppingList(w, userID)
+}
+
+func checkShoppingItemHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		ID      int  `json:"id"`
+		Checked bool `json:"checked"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Неверный формат данных"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := shoppingRepo.SetChecked(userID, req.ID, req.Checked); err != nil {
+		http.Error(w, `{"error": "Покупка не найдена"}`, http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"checked": req.Checked,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func deleteShoppingItemHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	itemID, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID покупки"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := shoppingRepo.DeleteItem(userID, itemID); err != nil {
+		http.Error(w, `{"error": "Покупка не найдена"}`, http.StatusNotFound)
+		return
+	}
+
+	writeShoppingList(w, userID)
+}
+
+func clearShoppingListHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := getUserIDFromRequest(r)
+	if err != nil {
+		http.Error(w, `{"error": "Невалидный токен"}`, http.StatusUnauthorized)
+		return
+	}
+
+	checkedOnly := r.URL.Query().Get("checked") == "true"
+	if _, err := shoppingRepo.Clear(userID, checkedOnly); err != nil {
+		http.Error(w, `{"error": "Ошибка при очистке списка"}`, http.StatusInternalServerError)
+		return
+	}
+
+	writeShoppingList(w, userID)
+}
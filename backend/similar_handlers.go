@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"culinary-book/backend/models"
+	"culinary-book/backend/policy"
+	"culinary-book/backend/similarity"
+)
+
+const (
+	similarNeighborsKept = 50
+	defaultSimilarLimit  = 8
+	maxSimilarLimit      = 20
+)
+
+type similarRecipe struct {
+	models.Recipe
+	Similarity float64 `json:"similarity"`
+}
+
+// buildSimilarIndex строит индекс похожих рецептов при запуске; дальше он обновляется
+// при создании, изменении, копировании и удалении рецептов.
+func buildSimilarIndex() {
+	recipes, err := recipeRepo.GetIndexRecipes()
+	if err != nil {
+		log.Printf("⚠️  Не удалось построить индекс похожих рецептов: %v", err)
+		return
+	}
+
+	for _, recipe := range recipes {
+		similarIndex.Upsert(recipe)
+	}
+
+	log.Printf("✅ Индекс похожих рецептов построен: %d", similarIndex.Len())
+}
+
+// similarRecipesHandler возвращает похожие рецепты, которые читатель может открыть:
+// свои, из общих книг и из публичной ленты.
+func similarRecipesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, `{"error": "Метод не разрешен"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal := principalFromRequest(r)
+
+	recipeID, err := strconv.Atoi(r.URL.Query().Get("recipe_id"))
+	if err != nil {
+		http.Error(w, `{"error": "Неверный ID рецепта"}`, http.StatusBadRequest)
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > maxSimilarLimit {
+		limit = defaultSimilarLimit
+	}
+
+	if _, ok := loadRecipeForAction(w, principal, policy.ActionRead, recipeID); !ok {
+		return
+	}
+
+	// Соседи берутся только из кругов, которые читатель может видеть
+	scopes := []string{similarity.ScopePublic}
+	if principal.IsAuthenticated() {
+		scopes = append(scopes, similarity.UserScope(principal.UserID))
+		for cookbookID := range principal.CookbookRoles {
+			scopes = append(scopes, similarity.CookbookScope(cookbookID))
+		}
+	}
+
+	matches := similarIndex.Similar(recipeID, scopes...)
+	ids := make([]int, len(matches))
+	for i, match := range matches {
+		ids[i] = match.RecipeID
+	}
+
+	recipes, err := recipeRepo.GetRecipesByIDs(ids, principal.UserID)
+	if err != nil {
+		http.Error(w, `{"error": "Ошибка при получении рецептов"}`, http.StatusInternalServerError)
+		return
+	}
+	byID := make(map[int]models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		byID[recipe.ID] = recipe
+	}
+
+	similar := []similarRecipe{}
+	for _, match := range matches {
+		if len(similar) == limit {
+			break
+		}
+
+		recipe, ok := byID[match.RecipeID]
+		if !ok || !policy.Can(principal, policy.ActionRead, &recipe) {
+			continue
+		}
+		// Рецепты «по ссылке» открываются только по ссылке и в рекомендации не попадают
+		if recipe.Visibility == models.VisibilityUnlisted && recipe.UserID != principal.UserID {
+			continue
+		}
+
+		similar = append(similar, similarRecipe{Recipe: recipe, Similarity: match.Score})
+	}
+
+	response := map[string]interface{}{
+		"status":  "ok",
+		"count":   len(similar),
+		"recipes": similar,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,408 @@
+// Package similarity подбирает похожие рецепты по ингредиентам, меткам и тексту.
+// Индекс хранится в памяти и обновляется по одному рецепту: списки соседей
+// пересчитываются при добавлении и изменении, поэтому запрос только читает готовый результат.
+//
+// Соседи хранятся отдельно для каждого круга читателей (Scope): публичные рецепты,
+// рецепты одного автора, закрытые рецепты одной общей книги. Так чужие закрытые рецепты
+// не вытесняют из списка те, которые читатель может открыть.
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"culinary-book/backend/ingredients"
+	"culinary-book/backend/models"
+)
+
+// Веса составляющих оценки: доля общих ингредиентов (Jaccard), общих меток (Jaccard)
+// и косинусная близость текстов по TF-IDF.
+const (
+	ingredientWeight = 0.5
+	tagWeight        = 0.2
+	textWeight       = 0.3
+
+	minScore = 0.05
+	// Слова, которые встречаются больше чем в половине рецептов, не используются для поиска кандидатов
+	commonTermShare = 0.5
+	titleRepeat     = 2
+	minTermLength   = 3
+)
+
+var stopWords = map[string]bool{
+	"для": true, "или": true, "как": true, "все": true, "так": true, "это": true, "его": true, "еще": true,
+	"при": true, "под": true, "над": true, "без": true, "после": true, "затем": true, "потом": true,
+	"пока": true, "чтобы": true, "минут": true, "минуты": true, "минуту": true, "часа": true,
+	"добавить": true, "добавьте": true, "положить": true, "выложить": true, "вкусу": true,
+}
+
+// ScopePublic — круг публичных рецептов, которые видят все.
+const ScopePublic = "public"
+
+// UserScope — рецепты автора, которые видит только он сам: личные и открытые по ссылке.
+func UserScope(userID int) string {
+	return "u:" + strconv.Itoa(userID)
+}
+
+// CookbookScope — закрытые рецепты общей книги, которые видят её участники.
+func CookbookScope(cookbookID int) string {
+	return "c:" + strconv.Itoa(cookbookID)
+}
+
+// Scope определяет круг читателей рецепта. Рецепты «по ссылке» в рекомендации
+// попадают только их автору, поэтому относятся к его кругу.
+func Scope(recipe models.Recipe) string {
+	switch {
+	case recipe.Visibility == models.VisibilityPublic:
+		return ScopePublic
+	case recipe.Visibility == models.VisibilityPrivate && recipe.CookbookID != nil:
+		return CookbookScope(*recipe.CookbookID)
+	}
+	return UserScope(recipe.UserID)
+}
+
+// Match — похожий рецепт и оценка сходства от 0 до 1.
+type Match struct {
+	RecipeID int     `json:"recipe_id"`
+	Score    float64 `json:"score"`
+}
+
+type document struct {
+	scope       string
+	ingredients []string
+	tags        []string
+	terms       map[string]int
+	keys        []string
+}
+
+// Tags возвращает метки рецепта для сравнения: аллергены и диеты, сложность и длительность.
+func Tags(recipe models.Recipe) []string {
+	tags := append([]string{}, recipe.Labels...)
+	if recipe.Difficulty != "" {
+		tags = append(tags, "difficulty:"+strings.ToLower(recipe.Difficulty))
+	}
+	switch {
+	case recipe.CookingTime <= 0:
+	case recipe.CookingTime <= 30:
+		tags = append(tags, "time:quick")
+	case recipe.CookingTime <= 90:
+		tags = append(tags, "time:medium")
+	default:
+		tags = append(tags, "time:long")
+	}
+	return tags
+}
+
+// Terms разбивает текст на основы слов с частотами; название рецепта весит больше описания.
+func Terms(recipe models.Recipe) map[string]int {
+	terms := make(map[string]int)
+	add := func(text string, weight int) {
+		for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r)
+		}) {
+			if len([]rune(word)) < minTermLength || stopWords[word] {
+				continue
+			}
+			terms[ingredients.Key(word)] += weight
+		}
+	}
+
+	add(recipe.Title, titleRepeat)
+	add(recipe.Description, 1)
+	add(recipe.Instructions, 1)
+	return terms
+}
+
+func newDocument(recipe models.Recipe) *document {
+	doc := &document{scope: Scope(recipe), tags: Tags(recipe), terms: Terms(recipe)}
+
+	seen := make(map[string]bool)
+	for _, line := range recipe.Ingredients {
+		key := ingredients.Key(ingredients.Parse(line).Name)
+		if key != "" && !seen[key] {
+			seen[key] = true
+			doc.ingredients = append(doc.ingredients, key)
+		}
+	}
+
+	// Ключи обратного индекса: слова ингредиентов, метки и слова текста
+	keys := make(map[string]bool)
+	for _, key := range doc.ingredients {
+		for _, word := range strings.Fields(key) {
+			keys["i:"+word] = true
+		}
+	}
+	for _, tag := range doc.tags {
+		keys["t:"+tag] = true
+	}
+	for term := range doc.terms {
+		keys["w:"+term] = true
+	}
+	for key := range keys {
+		doc.keys = append(doc.keys, key)
+	}
+
+	return doc
+}
+
+// Index хранит признаки рецептов и готовые списки похожих рецептов: recipe_id → круг → соседи.
+type Index struct {
+	mu        sync.RWMutex
+	size      int
+	docs      map[int]*document
+	postings  map[string]map[int]bool
+	neighbors map[int]map[string][]Match
+}
+
+// NewIndex создаёт пустой индекс, который хранит до size похожих рецептов
+// каждого круга читателей на каждый рецепт.
+func NewIndex(size int) *Index {
+	return &Index{
+		size:      size,
+		docs:      make(map[int]*document),
+		postings:  make(map[string]map[int]bool),
+		neighbors: make(map[int]map[string][]Match),
+	}
+}
+
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.docs)
+}
+
+// Similar возвращает похожие рецепты из перечисленных кругов по убыванию оценки.
+// Круг — лишь предварительный отбор: права на каждый рецепт проверяет вызывающий.
+func (x *Index) Similar(recipeID int, scopes ...string) []Match {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var matches []Match
+	for _, scope := range scopes {
+		matches = append(matches, x.neighbors[recipeID][scope]...)
+	}
+	sortMatches(matches)
+	return matches
+}
+
+// Remove убирает рецепт из индекса и из списков соседей других рецептов.
+func (x *Index) Remove(recipeID int) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.removeLocked(recipeID)
+}
+
+// Upsert добавляет или обновляет рецепт: пересчитывает его соседей
+// и вставляет его в списки соседей рецептов, которые оказались похожими.
+func (x *Index) Upsert(recipe models.Recipe) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.removeLocked(recipe.ID)
+
+	doc := newDocument(recipe)
+	x.docs[recipe.ID] = doc
+	for _, key := range doc.keys {
+		if x.postings[key] == nil {
+			x.postings[key] = make(map[int]bool)
+		}
+		x.postings[key][recipe.ID] = true
+	}
+
+	matches := x.matches(recipe.ID, doc)
+	for _, match := range matches {
+		x.insert(match.RecipeID, doc.scope, Match{RecipeID: recipe.ID, Score: match.Score})
+	}
+	x.neighbors[recipe.ID] = x.group(matches)
+}
+
+// matches — все рецепты с оценкой сходства не ниже minScore, без сортировки.
+func (x *Index) matches(recipeID int, doc *document) []Match {
+	var matches []Match
+	for candidateID := range x.candidates(recipeID, doc) {
+		score := x.score(doc, x.docs[candidateID])
+		if score >= minScore {
+			matches = append(matches, Match{RecipeID: candidateID, Score: score})
+		}
+	}
+	return matches
+}
+
+// group раскладывает соседей по кругам читателей, оставляя в каждом не больше size лучших.
+func (x *Index) group(matches []Match) map[string][]Match {
+	grouped := make(map[string][]Match)
+	for _, match := range matches {
+		scope := x.docs[match.RecipeID].scope
+		grouped[scope] = append(grouped[scope], match)
+	}
+	for scope, list := range grouped {
+		sortMatches(list)
+		if len(list) > x.size {
+			grouped[scope] = list[:x.size]
+		}
+	}
+	return grouped
+}
+
+// removeLocked убирает рецепт из индекса. Заполненные списки, из которых он выпал,
+// пересчитываются заново: за пределами списка могли остаться подходящие рецепты.
+func (x *Index) removeLocked(recipeID int) {
+	doc, ok := x.docs[recipeID]
+	if !ok {
+		return
+	}
+
+	for _, key := range doc.keys {
+		delete(x.postings[key], recipeID)
+		if len(x.postings[key]) == 0 {
+			delete(x.postings, key)
+		}
+	}
+	delete(x.docs, recipeID)
+	delete(x.neighbors, recipeID)
+
+	var refill []int
+	for id, scopes := range x.neighbors {
+		matches := scopes[doc.scope]
+		for i, match := range matches {
+			if match.RecipeID != recipeID {
+				continue
+			}
+			if len(matches) == x.size {
+				refill = append(refill, id)
+			} else {
+				scopes[doc.scope] = append(matches[:i:i], matches[i+1:]...)
+			}
+			break
+		}
+	}
+
+	for _, id := range refill {
+		x.neighbors[id] = x.group(x.matches(id, x.docs[id]))
+	}
+}
+
+// candidates — рецепты, у которых есть хотя бы один общий ингредиент или не слишком частое слово.
+// Рецепты, совпадающие только метками, кандидатами не считаются.
+func (x *Index) candidates(recipeID int, doc *document) map[int]bool {
+	limit := int(math.Max(1, commonTermShare*float64(len(x.docs))))
+
+	result := make(map[int]bool)
+	for _, key := range doc.keys {
+		if strings.HasPrefix(key, "t:") {
+			continue
+		}
+		ids := x.postings[key]
+		if strings.HasPrefix(key, "w:") && len(ids) > limit && len(x.docs) > 2 {
+			continue
+		}
+		for id := range ids {
+			if id != recipeID {
+				result[id] = true
+			}
+		}
+	}
+	return result
+}
+
+func (x *Index) score(a, b *document) float64 {
+	return ingredientWeight*ingredientJaccard(a.ingredients, b.ingredients) +
+		tagWeight*jaccard(a.tags, b.tags) +
+		textWeight*x.cosine(a.terms, b.terms)
+}
+
+// insert добавляет рецепт в список соседей recipeID из круга scope, сохраняя порядок и ограничение размера.
+func (x *Index) insert(recipeID int, scope string, match Match) {
+	if x.neighbors[recipeID] == nil {
+		x.neighbors[recipeID] = make(map[string][]Match)
+	}
+	matches := append(x.neighbors[recipeID][scope], match)
+	sortMatches(matches)
+	if len(matches) > x.size {
+		matches = matches[:x.size]
+	}
+	x.neighbors[recipeID][scope] = matches
+}
+
+// idf считается по текущему числу рецептов: при росте индекса веса старых списков
+// немного устаревают, но выравниваются при следующем изменении рецепта.
+func (x *Index) idf(term string) float64 {
+	return math.Log(1 + float64(len(x.docs))/float64(1+len(x.postings["w:"+term])))
+}
+
+func (x *Index) cosine(a, b map[string]int) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for term, countA := range a {
+		weight := x.idf(term)
+		wa := float64(countA) * weight
+		normA += wa * wa
+		if countB, ok := b[term]; ok {
+			dot += wa * float64(countB) * weight
+		}
+	}
+	for term, countB := range b {
+		wb := float64(countB) * x.idf(term)
+		normB += wb * wb
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// ingredientJaccard сравнивает наборы ингредиентов с учётом уточнений:
+// «мук» совпадает с «мук пшеничн» (ingredients.Matches).
+func ingredientJaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	common := 0
+	used := make([]bool, len(b))
+	for _, left := range a {
+		for i, right := range b {
+			if !used[i] && ingredients.Matches(left, right) {
+				used[i] = true
+				common++
+				break
+			}
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool)
+	for _, item := range a {
+		set[item] = true
+	}
+	common := 0
+	for _, item := range b {
+		if set[item] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(set)+len(b)-common)
+}
+
+func sortMatches(matches []Match) {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].RecipeID < matches[j].RecipeID
+	})
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconSettings   = "⚙"
+	avatarSize     = 256
+	maxAvatarBytes = 256 * 1024
+)
+
+type Profile struct {
+	ID                  int        `json:"id"`
+	Username            string     `json:"username"`
+	Email               string     `json:"email"`
+	DisplayName         string     `json:"display_name"`
+	AvatarBase64        string     `json:"avatar_base64"`
+	Bio                 string     `json:"bio"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+func loadProfile() (*Profile, error) {
+	body, err := apiRequest("GET", "/me", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var profileResp struct {
+		User Profile `json:"user"`
+	}
+	json.Unmarshal(body, &profileResp)
+
+	return &profileResp.User, nil
+}
+
+// prepareAvatar обрезает фото по центру в квадрат и уменьшает до размера аватара.
+func prepareAvatar(filePath string) ([]byte, error) {
+	img, err := loadEditableImage(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	img = cropToAspect(img, 1, 0.5)
+	if side := img.Bounds().Dx(); side > avatarSize {
+		img = scaleImage(img, float64(avatarSize)/math.Max(1, float64(side)))
+	}
+
+	return fitImageToLimit(img, maxAvatarBytes)
+}
+
+func avatarImage(avatarBase64 string) *canvas.Image {
+	var resource fyne.Resource = theme.AccountIcon()
+	if data, err := base64.StdEncoding.DecodeString(avatarBase64); err == nil && len(data) > 0 {
+		resource = fyne.NewStaticResource("avatar", data)
+	}
+
+	img := canvas.NewImageFromResource(resource)
+	img.FillMode = canvas.ImageFillContain
+	img.SetMinSize(fyne.NewSize(96, 96))
+	return img
+}
+
+func showAccountWindow() {
+	profile, err := loadProfile()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка загрузки профиля: %v", iconError, err), myWindow)
+		return
+	}
+
+	accountWindow := myApp.NewWindow(fmt.Sprintf("%s Настройки аккаунта", iconSettings))
+	accountWindow.Resize(fyne.NewSize(520, 560))
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem(fmt.Sprintf("%s Профиль", iconUser), createProfileTab(profile, accountWindow)),
+		container.NewTabItem("🔑 Пароль", createPasswordTab(accountWindow)),
+		container.NewTabItem(fmt.Sprintf("%s Удаление", iconDelete), createDeletionTab(profile, accountWindow)),
+	)
+
+	accountWindow.SetContent(tabs)
+	accountWindow.Show()
+}
+
+func createProfileTab(profile *Profile, parent fyne.Window) fyne.CanvasObject {
+	avatarBase64 := profile.AvatarBase64
+	avatarBox := container.NewCenter(avatarImage(avatarBase64))
+
+	displayNameEntry := widget.NewEntry()
+	displayNameEntry.SetText(profile.DisplayName)
+	displayNameEntry.SetPlaceHolder(profile.Username)
+
+	emailEntry := widget.NewEntry()
+	emailEntry.SetText(profile.Email)
+	emailEntry.SetPlaceHolder("name@example.com")
+
+	bioEntry := widget.NewMultiLineEntry()
+	bioEntry.SetText(profile.Bio)
+	bioEntry.SetPlaceHolder("Пара слов о себе и любимой кухне")
+	bioEntry.Wrapping = fyne.TextWrapWord
+	bioEntry.SetMinRowsVisible(4)
+
+	chooseAvatarBtn := widget.NewButton("📁 Выбрать аватар", func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, err := prepareAvatar(reader.URI().Path())
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s %v", iconError, err), parent)
+				return
+			}
+
+			avatarBase64 = base64.StdEncoding.EncodeToString(data)
+			avatarBox.Objects = []fyne.CanvasObject{avatarImage(avatarBase64)}
+			avatarBox.Refresh()
+		}, parent)
+	})
+	removeAvatarBtn := widget.NewButton(fmt.Sprintf("%s Убрать", iconDelete), func() {
+		avatarBase64 = ""
+		avatarBox.Objects = []fyne.CanvasObject{avatarImage("")}
+		avatarBox.Refresh()
+	})
+
+	saveBtn := widget.NewButton(fmt.Sprintf("%s Сохранить", iconSuccess), func() {
+		_, err := apiRequest("PATCH", "/me", map[string]interface{}{
+			"display_name":  displayNameEntry.Text,
+			"email":         emailEntry.Text,
+			"bio":           bioEntry.Text,
+			"avatar_base64": avatarBase64,
+		})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+			return
+		}
+
+		currentUser.Email = emailEntry.Text
+		dialog.ShowInformation(fmt.Sprintf("%s Готово", iconSuccess), "Профиль сохранён", parent)
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	form := widget.NewForm(
+		widget.NewFormItem("Логин:", widget.NewLabel(profile.Username)),
+		widget.NewFormItem("Имя:", displayNameEntry),
+		widget.NewFormItem("Email:", emailEntry),
+		widget.NewFormItem("О себе:", bioEntry),
+	)
+
+	return container.NewVScroll(container.NewVBox(
+		avatarBox,
+		container.NewCenter(container.NewHBox(chooseAvatarBtn, removeAvatarBtn)),
+		form,
+		widget.NewLabel(fmt.Sprintf("%s С нами с %s", iconCalendar, profile.CreatedAt.Local().Format("02.01.2006"))),
+		saveBtn,
+	))
+}
+
+func createPasswordTab(parent fyne.Window) fyne.CanvasObject {
+	currentEntry := widget.NewPasswordEntry()
+	newEntry := widget.NewPasswordEntry()
+	repeatEntry := widget.NewPasswordEntry()
+
+	changeBtn := widget.NewButton("🔑 Сменить пароль", func() {
+		if newEntry.Text != repeatEntry.Text {
+			dialog.ShowError(fmt.Errorf("Новые пароли не совпадают"), parent)
+			return
+		}
+		if len(newEntry.Text) < 6 {
+			dialog.ShowError(fmt.Errorf("Пароль должен быть не менее 6 символов"), parent)
+			return
+		}
+
+		_, err := apiRequest("POST", "/me/password", map[string]interface{}{
+			"current_password": currentEntry.Text,
+			"new_password":     newEntry.Text,
+		})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+			return
+		}
+
+		currentEntry.SetText("")
+		newEntry.SetText("")
+		repeatEntry.SetText("")
+		dialog.ShowInformation(fmt.Sprintf("%s Готово", iconSuccess), "Пароль изменён", parent)
+	})
+
+	return container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Текущий пароль:", currentEntry),
+			widget.NewFormItem("Новый пароль:", newEntry),
+			widget.NewFormItem("Ещё раз:", repeatEntry),
+		),
+		changeBtn,
+	)
+}
+
+func createDeletionTab(profile *Profile, parent fyne.Window) fyne.CanvasObject {
+	box := container.NewVBox()
+
+	exportBtn := widget.NewButton(fmt.Sprintf("%s Скачать архив моих данных", iconArchive), func() {
+		exportArchive(parent)
+	})
+
+	var render func(scheduledAt *time.Time)
+	render = func(scheduledAt *time.Time) {
+		box.Objects = nil
+
+		if scheduledAt != nil {
+			info := widget.NewLabel(fmt.Sprintf("%s Аккаунт будет удалён %s.\nДо этого момента удаление можно отменить.",
+				iconError, scheduledAt.Local().Format("02.01.2006")))
+			info.Wrapping = fyne.TextWrapWord
+			box.Add(info)
+			box.Add(exportBtn)
+			box.Add(widget.NewButton("↩ Отменить удаление", func() {
+				if _, err := apiRequest("POST", "/me/delete/cancel", nil); err != nil {
+					dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+					return
+				}
+				render(nil)
+			}))
+			box.Refresh()
+			return
+		}
+
+		info := widget.NewLabel("После запроса аккаунт хранится ещё 30 дней: можно войти, выгрузить рецепты " +
+			"и передумать. Затем рецепты, отзывы и комментарии удаляются безвозвратно.")
+		info.Wrapping = fyne.TextWrapWord
+
+		passwordEntry := widget.NewPasswordEntry()
+		passwordEntry.SetPlaceHolder("Пароль для подтверждения")
+
+		deleteBtn := widget.NewButton(fmt.Sprintf("%s Удалить аккаунт", iconDelete), func() {
+			dialog.ShowConfirm("Удаление аккаунта",
+				"Перед удалением советуем скачать свои данные.\nЗапросить удаление аккаунта?",
+				func(confirmed bool) {
+					if !confirmed {
+						return
+					}
+
+					body, err := apiRequest("POST", "/me/delete", map[string]interface{}{
+						"password": passwordEntry.Text,
+					})
+					if err != nil {
+						dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+						return
+					}
+
+					var deleteResp struct {
+						ScheduledAt time.Time `json:"scheduled_at"`
+					}
+					json.Unmarshal(body, &deleteResp)
+					render(&deleteResp.ScheduledAt)
+				}, parent)
+		})
+		deleteBtn.Importance = widget.DangerImportance
+
+		box.Add(info)
+		box.Add(exportBtn)
+		box.Add(widget.NewSeparator())
+		box.Add(passwordEntry)
+		box.Add(deleteBtn)
+		box.Refresh()
+	}
+
+	render(profile.DeletionScheduledAt)
+	return box
+}
+
+// warnPendingDeletion напоминает после входа, что аккаунт ждёт удаления.
+func warnPendingDeletion(user *User) {
+	if user == nil || user.DeletionScheduledAt == nil {
+		return
+	}
+
+	dialog.ShowConfirm(fmt.Sprintf("%s Аккаунт ожидает удаления", iconError),
+		fmt.Sprintf("Аккаунт будет удалён %s.\nОтменить удаление?", user.DeletionScheduledAt.Local().Format("02.01.2006")),
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if _, err := apiRequest("POST", "/me/delete/cancel", nil); err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+				return
+			}
+			user.DeletionScheduledAt = nil
+			dialog.ShowInformation(fmt.Sprintf("%s Готово", iconSuccess), "Удаление аккаунта отменено", myWindow)
+		}, myWindow)
+}
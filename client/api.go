@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func apiRequest(method, path string, payload interface{}) ([]byte, error) {
+	if payload == nil {
+		return apiRawRequest(method, path, "", nil)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return apiRawRequest(method, path, "application/json", bytes.NewBuffer(data))
+}
+
+// apiRawRequest отправляет тело как есть, например zip-архив при импорте.
+func apiRawRequest(method, path, contentType string, reqBody io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, getAPIURL()+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if currentToken != "" {
+		req.Header.Set("Authorization", "Bearer "+currentToken)
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Ошибка подключения: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("%s", apiErrorMessage(body))
+	}
+
+	return body, nil
+}
+
+func apiErrorMessage(body []byte) string {
+	var errorResp map[string]interface{}
+	if err := json.Unmarshal(body, &errorResp); err == nil {
+		if msg, ok := errorResp["error"].(string); ok {
+			return msg
+		}
+	}
+	return string(body)
+}
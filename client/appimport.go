@@ -0,0 +1,235 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const iconAppImport = "📥"
+
+// Файлы, которые принимает импорт из других приложений; zip может содержать любые из них
+var appImportExtensions = []string{".paprikarecipes", ".paprikarecipe", ".mmf", ".mm", ".txt", ".cook", ".zip"}
+
+var appImportFormats = map[string]string{
+	"paprika":    "Paprika",
+	"mealmaster": "MealMaster",
+	"cooklang":   "Cooklang",
+}
+
+// AppImportItem — рецепт из файла другого приложения в предпросмотре импорта.
+type AppImportItem struct {
+	Index        int      `json:"index"`
+	Source       string   `json:"source"`
+	Format       string   `json:"format"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Ingredients  []string `json:"ingredients"`
+	Instructions string   `json:"instructions"`
+	CookingTime  int      `json:"cooking_time"`
+	Servings     int      `json:"servings"`
+	HasImage     bool     `json:"has_image"`
+	Exists       bool     `json:"exists"`
+	Warnings     []string `json:"warnings"`
+}
+
+type AppImportFailure struct {
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+type AppImportPreviewResponse struct {
+	Status   string             `json:"status"`
+	Recipes  []AppImportItem    `json:"recipes"`
+	Failures []AppImportFailure `json:"failures"`
+}
+
+// bundleCooklangImage упаковывает рецепт .cook в zip вместе с картинкой, лежащей рядом под тем же именем.
+// Если картинки нет, файл отправляется как есть.
+func bundleCooklangImage(path string, data []byte) (string, []byte) {
+	name := filepath.Base(path)
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+
+	for _, ext := range []string{".jpg", ".jpeg", ".png", ".webp", ".JPG", ".JPEG", ".PNG", ".WEBP"} {
+		image, err := os.ReadFile(base + ext)
+		if err != nil {
+			continue
+		}
+
+		var buffer bytes.Buffer
+		zw := zip.NewWriter(&buffer)
+		files := []struct {
+			name    string
+			content []byte
+		}{{name, data}, {filepath.Base(base + ext), image}}
+		for _, file := range files {
+			writer, err := zw.Create(file.name)
+			if err != nil {
+				return name, data
+			}
+			writer.Write(file.content)
+		}
+		if zw.Close() != nil {
+			return name, data
+		}
+		return strings.TrimSuffix(name, filepath.Ext(name)) + archiveExtension, buffer.Bytes()
+	}
+	return name, data
+}
+
+func appImportSummary(item AppImportItem) string {
+	parts := []string{
+		appImportFormats[item.Format],
+		fmt.Sprintf("%d ингр.", len(item.Ingredients)),
+		fmt.Sprintf("%d шагов", len(splitSteps(item.Instructions))),
+	}
+	if item.CookingTime > 0 {
+		parts = append(parts, fmt.Sprintf("%s %d мин", iconTime, item.CookingTime))
+	}
+	if item.Servings > 0 {
+		parts = append(parts, fmt.Sprintf("🍽️ %d", item.Servings))
+	}
+	if item.HasImage {
+		parts = append(parts, "📷")
+	}
+	if item.Exists {
+		parts = append(parts, "уже есть в книге")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func appImportDetails(item AppImportItem) string {
+	var b strings.Builder
+	if item.Description != "" {
+		b.WriteString(item.Description + "\n\n")
+	}
+	b.WriteString("Ингредиенты:\n")
+	for _, ingredient := range item.Ingredients {
+		fmt.Fprintf(&b, "%s %s\n", iconBullet, ingredient)
+	}
+	b.WriteString("\nПриготовление:\n")
+	for i, step := range splitSteps(item.Instructions) {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// importFromApp — импорт из Paprika, MealMaster и Cooklang. Сервер сначала только разбирает файл,
+// пользователь видит, что будет импортировано и что не прочиталось, и выбирает рецепты;
+// затем тот же файл отправляется ещё раз вместе с номерами выбранных рецептов.
+func importFromApp(parent fyne.Window) {
+	openFileWithDialog("Выберите файл Paprika, MealMaster или Cooklang", appImportExtensions, parent, func(path string, data []byte) {
+		name := filepath.Base(path)
+		if strings.EqualFold(filepath.Ext(path), ".cook") {
+			name, data = bundleCooklangImage(path, data)
+		}
+
+		body, err := apiRawRequest("POST", "/app-import/preview?name="+url.QueryEscape(name), "application/octet-stream", bytes.NewReader(data))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка чтения файла: %v", iconError, err), parent)
+			return
+		}
+
+		var preview AppImportPreviewResponse
+		json.Unmarshal(body, &preview)
+		showAppImportPreview(name, data, preview)
+	})
+}
+
+func showAppImportPreview(name string, data []byte, preview AppImportPreviewResponse) {
+	previewWindow := myApp.NewWindow(fmt.Sprintf("%s Импорт: %s", iconAppImport, name))
+	previewWindow.Resize(fyne.NewSize(640, 700))
+
+	results := container.NewVBox(widget.NewLabelWithStyle(
+		fmt.Sprintf("Будет импортировано рецептов: %d", len(preview.Recipes)),
+		fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+
+	checks := make([]*widget.Check, len(preview.Recipes))
+	for i, item := range preview.Recipes {
+		checks[i] = widget.NewCheck(item.Title, nil)
+		checks[i].SetChecked(true)
+
+		details := widget.NewLabel(appImportDetails(item))
+		details.Wrapping = fyne.TextWrapWord
+
+		box := container.NewVBox(checks[i], widget.NewLabel(appImportSummary(item)))
+		for _, warning := range item.Warnings {
+			label := widget.NewLabel(fmt.Sprintf("%s %s", iconWarning, warning))
+			label.Wrapping = fyne.TextWrapWord
+			box.Add(label)
+		}
+		box.Add(widget.NewAccordion(widget.NewAccordionItem("Ингредиенты и шаги", details)))
+		results.Add(box)
+		results.Add(widget.NewSeparator())
+	}
+
+	if len(preview.Failures) > 0 {
+		results.Add(widget.NewLabelWithStyle(
+			fmt.Sprintf("%s Не удалось прочитать: %d", iconWarning, len(preview.Failures)),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, failure := range preview.Failures {
+			label := widget.NewLabel(fmt.Sprintf("%s %s: %s", iconBullet, failure.Source, failure.Reason))
+			label.Wrapping = fyne.TextWrapWord
+			results.Add(label)
+		}
+	}
+
+	modeRadio := widget.NewRadioGroup([]string{importModeSkip, importModeOverwrite, importModeDuplicate}, nil)
+	modeRadio.SetSelected(importModeSkip)
+	modeRadio.Horizontal = true
+
+	var importBtn *widget.Button
+	importBtn = widget.NewButton(fmt.Sprintf("%s Импортировать отмеченные", iconAppImport), func() {
+		var selected []string
+		for i, check := range checks {
+			if check.Checked {
+				selected = append(selected, strconv.Itoa(preview.Recipes[i].Index))
+			}
+		}
+		if len(selected) == 0 {
+			dialog.ShowError(fmt.Errorf("%s Отметьте хотя бы один рецепт", iconError), previewWindow)
+			return
+		}
+
+		importBtn.Disable()
+		go func() {
+			path := fmt.Sprintf("/app-import?name=%s&mode=%s&select=%s",
+				url.QueryEscape(name), importModeValue(modeRadio.Selected), strings.Join(selected, ","))
+			body, err := apiRawRequest("POST", path, "application/octet-stream", bytes.NewReader(data))
+			if err != nil {
+				importBtn.Enable()
+				dialog.ShowError(fmt.Errorf("%s Ошибка импорта: %v", iconError, err), previewWindow)
+				return
+			}
+			previewWindow.Close()
+			showImportResult(body, myWindow)
+		}()
+	})
+	if len(preview.Recipes) == 0 {
+		importBtn.Disable()
+	}
+
+	previewWindow.SetContent(container.NewBorder(
+		nil,
+		container.NewVBox(
+			widget.NewSeparator(),
+			widget.NewLabel("Если рецепт с таким названием уже есть:"),
+			modeRadio,
+			container.NewHBox(importBtn),
+		),
+		nil, nil,
+		container.NewVScroll(results),
+	))
+	previewWindow.Show()
+}
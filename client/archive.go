@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconArchive         = "📦"
+	archiveExtension    = ".zip"
+	maxImportWarnings   = 10
+	importModeSkip      = "Пропустить"
+	importModeOverwrite = "Заменить существующий"
+	importModeDuplicate = "Создать копию"
+)
+
+var errNoNativeDialog = errors.New("системный диалог выбора файла недоступен")
+
+type ImportResult struct {
+	Created     int      `json:"created"`
+	Updated     int      `json:"updated"`
+	Skipped     int      `json:"skipped"`
+	Favorites   int      `json:"favorites"`
+	Collections int      `json:"collections"`
+	Reviews     int      `json:"reviews"`
+	Warnings    []string `json:"warnings"`
+}
+
+type ImportResponse struct {
+	Status string       `json:"status"`
+	Result ImportResult `json:"result"`
+}
+
+func importModeValue(label string) string {
+	switch label {
+	case importModeOverwrite:
+		return "overwrite"
+	case importModeDuplicate:
+		return "duplicate"
+	}
+	return "skip"
+}
+
+// createFileMenu — меню «Файл» главного окна: архив аккаунта и импорт рецептов.
+func createFileMenu() *fyne.MainMenu {
+	return fyne.NewMainMenu(fyne.NewMenu("Файл",
+		fyne.NewMenuItem(fmt.Sprintf("%s Экспорт…", iconArchive), func() {
+			exportArchive(myWindow)
+		}),
+		fyne.NewMenuItem(fmt.Sprintf("%s Импорт…", iconArchive), func() {
+			importArchive(myWindow)
+		}),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(fmt.Sprintf("%s Экспорт в Markdown…", iconMarkdown), func() {
+			exportMarkdown(0, myWindow)
+		}),
+		fyne.NewMenuItem(fmt.Sprintf("%s Импорт Markdown…", iconMarkdown), func() {
+			importMarkdown(myWindow)
+		}),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(fmt.Sprintf("%s Книга в PDF…", iconPDF), func() {
+			showPDFBookDialog(myWindow)
+		}),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(fmt.Sprintf("%s Импорт со страницы…", iconWebImport), func() {
+			showWebImportWindow()
+		}),
+		fyne.NewMenuItem(fmt.Sprintf("%s Импорт из Paprika, MealMaster, Cooklang…", iconAppImport), func() {
+			importFromApp(myWindow)
+		}),
+	))
+}
+
+func homeLister() fyne.ListableURI {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	dir, err := storage.ListerForURI(storage.NewFileURI(home))
+	if err != nil {
+		return nil
+	}
+	return dir
+}
+
+// saveFileWithDialog сохраняет данные в файл, выбранный в системном диалоге;
+// если системного диалога нет, показывается диалог Fyne.
+func saveFileWithDialog(title, fileName string, extensions []string, data []byte, parent fyne.Window, onSaved func()) {
+	go func() {
+		path, err := nativeFileDialog(true, title, fileName, extensions)
+		if err == nil {
+			if path == "" {
+				return
+			}
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка записи: %v", iconError, err), parent)
+				return
+			}
+			onSaved()
+			return
+		}
+
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			if _, err := writer.Write(data); err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка записи: %v", iconError, err), parent)
+				return
+			}
+			onSaved()
+		}, parent)
+		saveDialog.SetFileName(fileName)
+		saveDialog.SetFilter(storage.NewExtensionFileFilter(extensions))
+		if dir := homeLister(); dir != nil {
+			saveDialog.SetLocation(dir)
+		}
+		saveDialog.Show()
+	}()
+}
+
+// openFileWithDialog читает файл, выбранный в системном диалоге или, если его нет, в диалоге Fyne.
+// Вместе с содержимым передаётся путь к файлу: по нему находятся файлы рядом, например картинки.
+func openFileWithDialog(title string, extensions []string, parent fyne.Window, onOpened func(path string, data []byte)) {
+	go func() {
+		path, err := nativeFileDialog(false, title, "", extensions)
+		if err == nil {
+			if path == "" {
+				return
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка чтения: %v", iconError, err), parent)
+				return
+			}
+			onOpened(path, data)
+			return
+		}
+
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка чтения: %v", iconError, err), parent)
+				return
+			}
+			onOpened(reader.URI().Path(), data)
+		}, parent)
+		openDialog.SetFilter(storage.NewExtensionFileFilter(extensions))
+		if dir := homeLister(); dir != nil {
+			openDialog.SetLocation(dir)
+		}
+		openDialog.Show()
+	}()
+}
+
+// exportArchive скачивает архив со всеми рецептами, изображениями, избранным, коллекциями и отзывами.
+func exportArchive(parent fyne.Window) {
+	body, err := apiRequest("GET", "/export", nil)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка выгрузки: %v", iconError, err), parent)
+		return
+	}
+
+	fileName := fmt.Sprintf("culinary-book-%s%s", time.Now().Format("2006-01-02"), archiveExtension)
+	saveFileWithDialog("Сохранить архив кулинарной книги", fileName, []string{archiveExtension}, body, parent, func() {
+		dialog.ShowInformation(fmt.Sprintf("%s Готово", iconSuccess), "Архив сохранен", parent)
+	})
+}
+
+// chooseFolderWithDialog предлагает выбрать папку в системном диалоге или, если его нет, в диалоге Fyne.
+func chooseFolderWithDialog(title string, parent fyne.Window, onChosen func(dir string)) {
+	go func() {
+		dir, err := nativeFolderDialog(title)
+		if err == nil {
+			if dir != "" {
+				onChosen(dir)
+			}
+			return
+		}
+
+		folderDialog := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil || uri == nil {
+				return
+			}
+			onChosen(uri.Path())
+		}, parent)
+		if dir := homeLister(); dir != nil {
+			folderDialog.SetLocation(dir)
+		}
+		folderDialog.Show()
+	}()
+}
+
+// showImportModeDialog спрашивает, что делать с рецептами, название которых уже есть у пользователя.
+func showImportModeDialog(parent fyne.Window, onConfirmed func(mode string)) {
+	modeRadio := widget.NewRadioGroup([]string{importModeSkip, importModeOverwrite, importModeDuplicate}, nil)
+	modeRadio.SetSelected(importModeSkip)
+
+	content := container.NewVBox(
+		widget.NewLabel("Если рецепт с таким названием уже есть:"),
+		modeRadio,
+	)
+
+	dialog.ShowCustomConfirm(fmt.Sprintf("%s Импорт", iconArchive), "Импортировать", "Отмена", content, func(confirmed bool) {
+		if confirmed {
+			onConfirmed(importModeValue(modeRadio.Selected))
+		}
+	}, parent)
+}
+
+// showImportResult показывает итог импорта и обновляет список рецептов.
+func showImportResult(body []byte, parent fyne.Window) {
+	var importResp ImportResponse
+	json.Unmarshal(body, &importResp)
+	result := importResp.Result
+
+	lines := []string{
+		fmt.Sprintf("Добавлено рецептов: %d", result.Created),
+		fmt.Sprintf("Заменено: %d", result.Updated),
+		fmt.Sprintf("Пропущено: %d", result.Skipped),
+	}
+	if result.Favorites > 0 {
+		lines = append(lines, fmt.Sprintf("Восстановлено в избранном: %d", result.Favorites))
+	}
+	if result.Collections > 0 {
+		lines = append(lines, fmt.Sprintf("Создано коллекций: %d", result.Collections))
+	}
+	if result.Reviews > 0 {
+		lines = append(lines, fmt.Sprintf("Восстановлено отзывов: %d", result.Reviews))
+	}
+	if len(result.Warnings) > 0 {
+		lines = append(lines, "")
+		for i, warning := range result.Warnings {
+			if i == maxImportWarnings {
+				lines = append(lines, fmt.Sprintf("…и еще %d", len(result.Warnings)-maxImportWarnings))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("%s %s", iconBullet, warning))
+		}
+	}
+
+	dialog.ShowInformation(fmt.Sprintf("%s Импорт завершен", iconSuccess), strings.Join(lines, "\n"), parent)
+	reloadCurrentListing()
+}
+
+// importArchive восстанавливает рецепты из архива; способ обработки совпадающих рецептов выбирает пользователь.
+func importArchive(parent fyne.Window) {
+	openFileWithDialog("Выберите архив кулинарной книги", []string{archiveExtension}, parent, func(_ string, data []byte) {
+		showImportModeDialog(parent, func(mode string) {
+			body, err := apiRawRequest("POST", "/import?mode="+mode, "application/zip", bytes.NewReader(data))
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка импорта: %v", iconError, err), parent)
+				return
+			}
+			showImportResult(body, parent)
+		})
+	})
+}
@@ -0,0 +1,33 @@
+//go:build linux
+
+package main
+
+import (
+	"os/exec"
+	"time"
+)
+
+// keepScreenAwake не даёт экрану погаснуть, пока открыт режим готовки.
+// На Linux периодически сбрасывает таймер заставки через xdg-screensaver, если он установлен.
+func keepScreenAwake() (stop func()) {
+	path, err := exec.LookPath("xdg-screensaver")
+	if err != nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for {
+			exec.Command(path, "reset").Run()
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
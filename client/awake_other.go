@@ -0,0 +1,8 @@
+//go:build !linux && !windows
+
+package main
+
+// keepScreenAwake на остальных платформах ничего не делает.
+func keepScreenAwake() (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package main
+
+import (
+	"runtime"
+	"syscall"
+)
+
+const (
+	esContinuous      = 0x80000000
+	esDisplayRequired = 0x00000002
+)
+
+var setThreadExecutionState = syscall.NewLazyDLL("kernel32.dll").NewProc("SetThreadExecutionState")
+
+// keepScreenAwake не даёт экрану погаснуть, пока открыт режим готовки.
+// Флаг SetThreadExecutionState действует для потока, поэтому он держится в отдельной горутине.
+func keepScreenAwake() (stop func()) {
+	if setThreadExecutionState.Find() != nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		setThreadExecutionState.Call(uintptr(esContinuous | esDisplayRequired))
+		<-done
+		setThreadExecutionState.Call(uintptr(esContinuous))
+	}()
+
+	return func() { close(done) }
+}
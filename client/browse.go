@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const browsePageSize = 20
+
+var (
+	browseGrid        *fyne.Container
+	browseSearchEntry *widget.Entry
+	browsePageLabel   *widget.Label
+	browsePrevBtn     *widget.Button
+	browseNextBtn     *widget.Button
+	browseSortSelect  *widget.Select
+	browsePage        = 1
+	browseTotal       = 0
+)
+
+var visibilityOptions = []struct {
+	Value string
+	Label string
+}{
+	{"private", "🔒 Только я"},
+	{"unlisted", "🔗 По ссылке"},
+	{"public", "🌍 Публичный"},
+}
+
+func visibilityLabels() []string {
+	labels := make([]string, 0, len(visibilityOptions))
+	for _, opt := range visibilityOptions {
+		labels = append(labels, opt.Label)
+	}
+	return labels
+}
+
+func visibilityLabel(value string) string {
+	for _, opt := range visibilityOptions {
+		if opt.Value == value {
+			return opt.Label
+		}
+	}
+	return visibilityOptions[0].Label
+}
+
+func visibilityValue(label string) string {
+	for _, opt := range visibilityOptions {
+		if opt.Label == label {
+			return opt.Value
+		}
+	}
+	return visibilityOptions[0].Value
+}
+
+func createBrowseTab() fyne.CanvasObject {
+	browseGrid = container.NewGridWrap(fyne.NewSize(250, 220))
+	browsePage = 1
+
+	browseSearchEntry = widget.NewEntry()
+	browseSearchEntry.SetPlaceHolder(fmt.Sprintf("%s Поиск по рецептам сообщества...", iconSearch))
+	browseSearchEntry.OnSubmitted = func(string) {
+		browsePage = 1
+		loadPublicRecipes()
+	}
+
+	searchBtn := widget.NewButton(fmt.Sprintf("%s Найти", iconSearch), func() {
+		browsePage = 1
+		loadPublicRecipes()
+	})
+
+	browsePageLabel = widget.NewLabel("")
+	browsePrevBtn = widget.NewButton("◀ Назад", func() {
+		if browsePage > 1 {
+			browsePage--
+			loadPublicRecipes()
+		}
+	})
+	browseNextBtn = widget.NewButton("Вперёд ▶", func() {
+		if browsePage*browsePageSize < browseTotal {
+			browsePage++
+			loadPublicRecipes()
+		}
+	})
+
+	browseSortSelect = widget.NewSelect(sortLabels(), func(string) {
+		browsePage = 1
+		loadPublicRecipes()
+	})
+	browseSortSelect.Selected = sortOptions[0].Label
+
+	topPanel := container.NewBorder(nil, nil, nil, container.NewHBox(searchBtn, browseSortSelect), browseSearchEntry)
+	pager := container.NewHBox(layout.NewSpacer(), browsePrevBtn, browsePageLabel, browseNextBtn, layout.NewSpacer())
+
+	return container.NewBorder(
+		topPanel,
+		pager,
+		nil,
+		nil,
+		container.NewScroll(browseGrid),
+	)
+}
+
+func loadPublicRecipes() {
+	if browseGrid == nil {
+		return
+	}
+
+	statusLabel.SetText(fmt.Sprintf("%s Статус: Загрузка ленты...", iconTime))
+
+	query := url.Values{}
+	query.Set("page", fmt.Sprint(browsePage))
+	query.Set("limit", fmt.Sprint(browsePageSize))
+	query.Set("sort", sortValue(browseSortSelect.Selected))
+	if browseSearchEntry.Text != "" {
+		query.Set("q", browseSearchEntry.Text)
+	}
+	if activeDietProfileID != 0 {
+		query.Set("safe_for", fmt.Sprint(activeDietProfileID))
+	}
+
+	body, err := apiRequest("GET", "/recipes?"+query.Encode(), nil)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка загрузки ленты: %v", iconError, err), myWindow)
+		statusLabel.SetText(fmt.Sprintf("%s Статус: Ошибка загрузки", iconError))
+		return
+	}
+
+	var feedResp RecipesResponse
+	json.Unmarshal(body, &feedResp)
+
+	browseTotal = feedResp.Total
+	browseGrid.Objects = nil
+	for _, recipe := range feedResp.Recipes {
+		browseGrid.Add(createRecipeCard(recipe))
+	}
+	browseGrid.Refresh()
+
+	pages := (browseTotal + browsePageSize - 1) / browsePageSize
+	if pages == 0 {
+		pages = 1
+	}
+	browsePageLabel.SetText(fmt.Sprintf("Страница %d из %d", browsePage, pages))
+	if browsePage > 1 {
+		browsePrevBtn.Enable()
+	} else {
+		browsePrevBtn.Disable()
+	}
+	if browsePage < pages {
+		browseNextBtn.Enable()
+	} else {
+		browseNextBtn.Disable()
+	}
+
+	statusLabel.SetText(fmt.Sprintf("%s Статус: %d рецептов в ленте", iconSuccess, browseTotal))
+}
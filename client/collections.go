@@ -0,0 +1,461 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconCollection        = "📚"
+	favoritesCollectionID = 0
+	coverWidth            = 480
+	maxCoverBytes         = 256 * 1024
+)
+
+type Collection struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CoverBase64 string `json:"cover_base64"`
+	Builtin     bool   `json:"builtin"`
+	RecipeCount int    `json:"recipe_count"`
+	Contains    bool   `json:"contains"`
+}
+
+type CollectionsResponse struct {
+	Status      string       `json:"status"`
+	Collections []Collection `json:"collections"`
+}
+
+// loadCollections получает коллекции; при recipeID != 0 отмечены те, где уже лежит рецепт.
+func loadCollections(recipeID int) ([]Collection, error) {
+	path := "/collections"
+	if recipeID != 0 {
+		path = fmt.Sprintf("/collections?recipe_id=%d", recipeID)
+	}
+
+	body, err := apiRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var collectionsResp CollectionsResponse
+	if err := json.Unmarshal(body, &collectionsResp); err != nil {
+		return nil, err
+	}
+	return collectionsResp.Collections, nil
+}
+
+func collectionLabel(collection Collection) string {
+	icon := iconCollection
+	if collection.Builtin {
+		icon = iconStarFull
+	}
+	return fmt.Sprintf("%s %s (%d)", icon, collection.Name, collection.RecipeCount)
+}
+
+// prepareCollectionCover обрезает фото до 4:3 и ужимает, чтобы обложка не раздувала список коллекций.
+func prepareCollectionCover(filePath string) ([]byte, error) {
+	img, err := loadEditableImage(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	img = cropToAspect(img, 4.0/3.0, 0.5)
+	if width := img.Bounds().Dx(); width > coverWidth {
+		img = scaleImage(img, float64(coverWidth)/float64(width))
+	}
+
+	return fitImageToLimit(img, maxCoverBytes)
+}
+
+func collectionCover(coverBase64 string, size fyne.Size) *canvas.Image {
+	var resource fyne.Resource = theme.FolderIcon()
+	if data, err := base64.StdEncoding.DecodeString(coverBase64); err == nil && len(data) > 0 {
+		resource = fyne.NewStaticResource("collection_cover", data)
+	}
+
+	img := canvas.NewImageFromResource(resource)
+	img.FillMode = canvas.ImageFillContain
+	img.SetMinSize(size)
+	return img
+}
+
+// refreshAfterCollectionChange перечитывает списки, в которых виден значок избранного.
+func refreshAfterCollectionChange() {
+	if showFavoritesOnly {
+		showOnlyFavorites()
+	} else {
+		loadRecipes()
+	}
+	loadPublicRecipes()
+}
+
+func setRecipeInCollection(collectionID, recipeID int, add bool) error {
+	if add {
+		_, err := apiRequest("POST", "/collections/add", map[string]interface{}{
+			"collection_id": collectionID,
+			"recipe_id":     recipeID,
+		})
+		return err
+	}
+
+	_, err := apiRequest("DELETE", fmt.Sprintf("/collections/remove?collection_id=%d&recipe_id=%d", collectionID, recipeID), nil)
+	return err
+}
+
+// showCollectionPicker заменяет прежний переключатель избранного: рецепт можно положить в несколько коллекций сразу.
+func showCollectionPicker(recipe Recipe) {
+	collections, err := loadCollections(recipe.ID)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка загрузки коллекций: %v", iconError, err), myWindow)
+		return
+	}
+
+	changed := false
+	list := container.NewVBox()
+
+	addCheck := func(collection Collection) {
+		check := widget.NewCheck(collectionLabel(collection), nil)
+		check.SetChecked(collection.Contains)
+		check.OnChanged = func(checked bool) {
+			if err := setRecipeInCollection(collection.ID, recipe.ID, checked); err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+				return
+			}
+			changed = true
+		}
+		list.Add(check)
+	}
+
+	for _, collection := range collections {
+		addCheck(collection)
+	}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Новая коллекция")
+	createBtn := widget.NewButton(iconAdd, func() {
+		name := strings.TrimSpace(nameEntry.Text)
+		if name == "" {
+			return
+		}
+
+		body, err := apiRequest("POST", "/collections/create", map[string]interface{}{"name": name})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+			return
+		}
+
+		var createResp struct {
+			Collection Collection `json:"collection"`
+		}
+		json.Unmarshal(body, &createResp)
+
+		if err := setRecipeInCollection(createResp.Collection.ID, recipe.ID, true); err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+			return
+		}
+
+		createResp.Collection.RecipeCount = 1
+		createResp.Collection.Contains = true
+		addCheck(createResp.Collection)
+		nameEntry.SetText("")
+		changed = true
+	})
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle(recipe.Title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewVScroll(list),
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, nil, createBtn, nameEntry),
+	)
+
+	pickerDialog := dialog.NewCustom(fmt.Sprintf("%s Сохранить в коллекцию", iconCollection), "Готово", content, myWindow)
+	pickerDialog.SetOnClosed(func() {
+		if changed {
+			refreshAfterCollectionChange()
+		}
+	})
+	pickerDialog.Resize(fyne.NewSize(380, 420))
+	pickerDialog.Show()
+}
+
+// moveID переставляет элемент списка на одну позицию вверх или вниз.
+func moveID(ids []int, index, delta int) []int {
+	target := index + delta
+	if target < 0 || target >= len(ids) {
+		return ids
+	}
+	ids[index], ids[target] = ids[target], ids[index]
+	return ids
+}
+
+func showCollectionsWindow() {
+	collectionsWindow := myApp.NewWindow(fmt.Sprintf("%s Коллекции", iconCollection))
+	collectionsWindow.Resize(fyne.NewSize(620, 600))
+
+	content := container.NewVBox()
+
+	var render func()
+	render = func() {
+		collections, err := loadCollections(0)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка загрузки коллекций: %v", iconError, err), collectionsWindow)
+			return
+		}
+
+		content.Objects = nil
+		content.Add(container.NewHBox(
+			layout.NewSpacer(),
+			widget.NewButton(fmt.Sprintf("%s Новая коллекция", iconAdd), func() {
+				showCollectionForm(Collection{}, collectionsWindow, render)
+			}),
+		))
+		content.Add(widget.NewSeparator())
+
+		// Порядок хранится только для пользовательских коллекций, «Избранное» всегда первое
+		var ids []int
+		for _, collection := range collections {
+			if !collection.Builtin {
+				ids = append(ids, collection.ID)
+			}
+		}
+
+		for _, collection := range collections {
+			collection := collection
+
+			info := container.NewVBox(widget.NewLabelWithStyle(collectionLabel(collection),
+				fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			if collection.Description != "" {
+				description := widget.NewLabel(truncateText(collection.Description, 120))
+				description.Wrapping = fyne.TextWrapWord
+				info.Add(description)
+			}
+
+			buttons := container.NewHBox(widget.NewButton("Открыть", func() {
+				showCollectionRecipes(collection, render)
+			}))
+
+			if !collection.Builtin {
+				index := -1
+				for i, id := range ids {
+					if id == collection.ID {
+						index = i
+					}
+				}
+				reorder := func(delta int) {
+					order := moveID(append([]int(nil), ids...), index, delta)
+					if _, err := apiRequest("POST", "/collections/reorder", map[string]interface{}{"ids": order}); err != nil {
+						dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), collectionsWindow)
+						return
+					}
+					render()
+				}
+				buttons.Add(widget.NewButton("▲", func() { reorder(-1) }))
+				buttons.Add(widget.NewButton("▼", func() { reorder(1) }))
+				buttons.Add(widget.NewButton(iconEdit, func() {
+					showCollectionForm(collection, collectionsWindow, render)
+				}))
+				buttons.Add(widget.NewButton(iconDelete, func() {
+					dialog.ShowConfirm("Удаление коллекции",
+						fmt.Sprintf("Удалить коллекцию «%s»? Рецепты останутся на месте.", collection.Name),
+						func(confirmed bool) {
+							if !confirmed {
+								return
+							}
+							if _, err := apiRequest("DELETE", fmt.Sprintf("/collections/delete?id=%d", collection.ID), nil); err != nil {
+								dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), collectionsWindow)
+								return
+							}
+							render()
+						}, collectionsWindow)
+				}))
+			}
+
+			content.Add(container.NewBorder(nil, nil,
+				collectionCover(collection.CoverBase64, fyne.NewSize(96, 72)),
+				buttons,
+				info,
+			))
+			content.Add(widget.NewSeparator())
+		}
+
+		content.Refresh()
+	}
+
+	render()
+
+	collectionsWindow.SetOnClosed(func() {
+		refreshAfterCollectionChange()
+	})
+	collectionsWindow.SetContent(container.NewScroll(content))
+	collectionsWindow.Show()
+}
+
+// showCollectionForm создаёт коллекцию или изменяет существующую (если у неё есть ID).
+func showCollectionForm(collection Collection, parent fyne.Window, onSaved func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(collection.Name)
+	nameEntry.SetPlaceHolder("Например: Ужины за 30 минут")
+
+	descriptionEntry := widget.NewMultiLineEntry()
+	descriptionEntry.SetText(collection.Description)
+	descriptionEntry.Wrapping = fyne.TextWrapWord
+	descriptionEntry.SetMinRowsVisible(3)
+
+	coverBase64 := collection.CoverBase64
+	coverBox := container.NewCenter(collectionCover(coverBase64, fyne.NewSize(160, 120)))
+
+	chooseCoverBtn := widget.NewButton("📁 Выбрать обложку", func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, err := prepareCollectionCover(reader.URI().Path())
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s %v", iconError, err), parent)
+				return
+			}
+
+			coverBase64 = base64.StdEncoding.EncodeToString(data)
+			coverBox.Objects = []fyne.CanvasObject{collectionCover(coverBase64, fyne.NewSize(160, 120))}
+			coverBox.Refresh()
+		}, parent)
+	})
+	removeCoverBtn := widget.NewButton(fmt.Sprintf("%s Убрать", iconDelete), func() {
+		coverBase64 = ""
+		coverBox.Objects = []fyne.CanvasObject{collectionCover("", fyne.NewSize(160, 120))}
+		coverBox.Refresh()
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Название", nameEntry),
+		widget.NewFormItem("Описание", descriptionEntry),
+		widget.NewFormItem("Обложка", container.NewVBox(coverBox, container.NewHBox(chooseCoverBtn, removeCoverBtn))),
+	}
+
+	title := fmt.Sprintf("%s Новая коллекция", iconAdd)
+	if collection.ID != 0 {
+		title = fmt.Sprintf("%s Изменить коллекцию", iconEdit)
+	}
+
+	formDialog := dialog.NewForm(title, "Сохранить", "Отмена", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		payload := map[string]interface{}{
+			"name":         nameEntry.Text,
+			"description":  descriptionEntry.Text,
+			"cover_base64": coverBase64,
+		}
+
+		var err error
+		if collection.ID != 0 {
+			payload["id"] = collection.ID
+			_, err = apiRequest("PUT", "/collections/update", payload)
+		} else {
+			_, err = apiRequest("POST", "/collections/create", payload)
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+			return
+		}
+
+		onSaved()
+	}, parent)
+	formDialog.Resize(fyne.NewSize(460, 460))
+	formDialog.Show()
+}
+
+// showCollectionRecipes показывает рецепты коллекции в сохранённом порядке и позволяет его менять.
+func showCollectionRecipes(collection Collection, onChanged func()) {
+	recipesWindow := myApp.NewWindow(fmt.Sprintf("%s %s", iconCollection, collection.Name))
+	recipesWindow.Resize(fyne.NewSize(560, 560))
+
+	content := container.NewVBox()
+
+	var render func()
+	render = func() {
+		body, err := apiRequest("GET", fmt.Sprintf("/collections/recipes?id=%d", collection.ID), nil)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка загрузки: %v", iconError, err), recipesWindow)
+			return
+		}
+
+		var recipesResp RecipesResponse
+		json.Unmarshal(body, &recipesResp)
+
+		ids := make([]int, len(recipesResp.Recipes))
+		for i, recipe := range recipesResp.Recipes {
+			ids[i] = recipe.ID
+		}
+
+		content.Objects = nil
+		if collection.CoverBase64 != "" {
+			content.Add(container.NewCenter(collectionCover(collection.CoverBase64, fyne.NewSize(240, 180))))
+		}
+		if collection.Description != "" {
+			description := widget.NewLabel(collection.Description)
+			description.Wrapping = fyne.TextWrapWord
+			content.Add(description)
+		}
+		if len(recipesResp.Recipes) == 0 {
+			content.Add(widget.NewLabel("В коллекции пока нет рецептов"))
+		}
+
+		for i, recipe := range recipesResp.Recipes {
+			index, recipe := i, recipe
+
+			reorder := func(delta int) {
+				order := moveID(append([]int(nil), ids...), index, delta)
+				_, err := apiRequest("POST", "/collections/order", map[string]interface{}{
+					"collection_id": collection.ID,
+					"recipe_ids":    order,
+				})
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), recipesWindow)
+					return
+				}
+				render()
+			}
+
+			content.Add(container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					widget.NewButton("▲", func() { reorder(-1) }),
+					widget.NewButton("▼", func() { reorder(1) }),
+					widget.NewButton(iconDelete, func() {
+						if err := setRecipeInCollection(collection.ID, recipe.ID, false); err != nil {
+							dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), recipesWindow)
+							return
+						}
+						render()
+					}),
+				),
+				widget.NewButton(fmt.Sprintf("%s %s", iconRecipe, recipe.Title), func() {
+					showRecipeDetails(recipe)
+				}),
+			))
+		}
+
+		content.Refresh()
+	}
+
+	render()
+
+	recipesWindow.SetOnClosed(onChanged)
+	recipesWindow.SetContent(container.NewScroll(content))
+	recipesWindow.Show()
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const iconComment = "💬"
+
+type Comment struct {
+	ID         int       `json:"id"`
+	RecipeID   int       `json:"recipe_id"`
+	UserID     int       `json:"user_id"`
+	AuthorName string    `json:"author_name"`
+	ParentID   *int      `json:"parent_id"`
+	Body       string    `json:"body"`
+	Hidden     bool      `json:"hidden"`
+	Pinned     bool      `json:"pinned"`
+	Deleted    bool      `json:"deleted"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Replies    []Comment `json:"replies"`
+}
+
+type CommentsResponse struct {
+	Status      string    `json:"status"`
+	Total       int       `json:"total"`
+	HasMore     bool      `json:"has_more"`
+	CanModerate bool      `json:"can_moderate"`
+	Comments    []Comment `json:"comments"`
+}
+
+var unreadComments = map[int]int{}
+
+func loadUnreadComments() {
+	body, err := apiRequest("GET", "/comments/unread", nil)
+	if err != nil {
+		return
+	}
+
+	var unreadResp struct {
+		Unread map[string]int `json:"unread"`
+	}
+	json.Unmarshal(body, &unreadResp)
+
+	unreadComments = map[int]int{}
+	for id, count := range unreadResp.Unread {
+		if recipeID, err := strconv.Atoi(id); err == nil {
+			unreadComments[recipeID] = count
+		}
+	}
+}
+
+func createCommentsSection(recipe Recipe, parent fyne.Window) fyne.CanvasObject {
+	threadsBox := container.NewVBox()
+	page := 1
+	var comments []Comment
+	canModerate := false
+
+	moreBtn := widget.NewButton("Показать ещё", nil)
+	moreBtn.Hide()
+
+	var render func()
+	var loadPage func(reset bool)
+
+	loadPage = func(reset bool) {
+		if reset {
+			page = 1
+			comments = nil
+		}
+
+		body, err := apiRequest("GET", fmt.Sprintf("/comments?recipe_id=%d&page=%d", recipe.ID, page), nil)
+		if err != nil {
+			threadsBox.Objects = []fyne.CanvasObject{widget.NewLabel("Не удалось загрузить комментарии")}
+			threadsBox.Refresh()
+			return
+		}
+
+		var commentsResp CommentsResponse
+		json.Unmarshal(body, &commentsResp)
+
+		comments = append(comments, commentsResp.Comments...)
+		canModerate = commentsResp.CanModerate
+		if commentsResp.HasMore {
+			moreBtn.Show()
+		} else {
+			moreBtn.Hide()
+		}
+
+		delete(unreadComments, recipe.ID)
+		render()
+	}
+
+	moreBtn.OnTapped = func() {
+		page++
+		loadPage(false)
+	}
+
+	reload := func() { loadPage(true) }
+
+	var addComment func(comment Comment, depth int)
+	addComment = func(comment Comment, depth int) {
+		header := fmt.Sprintf("%s %s · %s", iconUser, comment.AuthorName,
+			comment.CreatedAt.Local().Format("02.01.2006 15:04"))
+		if comment.Pinned {
+			header = "📌 " + header
+		}
+		if comment.UpdatedAt.Sub(comment.CreatedAt) > time.Minute && !comment.Deleted {
+			header += " (изменён)"
+		}
+
+		text := comment.Body
+		switch {
+		case comment.Deleted:
+			text = "Комментарий удалён"
+		case comment.Hidden && text == "":
+			text = "Комментарий скрыт владельцем рецепта"
+		case comment.Hidden:
+			header += " 🙈 скрыт"
+		}
+
+		bodyLabel := widget.NewLabel(text)
+		bodyLabel.Wrapping = fyne.TextWrapWord
+
+		actions := container.NewHBox()
+		if currentUser != nil && !comment.Deleted {
+			actions.Add(widget.NewButton("↩ Ответить", func() {
+				showCommentForm("Ответ", "", parent, func(text string) error {
+					_, err := apiRequest("POST", "/comments/create", map[string]interface{}{
+						"recipe_id": recipe.ID,
+						"parent_id": comment.ID,
+						"body":      text,
+					})
+					return err
+				}, reload)
+			}))
+		}
+		if currentUser != nil && comment.UserID == currentUser.ID && !comment.Deleted {
+			actions.Add(widget.NewButton(iconEdit, func() {
+				showCommentForm("Редактирование", comment.Body, parent, func(text string) error {
+					_, err := apiRequest("PUT", "/comments/update", map[string]interface{}{
+						"id":   comment.ID,
+						"body": text,
+					})
+					return err
+				}, reload)
+			}))
+		}
+		if currentUser != nil && !comment.Deleted && (comment.UserID == currentUser.ID || canModerate) {
+			actions.Add(widget.NewButton(iconDelete, func() {
+				dialog.ShowConfirm("Удаление комментария", "Удалить комментарий?", func(confirmed bool) {
+					if !confirmed {
+						return
+					}
+					if _, err := apiRequest("DELETE", fmt.Sprintf("/comments/delete?id=%d", comment.ID), nil); err != nil {
+						dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+						return
+					}
+					reload()
+				}, parent)
+			}))
+		}
+		if canModerate && !comment.Deleted {
+			hideText := "🙈 Скрыть"
+			if comment.Hidden {
+				hideText = "👁 Показать"
+			}
+			actions.Add(widget.NewButton(hideText, func() {
+				moderateComment(comment.ID, map[string]interface{}{"hidden": !comment.Hidden}, parent, reload)
+			}))
+			if comment.ParentID == nil {
+				pinText := "📌 Закрепить"
+				if comment.Pinned {
+					pinText = "📍 Открепить"
+				}
+				actions.Add(widget.NewButton(pinText, func() {
+					moderateComment(comment.ID, map[string]interface{}{"pinned": !comment.Pinned}, parent, reload)
+				}))
+			}
+		}
+
+		indent := canvas.NewRectangle(color.Transparent)
+		indent.SetMinSize(fyne.NewSize(float32(depth)*24, 1))
+
+		threadsBox.Add(container.NewBorder(nil, nil, indent, nil, container.NewVBox(
+			widget.NewLabelWithStyle(header, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			bodyLabel,
+			actions,
+		)))
+
+		for _, reply := range comment.Replies {
+			addComment(reply, depth+1)
+		}
+	}
+
+	render = func() {
+		threadsBox.Objects = nil
+		if len(comments) == 0 {
+			threadsBox.Add(widget.NewLabel("Комментариев пока нет"))
+		}
+		for _, comment := range comments {
+			addComment(comment, 0)
+			threadsBox.Add(widget.NewSeparator())
+		}
+		threadsBox.Refresh()
+	}
+
+	newCommentBtn := widget.NewButton(fmt.Sprintf("%s Написать комментарий", iconComment), func() {
+		showCommentForm("Новый комментарий", "", parent, func(text string) error {
+			_, err := apiRequest("POST", "/comments/create", map[string]interface{}{
+				"recipe_id": recipe.ID,
+				"body":      text,
+			})
+			return err
+		}, reload)
+	})
+
+	reload()
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(fmt.Sprintf("%s Обсуждение", iconComment),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewSeparator(),
+		container.NewHBox(newCommentBtn, layout.NewSpacer()),
+		threadsBox,
+		moreBtn,
+	)
+}
+
+func moderateComment(commentID int, changes map[string]interface{}, parent fyne.Window, onDone func()) {
+	changes["id"] = commentID
+	if _, err := apiRequest("POST", "/comments/moderate", changes); err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+		return
+	}
+	onDone()
+}
+
+func showCommentForm(title, initial string, parent fyne.Window, submit func(text string) error, onDone func()) {
+	textEntry := widget.NewMultiLineEntry()
+	textEntry.SetText(initial)
+	textEntry.SetPlaceHolder("Например: можно заменить сметану на йогурт?")
+	textEntry.Wrapping = fyne.TextWrapWord
+	textEntry.SetMinRowsVisible(4)
+
+	formDialog := dialog.NewForm(fmt.Sprintf("%s %s", iconComment, title), "Отправить", "Отмена",
+		[]*widget.FormItem{widget.NewFormItem("", textEntry)},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if textEntry.Text == "" {
+				dialog.ShowError(fmt.Errorf("Комментарий пустой"), parent)
+				return
+			}
+			if err := submit(textEntry.Text); err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+				return
+			}
+			onDone()
+		}, parent)
+
+	formDialog.Resize(fyne.NewSize(460, 260))
+	formDialog.Show()
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconCookbook       = "📒"
+	personalCookbook   = "📒 Личные рецепты"
+	cookbookRoleOwner  = "owner"
+	cookbookRoleEditor = "editor"
+	cookbookRoleViewer = "viewer"
+)
+
+type Cookbook struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	OwnerID     int    `json:"owner_id"`
+	Role        string `json:"role"`
+	MemberCount int    `json:"member_count"`
+	RecipeCount int    `json:"recipe_count"`
+}
+
+type CookbookMember struct {
+	UserID   int       `json:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type CookbookInvite struct {
+	ID           int    `json:"id"`
+	CookbookID   int    `json:"cookbook_id"`
+	CookbookName string `json:"cookbook_name"`
+	InviterName  string `json:"inviter_name"`
+	InviteeName  string `json:"invitee_name"`
+	Role         string `json:"role"`
+}
+
+type CookbooksResponse struct {
+	Status    string           `json:"status"`
+	Cookbooks []Cookbook       `json:"cookbooks"`
+	Invites   []CookbookInvite `json:"invites"`
+}
+
+var (
+	cookbooks         []Cookbook
+	cookbookInvites   []CookbookInvite
+	currentCookbookID = 0
+	cookbookSelect    *widget.Select
+	cookbookBtn       *widget.Button
+)
+
+var roleLabels = map[string]string{
+	cookbookRoleOwner:  "владелец",
+	cookbookRoleEditor: "редактор",
+	cookbookRoleViewer: "читатель",
+}
+
+func cookbookLabel(cookbook Cookbook) string {
+	return fmt.Sprintf("👪 %s (%s)", cookbook.Name, roleLabels[cookbook.Role])
+}
+
+func cookbookRole(cookbookID *int) string {
+	if cookbookID == nil {
+		return ""
+	}
+	for _, cookbook := range cookbooks {
+		if cookbook.ID == *cookbookID {
+			return cookbook.Role
+		}
+	}
+	return ""
+}
+
+func canEditRecipe(recipe Recipe) bool {
+	role := cookbookRole(recipe.CookbookID)
+	return isOwnRecipe(recipe) || role == cookbookRoleOwner || role == cookbookRoleEditor
+}
+
+func canManageRecipe(recipe Recipe) bool {
+	return isOwnRecipe(recipe) || cookbookRole(recipe.CookbookID) == cookbookRoleOwner
+}
+
+// canAddToCurrentCookbook сообщает, можно ли создавать рецепты в выбранной книге.
+func canAddToCurrentCookbook() bool {
+	if currentCookbookID == 0 {
+		return true
+	}
+	role := cookbookRole(&currentCookbookID)
+	return role == cookbookRoleOwner || role == cookbookRoleEditor
+}
+
+func createCookbookSwitcher() fyne.CanvasObject {
+	cookbookSelect = widget.NewSelect([]string{personalCookbook}, func(selected string) {
+		id := 0
+		for _, cookbook := range cookbooks {
+			if cookbookLabel(cookbook) == selected {
+				id = cookbook.ID
+			}
+		}
+		if id == currentCookbookID {
+			return
+		}
+		currentCookbookID = id
+		loadRecipes()
+	})
+	cookbookSelect.Selected = personalCookbook
+
+	cookbookBtn = widget.NewButton(fmt.Sprintf("%s Книги", iconCookbook), func() {
+		showCookbooksWindow()
+	})
+
+	return container.NewHBox(cookbookSelect, cookbookBtn)
+}
+
+func loadCookbooks() {
+	body, err := apiRequest("GET", "/cookbooks", nil)
+	if err != nil {
+		return
+	}
+
+	var cookbooksResp CookbooksResponse
+	json.Unmarshal(body, &cookbooksResp)
+
+	cookbooks = cookbooksResp.Cookbooks
+	cookbookInvites = cookbooksResp.Invites
+
+	if cookbookSelect == nil {
+		return
+	}
+
+	options := []string{personalCookbook}
+	selected := personalCookbook
+	for _, cookbook := range cookbooks {
+		options = append(options, cookbookLabel(cookbook))
+		if cookbook.ID == currentCookbookID {
+			selected = cookbookLabel(cookbook)
+		}
+	}
+	if selected == personalCookbook {
+		currentCookbookID = 0
+	}
+	cookbookSelect.Options = options
+	cookbookSelect.Selected = selected
+	cookbookSelect.Refresh()
+
+	if len(cookbookInvites) > 0 {
+		cookbookBtn.SetText(fmt.Sprintf("%s Книги (%d 📩)", iconCookbook, len(cookbookInvites)))
+	} else {
+		cookbookBtn.SetText(fmt.Sprintf("%s Книги", iconCookbook))
+	}
+}
+
+func showCookbooksWindow() {
+	cookbooksWindow := myApp.NewWindow(fmt.Sprintf("%s Общие книги", iconCookbook))
+	cookbooksWindow.Resize(fyne.NewSize(560, 560))
+
+	content := container.NewVBox()
+
+	var render func()
+	render = func() {
+		loadCookbooks()
+		content.Objects = nil
+
+		if len(cookbookInvites) > 0 {
+			content.Add(widget.NewLabelWithStyle("📩 Приглашения", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			for _, invite := range cookbookInvites {
+				invite := invite
+				respond := func(accept bool) {
+					_, err := apiRequest("POST", "/cookbooks/invites/respond", map[string]interface{}{
+						"id":     invite.ID,
+						"accept": accept,
+					})
+					if err != nil {
+						dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), cookbooksWindow)
+						return
+					}
+					render()
+				}
+				content.Add(container.NewHBox(
+					widget.NewLabel(fmt.Sprintf("«%s» от %s — %s", invite.CookbookName, invite.InviterName, roleLabels[invite.Role])),
+					layout.NewSpacer(),
+					widget.NewButton(iconSuccess+" Принять", func() { respond(true) }),
+					widget.NewButton(iconClose, func() { respond(false) }),
+				))
+			}
+			content.Add(widget.NewSeparator())
+		}
+
+		nameEntry := widget.NewEntry()
+		nameEntry.SetPlaceHolder("Например: Семейная кухня")
+		createBtn := widget.NewButton(fmt.Sprintf("%s Создать книгу", iconAdd), func() {
+			if nameEntry.Text == "" {
+				return
+			}
+			if _, err := apiRequest("POST", "/cookbooks/create", map[string]interface{}{"name": nameEntry.Text}); err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), cookbooksWindow)
+				return
+			}
+			render()
+		})
+		content.Add(container.NewBorder(nil, nil, nil, createBtn, nameEntry))
+		content.Add(widget.NewSeparator())
+
+		if len(cookbooks) == 0 {
+			content.Add(widget.NewLabel("Вы пока не состоите ни в одной общей книге"))
+		}
+		for _, cookbook := range cookbooks {
+			cookbook := cookbook
+			content.Add(container.NewHBox(
+				widget.NewLabel(fmt.Sprintf("%s · %s %d · %s %d", cookbookLabel(cookbook),
+					iconUser, cookbook.MemberCount, iconRecipe, cookbook.RecipeCount)),
+				layout.NewSpacer(),
+				widget.NewButton("Участники", func() {
+					showCookbookMembers(cookbook, cookbooksWindow, render)
+				}),
+			))
+		}
+
+		content.Refresh()
+	}
+
+	render()
+
+	cookbooksWindow.SetOnClosed(func() {
+		loadRecipes()
+	})
+	cookbooksWindow.SetContent(container.NewScroll(content))
+	cookbooksWindow.Show()
+}
+
+func showCookbookMembers(cookbook Cookbook, parent fyne.Window, onChanged func()) {
+	membersWindow := myApp.NewWindow(fmt.Sprintf("👪 %s", cookbook.Name))
+	membersWindow.Resize(fyne.NewSize(520, 480))
+
+	isOwner := cookbook.Role == cookbookRoleOwner
+	content := container.NewVBox()
+
+	var render func()
+	render = func() {
+		body, err := apiRequest("GET", fmt.Sprintf("/cookbooks/members?id=%d", cookbook.ID), nil)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), membersWindow)
+			return
+		}
+
+		var membersResp struct {
+			Members []CookbookMember `json:"members"`
+			Invites []CookbookInvite `json:"invites"`
+		}
+		json.Unmarshal(body, &membersResp)
+
+		content.Objects = nil
+		content.Add(widget.NewLabelWithStyle("Участники", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+
+		for _, member := range membersResp.Members {
+			member := member
+			row := container.NewHBox(widget.NewLabel(fmt.Sprintf("%s %s", iconUser, member.Username)), layout.NewSpacer())
+
+			if isOwner && member.Role != cookbookRoleOwner {
+				roleSelect := widget.NewSelect([]string{roleLabels[cookbookRoleEditor], roleLabels[cookbookRoleViewer]}, nil)
+				roleSelect.Selected = roleLabels[member.Role]
+				roleSelect.OnChanged = func(selected string) {
+					role := cookbookRoleViewer
+					if selected == roleLabels[cookbookRoleEditor] {
+						role = cookbookRoleEditor
+					}
+					if role == member.Role {
+						return
+					}
+					_, err := apiRequest("POST", "/cookbooks/members/role", map[string]interface{}{
+						"cookbook_id": cookbook.ID,
+						"user_id":     member.UserID,
+						"role":        role,
+					})
+					if err != nil {
+						dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), membersWindow)
+					}
+					render()
+				}
+				row.Add(roleSelect)
+				row.Add(widget.NewButton(iconDelete, func() {
+					removeCookbookMember(cookbook.ID, member.UserID, membersWindow, render)
+				}))
+			} else {
+				row.Add(widget.NewLabel(roleLabels[member.Role]))
+			}
+
+			content.Add(row)
+		}
+
+		if isOwner {
+			content.Add(widget.NewSeparator())
+			content.Add(widget.NewLabelWithStyle("Пригласить", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+
+			usernameEntry := widget.NewEntry()
+			usernameEntry.SetPlaceHolder("Имя пользователя")
+			roleSelect := widget.NewSelect([]string{roleLabels[cookbookRoleEditor], roleLabels[cookbookRoleViewer]}, nil)
+			roleSelect.Selected = roleLabels[cookbookRoleEditor]
+
+			inviteBtn := widget.NewButton("📩 Отправить", func() {
+				role := cookbookRoleViewer
+				if roleSelect.Selected == roleLabels[cookbookRoleEditor] {
+					role = cookbookRoleEditor
+				}
+				_, err := apiRequest("POST", "/cookbooks/invite", map[string]interface{}{
+					"cookbook_id": cookbook.ID,
+					"username":    usernameEntry.Text,
+					"role":        role,
+				})
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), membersWindow)
+					return
+				}
+				render()
+			})
+			content.Add(container.NewBorder(nil, nil, nil, container.NewHBox(roleSelect, inviteBtn), usernameEntry))
+
+			for _, invite := range membersResp.Invites {
+				invite := invite
+				content.Add(container.NewHBox(
+					widget.NewLabel(fmt.Sprintf("⏳ %s — %s", invite.InviteeName, roleLabels[invite.Role])),
+					layout.NewSpacer(),
+					widget.NewButton("Отменить", func() {
+						_, err := apiRequest("DELETE", fmt.Sprintf("/cookbooks/invites/cancel?cookbook_id=%d&id=%d", cookbook.ID, invite.ID), nil)
+						if err != nil {
+							dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), membersWindow)
+						}
+						render()
+					}),
+				))
+			}
+		}
+
+		content.Add(widget.NewSeparator())
+		if isOwner {
+			content.Add(widget.NewButton(fmt.Sprintf("%s Удалить книгу", iconDelete), func() {
+				dialog.ShowConfirm("Удаление книги",
+					fmt.Sprintf("Удалить книгу «%s»?\nРецепты вернутся в личные рецепты их авторов.", cookbook.Name),
+					func(confirmed bool) {
+						if !confirmed {
+							return
+						}
+						if _, err := apiRequest("DELETE", fmt.Sprintf("/cookbooks/delete?id=%d", cookbook.ID), nil); err != nil {
+							dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), membersWindow)
+							return
+						}
+						membersWindow.Close()
+						onChanged()
+					}, membersWindow)
+			}))
+		} else {
+			content.Add(widget.NewButton(fmt.Sprintf("%s Покинуть книгу", iconExit), func() {
+				removeCookbookMember(cookbook.ID, currentUser.ID, membersWindow, func() {
+					membersWindow.Close()
+					onChanged()
+				})
+			}))
+		}
+
+		content.Refresh()
+	}
+
+	render()
+
+	membersWindow.SetContent(container.NewScroll(content))
+	membersWindow.Show()
+}
+
+func removeCookbookMember(cookbookID, userID int, parent fyne.Window, onDone func()) {
+	_, err := apiRequest("DELETE", fmt.Sprintf("/cookbooks/members/remove?cookbook_id=%d&user_id=%d", cookbookID, userID), nil)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+		return
+	}
+	onDone()
+}
+
+// showMoveRecipeDialog переносит рецепт между личными рецептами и книгами,
+// куда у пользователя есть право добавлять.
+func showMoveRecipeDialog(recipe Recipe, parent fyne.Window) {
+	targets := map[string]int{personalCookbook: 0}
+	options := []string{personalCookbook}
+	current := personalCookbook
+
+	for _, cookbook := range cookbooks {
+		if cookbook.Role != cookbookRoleOwner && cookbook.Role != cookbookRoleEditor {
+			continue
+		}
+		label := cookbookLabel(cookbook)
+		targets[label] = cookbook.ID
+		options = append(options, label)
+		if recipe.CookbookID != nil && *recipe.CookbookID == cookbook.ID {
+			current = label
+		}
+	}
+
+	targetSelect := widget.NewSelect(options, nil)
+	targetSelect.Selected = current
+
+	dialog.ShowCustomConfirm(fmt.Sprintf("%s Переместить рецепт", iconCookbook), "Переместить", "Отмена",
+		targetSelect,
+		func(confirmed bool) {
+			if !confirmed || targetSelect.Selected == current {
+				return
+			}
+			_, err := apiRequest("POST", "/cookbooks/move", map[string]interface{}{
+				"recipe_id":   recipe.ID,
+				"cookbook_id": targets[targetSelect.Selected],
+			})
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+				return
+			}
+			parent.Close()
+			loadCookbooks()
+			loadRecipes()
+		}, parent)
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconCooking = "👩‍🍳"
+
+	sizeNameCookingStep       fyne.ThemeSizeName = "cookingStep"
+	sizeNameCookingIngredient fyne.ThemeSizeName = "cookingIngredient"
+)
+
+// konkiTheme — стандартная тема с крупными размерами текста для режима готовки.
+type konkiTheme struct {
+	fyne.Theme
+}
+
+func (t konkiTheme) Size(name fyne.ThemeSizeName) float32 {
+	switch name {
+	case sizeNameCookingStep:
+		return 40
+	case sizeNameCookingIngredient:
+		return 24
+	}
+	return t.Theme.Size(name)
+}
+
+var (
+	stepNumberPattern = regexp.MustCompile(`(?i)^\s*(?:шаг\s*)?\d+\s*[.):-]\s*`)
+	cookingWindows    = map[int]fyne.Window{}
+)
+
+// splitSteps делит инструкцию на шаги по строкам, убирая нумерацию вида «1.», «2)» и «Шаг 3:».
+func splitSteps(instructions string) []string {
+	var steps []string
+	for _, line := range strings.Split(instructions, "\n") {
+		line = strings.TrimSpace(stepNumberPattern.ReplaceAllString(line, ""))
+		if line != "" {
+			steps = append(steps, line)
+		}
+	}
+	return steps
+}
+
+// ingredientStems возвращает основы значимых слов названия ингредиента без количества и единиц.
+func ingredientStems(ingredient string) []string {
+	var stems []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(ingredient), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		runes := []rune(strings.ReplaceAll(word, "ё", "е"))
+		if len(runes) < 4 {
+			continue
+		}
+		if len(runes) > 5 {
+			runes = runes[:len(runes)-2]
+		}
+		stems = append(stems, string(runes))
+	}
+	return stems
+}
+
+// stepIngredients подбирает ингредиенты, упомянутые в тексте шага.
+func stepIngredients(step string, ingredients []string) []string {
+	text := strings.ReplaceAll(strings.ToLower(step), "ё", "е")
+
+	var used []string
+	for _, ingredient := range ingredients {
+		for _, stem := range ingredientStems(ingredient) {
+			if strings.Contains(text, stem) {
+				used = append(used, ingredient)
+				break
+			}
+		}
+	}
+	return used
+}
+
+func cookingStepKey(recipeID int) string {
+	return fmt.Sprintf("cooking_step_%d", recipeID)
+}
+
+// showCookingMode открывает полноэкранный пошаговый режим. Текущий шаг хранится
+// в настройках приложения, поэтому повторное открытие продолжает с того же места.
+func showCookingMode(recipe Recipe) {
+	if window, ok := cookingWindows[recipe.ID]; ok {
+		window.RequestFocus()
+		return
+	}
+
+	steps := splitSteps(recipe.Instructions)
+	if len(steps) == 0 {
+		return
+	}
+
+	preferences := myApp.Preferences()
+	step := preferences.IntWithFallback(cookingStepKey(recipe.ID), 0)
+	if step < 0 || step >= len(steps) {
+		step = 0
+	}
+
+	cookingWindow := myApp.NewWindow(fmt.Sprintf("%s %s", iconCooking, recipe.Title))
+	cookingWindows[recipe.ID] = cookingWindow
+
+	stopAwake := keepScreenAwake()
+
+	titleLabel := widget.NewLabelWithStyle(fmt.Sprintf("%s %s", iconCooking, recipe.Title),
+		fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	counterLabel := widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{})
+	progress := widget.NewProgressBar()
+	progress.TextFormatter = func() string { return "" }
+
+	stepText := widget.NewRichText()
+	stepText.Wrapping = fyne.TextWrapWord
+
+	ingredientsText := widget.NewRichText()
+	ingredientsText.Wrapping = fyne.TextWrapWord
+
+	stepTimers := container.NewVBox()
+
+	var prevBtn, nextBtn *widget.Button
+
+	render := func() {
+		preferences.SetInt(cookingStepKey(recipe.ID), step)
+
+		counterLabel.SetText(fmt.Sprintf("Шаг %d из %d", step+1, len(steps)))
+		progress.SetValue(float64(step+1) / float64(len(steps)))
+
+		stepText.Segments = []widget.RichTextSegment{&widget.TextSegment{
+			Text: steps[step],
+			Style: widget.RichTextStyle{
+				Alignment: fyne.TextAlignCenter,
+				SizeName:  sizeNameCookingStep,
+				TextStyle: fyne.TextStyle{Bold: true},
+			},
+		}}
+		stepText.Refresh()
+
+		used := stepIngredients(steps[step], recipe.Ingredients)
+		text := "Ингредиенты этого шага не указаны"
+		if len(used) > 0 {
+			text = fmt.Sprintf("%s %s", iconBullet, strings.Join(used, "\n"+iconBullet+" "))
+		}
+		ingredientsText.Segments = []widget.RichTextSegment{&widget.TextSegment{
+			Text:  text,
+			Style: widget.RichTextStyle{SizeName: sizeNameCookingIngredient},
+		}}
+		ingredientsText.Refresh()
+
+		stepTimers.Objects = nil
+		for _, duration := range parseStepDurations(steps[step]) {
+			duration := duration
+			name := fmt.Sprintf("%s, шаг %d", recipe.Title, step+1)
+			stepTimers.Add(widget.NewButton(fmt.Sprintf("%s Запустить %s", iconTimer, duration.Label), func() {
+				if err := startKitchenTimer(name, duration.Duration, recipe.ID); err != nil {
+					dialog.ShowError(err, cookingWindow)
+				}
+			}))
+		}
+		stepTimers.Refresh()
+
+		if step == 0 {
+			prevBtn.Disable()
+		} else {
+			prevBtn.Enable()
+		}
+		if step == len(steps)-1 {
+			nextBtn.SetText(fmt.Sprintf("%s Готово", iconSuccess))
+		} else {
+			nextBtn.SetText("Далее ▶")
+		}
+	}
+
+	closeCooking := func() {
+		cookingWindow.Close()
+	}
+
+	next := func() {
+		if step == len(steps)-1 {
+			// Блюдо готово — в следующий раз начинаем сначала
+			preferences.RemoveValue(cookingStepKey(recipe.ID))
+			closeCooking()
+			return
+		}
+		step++
+		render()
+	}
+	prev := func() {
+		if step > 0 {
+			step--
+			render()
+		}
+	}
+
+	prevBtn = widget.NewButton("◀ Назад", prev)
+	prevBtn.Importance = widget.HighImportance
+	nextBtn = widget.NewButton("Далее ▶", next)
+	nextBtn.Importance = widget.HighImportance
+
+	restartBtn := widget.NewButton("⟲ Сначала", func() {
+		step = 0
+		render()
+	})
+	exitBtn := widget.NewButton(fmt.Sprintf("%s Выйти", iconClose), closeCooking)
+
+	cookingWindow.Canvas().SetOnTypedKey(func(event *fyne.KeyEvent) {
+		switch event.Name {
+		case fyne.KeySpace, fyne.KeyRight, fyne.KeyDown, fyne.KeyPageDown, fyne.KeyReturn, fyne.KeyEnter:
+			next()
+		case fyne.KeyLeft, fyne.KeyUp, fyne.KeyPageUp, fyne.KeyBackspace:
+			prev()
+		case fyne.KeyEscape:
+			closeCooking()
+		case fyne.KeyF11:
+			cookingWindow.SetFullScreen(!cookingWindow.FullScreen())
+		}
+	})
+
+	timersBar, strip := createTimerStrip(cookingWindow)
+
+	cookingWindow.SetOnClosed(func() {
+		releaseTimerStrip(strip)
+		stopAwake()
+		delete(cookingWindows, recipe.ID)
+	})
+
+	header := container.NewVBox(
+		container.NewBorder(nil, nil, restartBtn, exitBtn, titleLabel),
+		counterLabel,
+		progress,
+	)
+
+	ingredientsPanel := container.NewBorder(
+		widget.NewLabelWithStyle(fmt.Sprintf("%s В этом шаге", iconAdd),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		stepTimers,
+		nil, nil,
+		container.NewVScroll(ingredientsText),
+	)
+
+	body := container.NewHSplit(
+		container.NewVScroll(container.NewPadded(stepText)),
+		ingredientsPanel,
+	)
+	body.Offset = 0.72
+
+	footer := container.NewVBox(
+		timersBar,
+		widget.NewSeparator(),
+		container.NewHBox(
+			layout.NewSpacer(),
+			container.NewGridWrap(fyne.NewSize(260, 90), prevBtn),
+			layout.NewSpacer(),
+			container.NewGridWrap(fyne.NewSize(260, 90), nextBtn),
+			layout.NewSpacer(),
+		),
+		widget.NewLabelWithStyle("Пробел или → — дальше, ← — назад, Esc — выйти",
+			fyne.TextAlignCenter, fyne.TextStyle{Italic: true}),
+	)
+
+	cookingWindow.SetContent(container.NewBorder(header, footer, nil, nil, body))
+	render()
+
+	cookingWindow.Resize(fyne.NewSize(1024, 700))
+	cookingWindow.SetFullScreen(true)
+	cookingWindow.Show()
+}
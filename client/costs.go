@@ -0,0 +1,398 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const iconCost = "💰"
+
+type IngredientPrice struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Display  string  `json:"display"`
+}
+
+type PricesResponse struct {
+	Status string            `json:"status"`
+	Count  int               `json:"count"`
+	Prices []IngredientPrice `json:"prices"`
+}
+
+type IngredientCost struct {
+	Raw       string  `json:"raw"`
+	Name      string  `json:"name"`
+	PriceName string  `json:"price_name"`
+	Cost      float64 `json:"cost"`
+	Priced    bool    `json:"priced"`
+	Note      string  `json:"note"`
+}
+
+type RecipeCost struct {
+	RecipeID    int              `json:"recipe_id"`
+	Title       string           `json:"title"`
+	Multiplier  float64          `json:"multiplier"`
+	Servings    int              `json:"servings"`
+	Total       float64          `json:"total"`
+	PerServing  *float64         `json:"per_serving"`
+	Ingredients []IngredientCost `json:"ingredients"`
+	Missing     []string         `json:"missing"`
+}
+
+type CostResponse struct {
+	Status string     `json:"status"`
+	Cost   RecipeCost `json:"cost"`
+}
+
+type CostEstimateResponse struct {
+	Status  string       `json:"status"`
+	Total   float64      `json:"total"`
+	Recipes []RecipeCost `json:"recipes"`
+	Missing []string     `json:"missing"`
+}
+
+var pricesWindow fyne.Window
+
+func formatMoney(value float64) string {
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f ₽", value)
+	}
+	return fmt.Sprintf("%.2f ₽", value)
+}
+
+// recipeCostBadge — стоимость порции для карточки; «≈», если у части ингредиентов нет цены.
+func recipeCostBadge(recipe Recipe) string {
+	if recipe.CostPerServing == nil {
+		return ""
+	}
+	approx := ""
+	if recipe.CostMissing > 0 {
+		approx = "≈"
+	}
+	return fmt.Sprintf("%s %s%s за порцию", iconCost, approx, formatMoney(*recipe.CostPerServing))
+}
+
+func createCostSection(recipe Recipe, parent fyne.Window) fyne.CanvasObject {
+	if currentToken == "" {
+		return container.NewVBox()
+	}
+
+	totalLabel := widget.NewLabel("Загрузка...")
+	servingLabel := widget.NewLabel("")
+	servingLabel.Hide()
+	missingLabel := widget.NewLabel("")
+	missingLabel.Wrapping = fyne.TextWrapWord
+	missingLabel.Hide()
+	detailsList := container.NewVBox()
+
+	loadCost := func() {
+		body, err := apiRequest("GET", fmt.Sprintf("/costs?recipe_id=%d", recipe.ID), nil)
+		if err != nil {
+			totalLabel.SetText("Не удалось рассчитать стоимость")
+			return
+		}
+
+		var costResp CostResponse
+		json.Unmarshal(body, &costResp)
+		cost := costResp.Cost
+
+		totalLabel.SetText("Всего: " + formatMoney(cost.Total))
+		if cost.PerServing != nil {
+			servingLabel.SetText(fmt.Sprintf("За порцию (%d): %s", cost.Servings, formatMoney(*cost.PerServing)))
+			servingLabel.Show()
+		} else {
+			servingLabel.Hide()
+		}
+
+		if len(cost.Missing) > 0 {
+			missingLabel.SetText(fmt.Sprintf("%s Нет цены: %s", iconError, strings.Join(cost.Missing, ", ")))
+			missingLabel.Show()
+		} else {
+			missingLabel.Hide()
+		}
+
+		detailsList.Objects = nil
+		for _, item := range cost.Ingredients {
+			var text string
+			switch {
+			case item.Note != "":
+				text = fmt.Sprintf("%s %s (%s)", iconBullet, item.Raw, item.Note)
+			default:
+				text = fmt.Sprintf("%s %s → %s, %s", iconBullet, item.Raw, item.PriceName, formatMoney(item.Cost))
+			}
+			label := widget.NewLabel(text)
+			label.Wrapping = fyne.TextWrapWord
+			detailsList.Add(label)
+		}
+		detailsList.Refresh()
+	}
+
+	loadCost()
+
+	details := widget.NewAccordion(widget.NewAccordionItem("По ингредиентам", detailsList))
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, nil,
+			widget.NewButton("Цены", func() {
+				showPricesWindow(loadCost)
+			}),
+			widget.NewLabelWithStyle(fmt.Sprintf("%s Стоимость", iconCost),
+				fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		),
+		widget.NewSeparator(),
+		totalLabel,
+		servingLabel,
+		missingLabel,
+		details,
+	)
+}
+
+// showPricesWindow открывает прайс-лист; onChanged вызывается после каждого изменения цен.
+func showPricesWindow(onChanged func()) {
+	if pricesWindow != nil {
+		pricesWindow.RequestFocus()
+		return
+	}
+
+	pricesWindow = myApp.NewWindow(fmt.Sprintf("%s Цены продуктов", iconCost))
+	pricesWindow.Resize(fyne.NewSize(520, 600))
+	window := pricesWindow
+
+	content := container.NewVBox()
+	summaryLabel := widget.NewLabel("")
+
+	var reload func()
+	changed := func() {
+		reload()
+		if onChanged != nil {
+			onChanged()
+		}
+	}
+
+	reload = func() {
+		body, err := apiRequest("GET", "/prices", nil)
+		if err != nil {
+			summaryLabel.SetText(fmt.Sprintf("%s Ошибка: %v", iconError, err))
+			return
+		}
+
+		var pricesResp PricesResponse
+		json.Unmarshal(body, &pricesResp)
+
+		summaryLabel.SetText(fmt.Sprintf("Продуктов с ценой: %d", pricesResp.Count))
+		content.Objects = nil
+		if pricesResp.Count == 0 {
+			content.Add(widget.NewLabel("Цен пока нет. Добавьте, например: «1 кг муки» за 89."))
+		}
+
+		for _, price := range pricesResp.Prices {
+			price := price
+			label := widget.NewLabel(fmt.Sprintf("%s — %s за %s", price.Name, formatMoney(price.Price), price.Display))
+			label.Wrapping = fyne.TextWrapWord
+
+			content.Add(container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					widget.NewButton(iconEdit, func() {
+						showEditPriceDialog(price, window, changed)
+					}),
+					widget.NewButton(iconDelete, func() {
+						if _, err := apiRequest("DELETE", fmt.Sprintf("/prices/delete?id=%d", price.ID), nil); err != nil {
+							dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), window)
+							return
+						}
+						changed()
+					}),
+				),
+				label,
+			))
+		}
+		content.Refresh()
+	}
+
+	packageEntry := widget.NewEntry()
+	packageEntry.SetPlaceHolder("Фасовка: 1 кг муки")
+	priceEntry := widget.NewEntry()
+	priceEntry.SetPlaceHolder("Цена")
+
+	addPrice := func() {
+		text := strings.TrimSpace(packageEntry.Text)
+		if text == "" {
+			return
+		}
+		value, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(priceEntry.Text), ",", ".", 1), 64)
+		if err != nil || value < 0 {
+			dialog.ShowError(fmt.Errorf("Цена должна быть числом"), window)
+			return
+		}
+
+		if _, err := apiRequest("POST", "/prices/add", map[string]interface{}{"text": text, "price": value}); err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), window)
+			return
+		}
+		packageEntry.SetText("")
+		priceEntry.SetText("")
+		changed()
+	}
+	priceEntry.OnSubmitted = func(string) { addPrice() }
+
+	top := container.NewVBox(
+		container.NewHBox(
+			widget.NewButton(fmt.Sprintf("%s Стоимость меню", iconRecipe), func() {
+				showMenuCostDialog(window)
+			}),
+			layout.NewSpacer(),
+		),
+		container.NewBorder(nil, nil, nil,
+			container.NewHBox(container.NewGridWrap(fyne.NewSize(100, 36), priceEntry), widget.NewButton(iconAdd, addPrice)),
+			packageEntry,
+		),
+		summaryLabel,
+		widget.NewSeparator(),
+	)
+
+	reload()
+
+	window.SetOnClosed(func() {
+		pricesWindow = nil
+	})
+	window.SetContent(container.NewBorder(top, nil, nil, nil, container.NewScroll(content)))
+	window.Show()
+}
+
+func showEditPriceDialog(price IngredientPrice, parent fyne.Window, onSaved func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(price.Name)
+
+	quantityEntry := widget.NewEntry()
+	quantityEntry.SetText(strconv.FormatFloat(price.Quantity, 'f', -1, 64))
+
+	unitSelect := widget.NewSelect([]string{"г", "кг", "мл", "л", "шт"}, nil)
+	unitSelect.SetSelected(price.Unit)
+
+	priceEntry := widget.NewEntry()
+	priceEntry.SetText(strconv.FormatFloat(price.Price, 'f', -1, 64))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Продукт:", nameEntry),
+		widget.NewFormItem("Фасовка:", container.NewGridWithColumns(2, quantityEntry, unitSelect)),
+		widget.NewFormItem("Цена:", priceEntry),
+	}
+
+	dialog.ShowForm(fmt.Sprintf("%s Цена", iconEdit), "Сохранить", "Отмена", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		quantity, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(quantityEntry.Text), ",", ".", 1), 64)
+		if err != nil || quantity <= 0 {
+			dialog.ShowError(fmt.Errorf("Фасовка должна быть положительным числом"), parent)
+			return
+		}
+		value, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(priceEntry.Text), ",", ".", 1), 64)
+		if err != nil || value < 0 {
+			dialog.ShowError(fmt.Errorf("Цена должна быть числом"), parent)
+			return
+		}
+
+		payload := map[string]interface{}{
+			"id":       price.ID,
+			"name":     strings.TrimSpace(nameEntry.Text),
+			"quantity": quantity,
+			"unit":     unitSelect.Selected,
+			"price":    value,
+		}
+		if _, err := apiRequest("PUT", "/prices/update", payload); err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+			return
+		}
+		onSaved()
+	}, parent)
+}
+
+// showMenuCostDialog считает общую стоимость выбранных рецептов из «Моих рецептов».
+func showMenuCostDialog(parent fyne.Window) {
+	if len(recipes) == 0 {
+		dialog.ShowInformation("Стоимость меню", "Нет рецептов для выбора", parent)
+		return
+	}
+
+	type selection struct {
+		check      *widget.Check
+		multiplier *widget.Entry
+	}
+
+	list := container.NewVBox()
+	selections := make(map[int]selection)
+	for _, recipe := range recipes {
+		check := widget.NewCheck(recipe.Title, nil)
+		multiplier := widget.NewEntry()
+		multiplier.SetText("1")
+		selections[recipe.ID] = selection{check, multiplier}
+		list.Add(container.NewBorder(nil, nil, nil,
+			container.NewHBox(widget.NewLabel("×"), container.NewGridWrap(fyne.NewSize(60, 36), multiplier)),
+			check,
+		))
+	}
+
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(420, 320))
+
+	dialog.ShowCustomConfirm(fmt.Sprintf("%s Стоимость меню", iconCost), "Посчитать", "Отмена", scroll, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		var selected []map[string]interface{}
+		for recipeID, s := range selections {
+			if !s.check.Checked {
+				continue
+			}
+			multiplier, err := strconv.ParseFloat(strings.ReplaceAll(s.multiplier.Text, ",", "."), 64)
+			if err != nil || multiplier <= 0 {
+				dialog.ShowError(fmt.Errorf("%s Множитель для «%s» должен быть положительным числом", iconError, s.check.Text), parent)
+				return
+			}
+			selected = append(selected, map[string]interface{}{"recipe_id": recipeID, "multiplier": multiplier})
+		}
+
+		if len(selected) == 0 {
+			return
+		}
+
+		body, err := apiRequest("POST", "/costs/estimate", map[string]interface{}{"recipes": selected})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+			return
+		}
+
+		var estimate CostEstimateResponse
+		json.Unmarshal(body, &estimate)
+
+		lines := []string{fmt.Sprintf("Итого: %s", formatMoney(estimate.Total)), ""}
+		for _, cost := range estimate.Recipes {
+			lines = append(lines, fmt.Sprintf("%s %s ×%g — %s", iconBullet, cost.Title, cost.Multiplier, formatMoney(cost.Total)))
+		}
+		if len(estimate.Missing) > 0 {
+			lines = append(lines, "", fmt.Sprintf("%s Нет цены: %s", iconError, strings.Join(estimate.Missing, ", ")))
+		}
+
+		result := widget.NewLabel(strings.Join(lines, "\n"))
+		result.Wrapping = fyne.TextWrapWord
+		resultScroll := container.NewVScroll(result)
+		resultScroll.SetMinSize(fyne.NewSize(420, 280))
+
+		dialog.ShowCustom(fmt.Sprintf("%s Стоимость меню", iconCost), "Закрыть", resultScroll, parent)
+	}, parent)
+}
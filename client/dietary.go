@@ -0,0 +1,468 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconDiet      = "🛡"
+	iconWarning   = "⚠️"
+	safeForAnyone = "Безопасно для: все"
+	labelAuto     = "Авто"
+	labelPresent  = "Есть"
+	labelAbsent   = "Нет"
+)
+
+var allergenLabels = []struct {
+	Value string
+	Label string
+	Icon  string
+}{
+	{"nuts", "орехи", "🥜"},
+	{"gluten", "глютен", "🌾"},
+	{"lactose", "лактоза", "🥛"},
+}
+
+var dietLabels = []struct {
+	Value string
+	Label string
+	Icon  string
+}{
+	{"vegetarian", "вегетарианское", "🥕"},
+	{"vegan", "веганское", "🌱"},
+}
+
+type DietProfile struct {
+	ID      int      `json:"id"`
+	Name    string   `json:"name"`
+	Avoid   []string `json:"avoid"`
+	Require []string `json:"require"`
+}
+
+type DietProfilesResponse struct {
+	Status   string        `json:"status"`
+	Profiles []DietProfile `json:"profiles"`
+}
+
+type RecipeLabelsResponse struct {
+	Status    string              `json:"status"`
+	Labels    []string            `json:"labels"`
+	Detected  []string            `json:"detected"`
+	Reasons   map[string][]string `json:"reasons"`
+	Overrides map[string]bool     `json:"overrides"`
+	CanEdit   bool                `json:"can_edit"`
+}
+
+var (
+	dietProfiles        []DietProfile
+	activeDietProfileID int
+	safeForSelect       *widget.Select
+)
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// avoidedByProfiles возвращает true, если аллерген исключён хотя бы в одном профиле пользователя.
+func avoidedByProfiles(allergen string) bool {
+	for _, profile := range dietProfiles {
+		if hasLabel(profile.Avoid, allergen) {
+			return true
+		}
+	}
+	return false
+}
+
+// recipeLabelBadges собирает строку значков для карточки; аллергены из профилей помечаются предупреждением.
+func recipeLabelBadges(recipe Recipe) string {
+	var badges []string
+	for _, allergen := range allergenLabels {
+		if !hasLabel(recipe.Labels, allergen.Value) {
+			continue
+		}
+		badge := fmt.Sprintf("%s %s", allergen.Icon, allergen.Label)
+		if avoidedByProfiles(allergen.Value) {
+			badge = iconWarning + badge
+		}
+		badges = append(badges, badge)
+	}
+	for _, diet := range dietLabels {
+		if hasLabel(recipe.Labels, diet.Value) {
+			badges = append(badges, diet.Icon)
+		}
+	}
+	return strings.Join(badges, "  ")
+}
+
+// safeForQuery добавляет к запросу списка фильтр по выбранному профилю.
+func safeForQuery() string {
+	if activeDietProfileID == 0 {
+		return ""
+	}
+	return fmt.Sprintf("&safe_for=%d", activeDietProfileID)
+}
+
+func safeForOptions() []string {
+	options := []string{safeForAnyone}
+	for _, profile := range dietProfiles {
+		options = append(options, fmt.Sprintf("%s %s", iconDiet, profile.Name))
+	}
+	return options
+}
+
+func reloadCurrentListing() {
+	if showFavoritesOnly {
+		showOnlyFavorites()
+	} else {
+		loadRecipes()
+	}
+	if mainTabs != nil && mainTabs.Selected() != nil && mainTabs.Selected().Text == "🌍 Обзор" {
+		browsePage = 1
+		loadPublicRecipes()
+	}
+}
+
+func createSafeForSelect() *widget.Select {
+	safeForSelect = widget.NewSelect(safeForOptions(), func(selected string) {
+		profileID := 0
+		for i, option := range safeForOptions() {
+			if option == selected && i > 0 {
+				profileID = dietProfiles[i-1].ID
+			}
+		}
+		if profileID == activeDietProfileID {
+			return
+		}
+		activeDietProfileID = profileID
+		reloadCurrentListing()
+	})
+	safeForSelect.Selected = safeForAnyone
+	return safeForSelect
+}
+
+// loadDietProfiles обновляет профили и список выбора; выбранный профиль сбрасывается, если его удалили.
+func loadDietProfiles() {
+	body, err := apiRequest("GET", "/diet-profiles", nil)
+	if err != nil {
+		return
+	}
+
+	var profilesResp DietProfilesResponse
+	json.Unmarshal(body, &profilesResp)
+	dietProfiles = profilesResp.Profiles
+
+	if safeForSelect == nil {
+		return
+	}
+
+	selected := safeForAnyone
+	for _, profile := range dietProfiles {
+		if profile.ID == activeDietProfileID {
+			selected = fmt.Sprintf("%s %s", iconDiet, profile.Name)
+		}
+	}
+	if selected == safeForAnyone && activeDietProfileID != 0 {
+		activeDietProfileID = 0
+		reloadCurrentListing()
+	}
+	safeForSelect.Options = safeForOptions()
+	safeForSelect.Selected = selected
+	safeForSelect.Refresh()
+}
+
+func createLabelsSection(recipe Recipe, parent fyne.Window) fyne.CanvasObject {
+	labelsList := container.NewVBox()
+	editBtn := widget.NewButton(fmt.Sprintf("%s Исправить метки", iconEdit), nil)
+	editBtn.Hide()
+
+	var loadLabels func()
+	loadLabels = func() {
+		body, err := apiRequest("GET", fmt.Sprintf("/recipe-labels?recipe_id=%d", recipe.ID), nil)
+		if err != nil {
+			labelsList.Objects = []fyne.CanvasObject{widget.NewLabel("Не удалось загрузить метки")}
+			labelsList.Refresh()
+			return
+		}
+
+		var labelsResp RecipeLabelsResponse
+		json.Unmarshal(body, &labelsResp)
+
+		labelsList.Objects = nil
+		for _, allergen := range allergenLabels {
+			if !hasLabel(labelsResp.Labels, allergen.Value) {
+				continue
+			}
+			text := fmt.Sprintf("%s Содержит: %s", allergen.Icon, allergen.Label)
+			if avoidedByProfiles(allergen.Value) {
+				text = iconWarning + " " + text
+			}
+			if reasons := labelsResp.Reasons[allergen.Value]; len(reasons) > 0 {
+				text += " (" + strings.Join(reasons, ", ") + ")"
+			} else {
+				text += " (указано автором)"
+			}
+			label := widget.NewLabel(text)
+			label.Wrapping = fyne.TextWrapWord
+			labelsList.Add(label)
+		}
+		for _, diet := range dietLabels {
+			if hasLabel(labelsResp.Labels, diet.Value) {
+				labelsList.Add(widget.NewLabel(fmt.Sprintf("%s Подходит: %s", diet.Icon, diet.Label)))
+			}
+		}
+		if len(labelsList.Objects) == 0 {
+			labelsList.Add(widget.NewLabel("Аллергены не найдены, диета не определена"))
+		}
+		labelsList.Refresh()
+
+		if labelsResp.CanEdit && currentToken != "" {
+			editBtn.OnTapped = func() {
+				showLabelOverridesDialog(recipe, labelsResp, parent, loadLabels)
+			}
+			editBtn.Show()
+		}
+	}
+
+	loadLabels()
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(fmt.Sprintf("%s Аллергены и диеты", iconDiet),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewSeparator(),
+		labelsList,
+		container.NewHBox(editBtn),
+	)
+}
+
+// showLabelOverridesDialog позволяет автору подтвердить или снять любую метку вместо автоматической.
+func showLabelOverridesDialog(recipe Recipe, current RecipeLabelsResponse, parent fyne.Window, onSaved func()) {
+	selects := make(map[string]*widget.Select)
+
+	var items []*widget.FormItem
+	addItem := func(value, title, icon string) {
+		auto := labelAbsent
+		if hasLabel(current.Detected, value) {
+			auto = labelPresent
+		}
+		autoOption := fmt.Sprintf("%s (%s)", labelAuto, strings.ToLower(auto))
+
+		choice := widget.NewSelect([]string{autoOption, labelPresent, labelAbsent}, nil)
+		choice.Selected = autoOption
+		if present, ok := current.Overrides[value]; ok {
+			if present {
+				choice.Selected = labelPresent
+			} else {
+				choice.Selected = labelAbsent
+			}
+		}
+		selects[value] = choice
+		items = append(items, widget.NewFormItem(fmt.Sprintf("%s %s:", icon, title), choice))
+	}
+	for _, allergen := range allergenLabels {
+		addItem(allergen.Value, allergen.Label, allergen.Icon)
+	}
+	for _, diet := range dietLabels {
+		addItem(diet.Value, diet.Label, diet.Icon)
+	}
+
+	formDialog := dialog.NewForm(fmt.Sprintf("%s Метки рецепта", iconDiet), "Сохранить", "Отмена", items,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			overrides := make(map[string]bool)
+			for value, choice := range selects {
+				switch choice.Selected {
+				case labelPresent:
+					overrides[value] = true
+				case labelAbsent:
+					overrides[value] = false
+				}
+			}
+
+			payload := map[string]interface{}{
+				"recipe_id": recipe.ID,
+				"overrides": overrides,
+			}
+			if _, err := apiRequest("PUT", "/recipe-labels/update", payload); err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+				return
+			}
+			onSaved()
+		}, parent)
+	formDialog.Resize(fyne.NewSize(420, 360))
+	formDialog.Show()
+}
+
+func showDietProfilesWindow() {
+	profilesWindow := myApp.NewWindow(fmt.Sprintf("%s Профили питания", iconDiet))
+	profilesWindow.Resize(fyne.NewSize(520, 480))
+
+	content := container.NewVBox()
+
+	var render func()
+	render = func() {
+		loadDietProfiles()
+
+		content.Objects = nil
+		content.Add(widget.NewLabel("Профиль описывает, для кого готовим: какие аллергены исключить и какой диете следовать."))
+		content.Add(container.NewHBox(
+			layout.NewSpacer(),
+			widget.NewButton(fmt.Sprintf("%s Новый профиль", iconAdd), func() {
+				showDietProfileForm(DietProfile{}, profilesWindow, render)
+			}),
+		))
+		content.Add(widget.NewSeparator())
+
+		if len(dietProfiles) == 0 {
+			content.Add(widget.NewLabel("Профилей пока нет"))
+		}
+		for _, profile := range dietProfiles {
+			profile := profile
+
+			var parts []string
+			for _, allergen := range allergenLabels {
+				if hasLabel(profile.Avoid, allergen.Value) {
+					parts = append(parts, "без: "+allergen.Label)
+				}
+			}
+			for _, diet := range dietLabels {
+				if hasLabel(profile.Require, diet.Value) {
+					parts = append(parts, diet.Label)
+				}
+			}
+
+			info := container.NewVBox(
+				widget.NewLabelWithStyle(fmt.Sprintf("%s %s", iconDiet, profile.Name),
+					fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				widget.NewLabel(strings.Join(parts, ", ")),
+			)
+
+			buttons := container.NewHBox(
+				widget.NewButton(iconEdit, func() {
+					showDietProfileForm(profile, profilesWindow, render)
+				}),
+				widget.NewButton(iconDelete, func() {
+					dialog.ShowConfirm(fmt.Sprintf("%s Удаление профиля", iconDelete),
+						fmt.Sprintf("Удалить профиль \"%s\"?", profile.Name),
+						func(confirmed bool) {
+							if !confirmed {
+								return
+							}
+							if _, err := apiRequest("DELETE", fmt.Sprintf("/diet-profiles/delete?id=%d", profile.ID), nil); err != nil {
+								dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), profilesWindow)
+								return
+							}
+							render()
+						}, profilesWindow)
+				}),
+			)
+
+			content.Add(container.NewBorder(nil, nil, nil, buttons, info))
+			content.Add(widget.NewSeparator())
+		}
+		content.Refresh()
+	}
+
+	render()
+
+	profilesWindow.SetContent(container.NewBorder(nil,
+		container.NewHBox(layout.NewSpacer(), widget.NewButton(fmt.Sprintf("%s Закрыть", iconClose), func() {
+			profilesWindow.Close()
+		})),
+		nil, nil, container.NewScroll(content)))
+	profilesWindow.Show()
+}
+
+func showDietProfileForm(profile DietProfile, parent fyne.Window, onSaved func()) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Например: Маша (аллергия на орехи)")
+	nameEntry.SetText(profile.Name)
+
+	var allergenOptions, dietOptions []string
+	for _, allergen := range allergenLabels {
+		allergenOptions = append(allergenOptions, fmt.Sprintf("%s %s", allergen.Icon, allergen.Label))
+	}
+	for _, diet := range dietLabels {
+		dietOptions = append(dietOptions, fmt.Sprintf("%s %s", diet.Icon, diet.Label))
+	}
+
+	avoidGroup := widget.NewCheckGroup(allergenOptions, nil)
+	for i, allergen := range allergenLabels {
+		if hasLabel(profile.Avoid, allergen.Value) {
+			avoidGroup.Selected = append(avoidGroup.Selected, allergenOptions[i])
+		}
+	}
+
+	dietGroup := widget.NewCheckGroup(dietOptions, nil)
+	for i, diet := range dietLabels {
+		if hasLabel(profile.Require, diet.Value) {
+			dietGroup.Selected = append(dietGroup.Selected, dietOptions[i])
+		}
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Название:", nameEntry),
+		widget.NewFormItem("Исключить:", avoidGroup),
+		widget.NewFormItem("Диета:", dietGroup),
+	}
+
+	title := fmt.Sprintf("%s Новый профиль", iconDiet)
+	if profile.ID != 0 {
+		title = fmt.Sprintf("%s Профиль", iconDiet)
+	}
+
+	formDialog := dialog.NewForm(title, "Сохранить", "Отмена", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		var avoid, require []string
+		for i, allergen := range allergenLabels {
+			if hasLabel(avoidGroup.Selected, allergenOptions[i]) {
+				avoid = append(avoid, allergen.Value)
+			}
+		}
+		for i, diet := range dietLabels {
+			if hasLabel(dietGroup.Selected, dietOptions[i]) {
+				require = append(require, diet.Value)
+			}
+		}
+
+		payload := map[string]interface{}{
+			"id":      profile.ID,
+			"name":    strings.TrimSpace(nameEntry.Text),
+			"avoid":   avoid,
+			"require": require,
+		}
+
+		var err error
+		if profile.ID == 0 {
+			_, err = apiRequest("POST", "/diet-profiles/create", payload)
+		} else {
+			_, err = apiRequest("PUT", "/diet-profiles/update", payload)
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+			return
+		}
+		onSaved()
+		reloadCurrentListing()
+	}, parent)
+	formDialog.Resize(fyne.NewSize(440, 380))
+	formDialog.Show()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+const iconExport = "📤"
+
+// safeFileName убирает из названия рецепта символы, недопустимые в именах файлов Windows и Linux.
+func safeFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 32 {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(title))
+
+	name = strings.Trim(name, ". ")
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+	if name == "" {
+		name = "recipe"
+	}
+	return name
+}
+
+// createExportButton — кнопка выгрузки рецепта с выбором формата.
+func createExportButton(recipe Recipe, parent fyne.Window) fyne.CanvasObject {
+	var button *widget.Button
+	button = widget.NewButton(fmt.Sprintf("%s Экспорт", iconExport), func() {
+		menu := fyne.NewMenu("",
+			fyne.NewMenuItem("Markdown…", func() {
+				exportMarkdown(recipe.ID, parent)
+			}),
+			fyne.NewMenuItem("PDF…", func() {
+				exportRecipePDF(recipe, parent)
+			}),
+			fyne.NewMenuItem("schema.org JSON-LD…", func() {
+				exportRecipeJSONLD(recipe, parent)
+			}),
+		)
+
+		position := fyne.CurrentApp().Driver().AbsolutePositionForObject(button)
+		widget.ShowPopUpMenuAtPosition(menu, parent.Canvas(), position.Add(fyne.NewPos(0, button.Size().Height)))
+	})
+	return button
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const iconFeed = "📰"
+
+type FeedItem struct {
+	ID          int       `json:"id"`
+	Kind        string    `json:"kind"`
+	ActorID     int       `json:"actor_id"`
+	ActorName   string    `json:"actor_name"`
+	RecipeID    int       `json:"recipe_id"`
+	RecipeTitle string    `json:"recipe_title"`
+	Rating      int       `json:"rating"`
+	ReviewText  string    `json:"review_text"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type FeedResponse struct {
+	Status     string     `json:"status"`
+	Items      []FeedItem `json:"items"`
+	HasMore    bool       `json:"has_more"`
+	NextCursor string     `json:"next_cursor"`
+}
+
+type UserSummary struct {
+	ID         int       `json:"id"`
+	Username   string    `json:"username"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+type UsersResponse struct {
+	Status string        `json:"status"`
+	Users  []UserSummary `json:"users"`
+}
+
+var (
+	feedList     *fyne.Container
+	feedMoreBtn  *widget.Button
+	feedCursor   string
+	followingIDs = map[int]bool{}
+)
+
+func createFeedTab() fyne.CanvasObject {
+	feedList = container.NewVBox()
+
+	feedMoreBtn = widget.NewButton("Показать ещё", func() {
+		loadFeed(false)
+	})
+	feedMoreBtn.Hide()
+
+	refreshBtn := widget.NewButton(fmt.Sprintf("%s Обновить", iconRefresh), func() {
+		loadFeed(true)
+	})
+	followsBtn := widget.NewButton("👥 Подписки", func() {
+		showFollowsWindow()
+	})
+
+	return container.NewBorder(
+		container.NewHBox(refreshBtn, followsBtn, layout.NewSpacer()),
+		nil,
+		nil,
+		nil,
+		container.NewScroll(container.NewVBox(feedList, feedMoreBtn)),
+	)
+}
+
+func loadFeed(reset bool) {
+	if feedList == nil {
+		return
+	}
+
+	if reset {
+		feedCursor = ""
+		feedList.Objects = nil
+	}
+
+	path := "/feed"
+	if feedCursor != "" {
+		path = "/feed?before=" + url.QueryEscape(feedCursor)
+	}
+
+	body, err := apiRequest("GET", path, nil)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка загрузки ленты: %v", iconError, err), myWindow)
+		return
+	}
+
+	var feedResp FeedResponse
+	json.Unmarshal(body, &feedResp)
+
+	for _, item := range feedResp.Items {
+		feedList.Add(createFeedItemCard(item))
+	}
+	if len(feedList.Objects) == 0 {
+		feedList.Add(widget.NewLabel("Здесь появятся новые рецепты и отзывы тех, на кого вы подписаны"))
+	}
+	feedList.Refresh()
+
+	feedCursor = feedResp.NextCursor
+	if feedResp.HasMore {
+		feedMoreBtn.Show()
+	} else {
+		feedMoreBtn.Hide()
+	}
+}
+
+func feedItemText(item FeedItem) string {
+	switch item.Kind {
+	case "recipe_created":
+		return fmt.Sprintf("%s опубликовал(а) рецепт «%s»", iconAdd, item.RecipeTitle)
+	case "recipe_updated":
+		return fmt.Sprintf("%s обновил(а) рецепт «%s»", iconEdit, item.RecipeTitle)
+	case "review":
+		return fmt.Sprintf("%s оценил(а) рецепт «%s» %s", iconRating, item.RecipeTitle, ratingStars(item.Rating))
+	}
+	return item.RecipeTitle
+}
+
+func createFeedItemCard(item FeedItem) fyne.CanvasObject {
+	header := widget.NewLabelWithStyle(
+		fmt.Sprintf("%s %s · %s", iconUser, item.ActorName, item.CreatedAt.Local().Format("02.01.2006 15:04")),
+		fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+	text := widget.NewLabel(feedItemText(item))
+	text.Wrapping = fyne.TextWrapWord
+
+	card := container.NewVBox(header, text)
+	if item.ReviewText != "" {
+		review := widget.NewLabel("«" + item.ReviewText + "»")
+		review.Wrapping = fyne.TextWrapWord
+		card.Add(review)
+	}
+
+	openBtn := widget.NewButton(fmt.Sprintf("%s Открыть рецепт", iconRecipe), func() {
+		openRecipeByID(item.RecipeID)
+	})
+	card.Add(container.NewHBox(openBtn, layout.NewSpacer()))
+	card.Add(widget.NewSeparator())
+
+	return card
+}
+
+func openRecipeByID(recipeID int) {
+	body, err := apiRequest("GET", fmt.Sprintf("/recipe?id=%d", recipeID), nil)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+		return
+	}
+
+	var recipeResp struct {
+		Recipe Recipe `json:"recipe"`
+	}
+	json.Unmarshal(body, &recipeResp)
+
+	showRecipeDetails(recipeResp.Recipe)
+}
+
+func loadFollowing() {
+	body, err := apiRequest("GET", "/following", nil)
+	if err != nil {
+		return
+	}
+
+	var usersResp UsersResponse
+	json.Unmarshal(body, &usersResp)
+
+	followingIDs = map[int]bool{}
+	for _, user := range usersResp.Users {
+		followingIDs[user.ID] = true
+	}
+}
+
+func createFollowButton(userID int, username string, parent fyne.Window) *widget.Button {
+	btn := widget.NewButton("", nil)
+
+	update := func() {
+		if followingIDs[userID] {
+			btn.SetText(fmt.Sprintf("%s Отписаться от %s", iconSuccess, username))
+		} else {
+			btn.SetText(fmt.Sprintf("%s Подписаться на %s", iconAdd, username))
+		}
+	}
+
+	btn.OnTapped = func() {
+		var err error
+		if followingIDs[userID] {
+			_, err = apiRequest("DELETE", fmt.Sprintf("/unfollow?user_id=%d", userID), nil)
+		} else {
+			_, err = apiRequest("POST", "/follow", map[string]interface{}{"user_id": userID})
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+			return
+		}
+
+		followingIDs[userID] = !followingIDs[userID]
+		update()
+		loadFeed(true)
+	}
+
+	update()
+	return btn
+}
+
+func showFollowsWindow() {
+	followsWindow := myApp.NewWindow("👥 Подписки")
+	followsWindow.Resize(fyne.NewSize(420, 480))
+
+	followingBox := container.NewVBox()
+	followersBox := container.NewVBox()
+
+	var load func()
+	load = func() {
+		loadFollowing()
+
+		fill := func(box *fyne.Container, path, empty string, withUnfollow bool) {
+			box.Objects = nil
+
+			body, err := apiRequest("GET", path, nil)
+			if err != nil {
+				box.Add(widget.NewLabel("Не удалось загрузить список"))
+				box.Refresh()
+				return
+			}
+
+			var usersResp UsersResponse
+			json.Unmarshal(body, &usersResp)
+
+			if len(usersResp.Users) == 0 {
+				box.Add(widget.NewLabel(empty))
+			}
+			for _, user := range usersResp.Users {
+				user := user
+				row := container.NewHBox(widget.NewLabel(fmt.Sprintf("%s %s", iconUser, user.Username)), layout.NewSpacer())
+				if withUnfollow {
+					row.Add(widget.NewButton("Отписаться", func() {
+						if _, err := apiRequest("DELETE", fmt.Sprintf("/unfollow?user_id=%d", user.ID), nil); err != nil {
+							dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), followsWindow)
+							return
+						}
+						load()
+						loadFeed(true)
+					}))
+				}
+				box.Add(row)
+			}
+			box.Refresh()
+		}
+
+		fill(followingBox, "/following", "Вы пока ни на кого не подписаны", true)
+		fill(followersBox, "/followers", "Подписчиков пока нет", false)
+	}
+
+	load()
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Я читаю", container.NewScroll(followingBox)),
+		container.NewTabItem("Мои подписчики", container.NewScroll(followersBox)),
+	)
+
+	followsWindow.SetContent(tabs)
+	followsWindow.Show()
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// nativeFileDialog показывает системный диалог выбора файла через zenity (GNOME) или kdialog (KDE).
+// Пустой путь без ошибки означает, что пользователь отменил выбор.
+func nativeFileDialog(save bool, title, fileName string, extensions []string) (string, error) {
+	patterns := make([]string, 0, len(extensions))
+	for _, extension := range extensions {
+		patterns = append(patterns, "*"+extension)
+	}
+	filter := strings.Join(patterns, " ")
+
+	var cmd *exec.Cmd
+	if path, err := exec.LookPath("zenity"); err == nil {
+		args := []string{"--file-selection", "--title=" + title, "--file-filter=" + filter}
+		if save {
+			args = append(args, "--save", "--confirm-overwrite", "--filename="+fileName)
+		}
+		cmd = exec.Command(path, args...)
+	} else if path, err := exec.LookPath("kdialog"); err == nil {
+		if save {
+			cmd = exec.Command(path, "--title", title, "--getsavefilename", fileName, filter)
+		} else {
+			cmd = exec.Command(path, "--title", title, "--getopenfilename", ".", filter)
+		}
+	} else {
+		return "", errNoNativeDialog
+	}
+
+	return runDialogCommand(cmd)
+}
+
+// nativeFolderDialog показывает системный диалог выбора папки.
+func nativeFolderDialog(title string) (string, error) {
+	var cmd *exec.Cmd
+	if path, err := exec.LookPath("zenity"); err == nil {
+		cmd = exec.Command(path, "--file-selection", "--directory", "--title="+title)
+	} else if path, err := exec.LookPath("kdialog"); err == nil {
+		cmd = exec.Command(path, "--title", title, "--getexistingdirectory", ".")
+	} else {
+		return "", errNoNativeDialog
+	}
+
+	return runDialogCommand(cmd)
+}
+
+func runDialogCommand(cmd *exec.Cmd) (string, error) {
+	output, err := cmd.Output()
+	if err != nil {
+		// Код выхода 1 — диалог закрыт без выбора
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", errNoNativeDialog
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
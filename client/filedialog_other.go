@@ -0,0 +1,12 @@
+//go:build !linux && !windows
+
+package main
+
+// nativeFileDialog на остальных платформах недоступен, используется диалог Fyne.
+func nativeFileDialog(save bool, title, fileName string, extensions []string) (string, error) {
+	return "", errNoNativeDialog
+}
+
+func nativeFolderDialog(title string) (string, error) {
+	return "", errNoNativeDialog
+}
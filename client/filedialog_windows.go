@@ -0,0 +1,60 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// nativeFileDialog показывает стандартный диалог Windows через PowerShell и System.Windows.Forms.
+// Пустой путь без ошибки означает, что пользователь отменил выбор.
+func nativeFileDialog(save bool, title, fileName string, extensions []string) (string, error) {
+	patterns := make([]string, 0, len(extensions))
+	for _, extension := range extensions {
+		patterns = append(patterns, "*"+extension)
+	}
+	filter := strings.Join(patterns, ";")
+
+	dialogType := "OpenFileDialog"
+	if save {
+		dialogType = "SaveFileDialog"
+	}
+
+	return runPowerShellDialog(fmt.Sprintf(`$d = New-Object System.Windows.Forms.%s
+$d.Title = %s
+$d.FileName = %s
+$d.Filter = %s
+if ($d.ShowDialog() -eq 'OK') { $d.FileName }`,
+		dialogType, quotePowerShell(title), quotePowerShell(fileName), quotePowerShell(filter+"|"+filter)))
+}
+
+// nativeFolderDialog показывает стандартный диалог Windows выбора папки.
+func nativeFolderDialog(title string) (string, error) {
+	return runPowerShellDialog(fmt.Sprintf(`$d = New-Object System.Windows.Forms.FolderBrowserDialog
+$d.Description = %s
+$d.ShowNewFolderButton = $true
+if ($d.ShowDialog() -eq 'OK') { $d.SelectedPath }`, quotePowerShell(title)))
+}
+
+func quotePowerShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func runPowerShellDialog(dialogScript string) (string, error) {
+	script := `[Console]::OutputEncoding = [Text.Encoding]::UTF8
+Add-Type -AssemblyName System.Windows.Forms
+` + dialogScript
+
+	cmd := exec.Command("powershell", "-NoProfile", "-STA", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errNoNativeDialog
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconFork         = "📥"
+	iconNotification = "🔔"
+)
+
+type Notification struct {
+	ID             int        `json:"id"`
+	Kind           string     `json:"kind"`
+	RecipeID       int        `json:"recipe_id"`
+	RecipeTitle    string     `json:"recipe_title"`
+	SourceRecipeID *int       `json:"source_recipe_id"`
+	SourceTitle    string     `json:"source_title"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReadAt         *time.Time `json:"read_at"`
+}
+
+type NotificationsResponse struct {
+	Status        string         `json:"status"`
+	Unread        int            `json:"unread"`
+	Notifications []Notification `json:"notifications"`
+}
+
+var notificationsBtn *widget.Button
+
+func lineageText(recipe Recipe) string {
+	if recipe.ForkedFromTitle == "" {
+		return ""
+	}
+	text := fmt.Sprintf("%s основано на рецепте «%s»", iconFork, recipe.ForkedFromTitle)
+	if recipe.ForkedFromAuthor != "" {
+		text += fmt.Sprintf(" (%s %s)", iconUser, recipe.ForkedFromAuthor)
+	}
+	if recipe.ForkedFromID == nil {
+		text += " — оригинал удалён"
+	}
+	return text
+}
+
+// createForkSection показывает происхождение рецепта и действия с копиями:
+// у чужого рецепта — кнопку копирования, у своей копии — слежение за оригиналом.
+func createForkSection(recipe Recipe, parent fyne.Window) fyne.CanvasObject {
+	box := container.NewVBox()
+
+	if lineage := lineageText(recipe); lineage != "" {
+		lineageLabel := widget.NewLabel(lineage)
+		lineageLabel.Wrapping = fyne.TextWrapWord
+		box.Add(lineageLabel)
+
+		if recipe.ForkedFromID != nil {
+			sourceID := *recipe.ForkedFromID
+			box.Add(container.NewHBox(widget.NewButton("Открыть оригинал", func() {
+				openRecipeByID(sourceID)
+			}), layout.NewSpacer()))
+		}
+
+		if isOwnRecipe(recipe) && recipe.ForkedFromID != nil {
+			notifyCheck := widget.NewCheck(fmt.Sprintf("%s Сообщать об изменениях оригинала", iconNotification), nil)
+			notifyCheck.SetChecked(recipe.ForkNotify)
+			notifyCheck.OnChanged = func(checked bool) {
+				_, err := apiRequest("POST", "/recipes/fork-notify", map[string]interface{}{
+					"id":     recipe.ID,
+					"notify": checked,
+				})
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+				}
+			}
+			box.Add(notifyCheck)
+		}
+	}
+
+	if recipe.ForkCount > 0 {
+		box.Add(widget.NewLabel(fmt.Sprintf("%s Скопировали себе: %d", iconFork, recipe.ForkCount)))
+	}
+
+	if currentUser != nil && !isOwnRecipe(recipe) {
+		box.Add(container.NewHBox(widget.NewButton(fmt.Sprintf("%s Скопировать себе", iconFork), func() {
+			showForkDialog(recipe, parent)
+		}), layout.NewSpacer()))
+	}
+
+	return box
+}
+
+func showForkDialog(recipe Recipe, parent fyne.Window) {
+	notifyCheck := widget.NewCheck("Сообщать, когда автор изменит оригинал", nil)
+
+	dialog.ShowCustomConfirm(fmt.Sprintf("%s Копия рецепта", iconFork), "Скопировать", "Отмена",
+		container.NewVBox(
+			widget.NewLabel(fmt.Sprintf("«%s» появится в ваших рецептах,\nего можно будет изменить под себя.", recipe.Title)),
+			notifyCheck,
+		),
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			_, err := apiRequest("POST", "/recipes/fork", map[string]interface{}{
+				"recipe_id": recipe.ID,
+				"notify":    notifyCheck.Checked,
+			})
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+				return
+			}
+
+			dialog.ShowInformation(fmt.Sprintf("%s Готово", iconSuccess), "Рецепт скопирован в «Мои рецепты»", parent)
+			loadRecipes()
+		}, parent)
+}
+
+func loadNotifications() (NotificationsResponse, error) {
+	var notificationsResp NotificationsResponse
+
+	body, err := apiRequest("GET", "/notifications", nil)
+	if err != nil {
+		return notificationsResp, err
+	}
+
+	json.Unmarshal(body, &notificationsResp)
+
+	if notificationsBtn != nil {
+		if notificationsResp.Unread > 0 {
+			notificationsBtn.SetText(fmt.Sprintf("%s %d", iconNotification, notificationsResp.Unread))
+		} else {
+			notificationsBtn.SetText(iconNotification)
+		}
+	}
+
+	return notificationsResp, nil
+}
+
+func showNotificationsWindow() {
+	notificationsWindow := myApp.NewWindow(fmt.Sprintf("%s Уведомления", iconNotification))
+	notificationsWindow.Resize(fyne.NewSize(480, 440))
+
+	list := container.NewVBox()
+
+	notificationsResp, err := loadNotifications()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка загрузки уведомлений: %v", iconError, err), myWindow)
+		return
+	}
+
+	if len(notificationsResp.Notifications) == 0 {
+		list.Add(widget.NewLabel("Уведомлений пока нет"))
+	}
+
+	for _, n := range notificationsResp.Notifications {
+		n := n
+
+		text := fmt.Sprintf("Оригинал «%s» изменился — сравните со своей копией «%s»", n.SourceTitle, n.RecipeTitle)
+		if n.ReadAt == nil {
+			text = "🆕 " + text
+		}
+
+		label := widget.NewLabel(text)
+		label.Wrapping = fyne.TextWrapWord
+
+		actions := container.NewHBox(
+			widget.NewLabel(n.CreatedAt.Local().Format("02.01.2006 15:04")),
+			layout.NewSpacer(),
+			widget.NewButton("Моя копия", func() {
+				openRecipeByID(n.RecipeID)
+			}),
+		)
+		if n.SourceRecipeID != nil {
+			sourceID := *n.SourceRecipeID
+			actions.Add(widget.NewButton("Оригинал", func() {
+				openRecipeByID(sourceID)
+			}))
+		}
+
+		list.Add(container.NewVBox(label, actions, widget.NewSeparator()))
+	}
+
+	if notificationsResp.Unread > 0 {
+		apiRequest("POST", "/notifications/read", map[string]interface{}{"ids": []int{}})
+		loadNotifications()
+	}
+
+	notificationsWindow.SetContent(container.NewScroll(list))
+	notificationsWindow.Show()
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"math"
+	"os"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/image/draw"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	maxImageSize      = 1024 * 1024
+	maxImageDimension = 1600
+)
+
+var aspectRatios = []struct {
+	Name  string
+	Ratio float64
+}{
+	{"Карточка 5:3", 5.0 / 3.0},
+	{"4:3", 4.0 / 3.0},
+	{"1:1", 1},
+	{"16:9", 16.0 / 9.0},
+	{"Без обрезки", 0},
+}
+
+func aspectRatioNames() []string {
+	names := make([]string, 0, len(aspectRatios))
+	for _, ar := range aspectRatios {
+		names = append(names, ar.Name)
+	}
+	return names
+}
+
+func aspectRatioByName(name string) float64 {
+	for _, ar := range aspectRatios {
+		if ar.Name == name {
+			return ar.Ratio
+		}
+	}
+	return 0
+}
+
+func loadEditableImage(filePath string) (image.Image, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("Ошибка чтения файла: %v", err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Не удалось распознать изображение: %v", err)
+	}
+
+	return applyOrientation(limitImageDimension(img), exifOrientation(data)), nil
+}
+
+// exifOrientation возвращает значение тега Orientation (1-8) из JPEG.
+// Для остальных форматов и при любой ошибке разбора возвращается 1.
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		segLen := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		if segLen < 2 || pos+2+segLen > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+segLen]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return parseTIFFOrientation(segment[6:])
+		}
+		pos += 2 + segLen
+	}
+
+	return 1
+}
+
+func parseTIFFOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:8]))
+	if ifdOffset+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifdOffset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+
+	return 1
+}
+
+func applyOrientation(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return flipHorizontal(img)
+	case 3:
+		return rotateImage(rotateImage(img, true), true)
+	case 4:
+		return flipHorizontal(rotateImage(rotateImage(img, true), true))
+	case 5:
+		return flipHorizontal(rotateImage(img, true))
+	case 6:
+		return rotateImage(img, true)
+	case 7:
+		return flipHorizontal(rotateImage(img, false))
+	case 8:
+		return rotateImage(img, false)
+	}
+	return img
+}
+
+func rotateImage(img image.Image, clockwise bool) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dy(), b.Dx()))
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			sx, sy := x-b.Min.X, y-b.Min.Y
+			if clockwise {
+				dst.Set(b.Dy()-1-sy, sx, img.At(x, y))
+			} else {
+				dst.Set(sy, b.Dx()-1-sx, img.At(x, y))
+			}
+		}
+	}
+
+	return dst
+}
+
+func flipHorizontal(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dst.Set(b.Dx()-1-(x-b.Min.X), y-b.Min.Y, img.At(x, y))
+		}
+	}
+
+	return dst
+}
+
+// cropToAspect вырезает из изображения область с заданным соотношением сторон.
+// position (0..1) сдвигает область вдоль стороны, которая обрезается.
+func cropToAspect(img image.Image, ratio, position float64) image.Image {
+	b := img.Bounds()
+	if ratio <= 0 || b.Dx() == 0 || b.Dy() == 0 {
+		return img
+	}
+
+	position = math.Max(0, math.Min(1, position))
+	rect := b
+
+	current := float64(b.Dx()) / float64(b.Dy())
+	if current > ratio {
+		width := int(math.Round(float64(b.Dy()) * ratio))
+		left := b.Min.X + int(math.Round(float64(b.Dx()-width)*position))
+		rect = image.Rect(left, b.Min.Y, left+width, b.Max.Y)
+	} else if current < ratio {
+		height := int(math.Round(float64(b.Dx()) / ratio))
+		top := b.Min.Y + int(math.Round(float64(b.Dy()-height)*position))
+		rect = image.Rect(b.Min.X, top, b.Max.X, top+height)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Copy(dst, image.Point{}, img, rect, draw.Src, nil)
+	return dst
+}
+
+func limitImageDimension(img image.Image) image.Image {
+	b := img.Bounds()
+	if longest := math.Max(float64(b.Dx()), float64(b.Dy())); longest > maxImageDimension {
+		return scaleImage(img, maxImageDimension/longest)
+	}
+	return img
+}
+
+func scaleImage(img image.Image, factor float64) image.Image {
+	b := img.Bounds()
+	width := int(math.Max(1, math.Round(float64(b.Dx())*factor)))
+	height := int(math.Max(1, math.Round(float64(b.Dy())*factor)))
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// fitImageToLimit уменьшает и пережимает изображение в JPEG,
+// пока результат не поместится в maxBytes.
+func fitImageToLimit(img image.Image, maxBytes int) ([]byte, error) {
+	img = limitImageDimension(img)
+
+	for attempt := 0; attempt < 8; attempt++ {
+		for quality := 90; quality >= 60; quality -= 10 {
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+				return nil, err
+			}
+			if buf.Len() <= maxBytes {
+				return buf.Bytes(), nil
+			}
+		}
+		img = scaleImage(img, 0.8)
+	}
+
+	return nil, fmt.Errorf("Не удалось уменьшить изображение до %d KB", maxBytes/1024)
+}
+
+// showImageEditor открывает окно обрезки и поворота фото.
+// onApply получает готовый JPEG, который уже укладывается в лимит размера.
+func showImageEditor(parent fyne.Window, filePath string, onApply func(imgBytes []byte)) {
+	original, err := loadEditableImage(filePath)
+	if err != nil {
+		dialog.ShowError(err, parent)
+		return
+	}
+
+	editorWindow := myApp.NewWindow("🖼 Редактор фото")
+	editorWindow.Resize(fyne.NewSize(600, 560))
+
+	rotated := original
+	ratio := aspectRatios[0].Ratio
+	position := 0.5
+
+	preview := canvas.NewImageFromImage(original)
+	preview.FillMode = canvas.ImageFillContain
+	preview.SetMinSize(fyne.NewSize(450, 300))
+
+	infoLabel := widget.NewLabel("")
+
+	updatePreview := func() {
+		edited := cropToAspect(rotated, ratio, position)
+		b := edited.Bounds()
+		infoLabel.SetText(fmt.Sprintf("Размер: %d × %d px", b.Dx(), b.Dy()))
+		preview.Image = edited
+		preview.Refresh()
+	}
+
+	positionSlider := widget.NewSlider(0, 1)
+	positionSlider.Step = 0.01
+	positionSlider.Value = position
+	positionSlider.OnChanged = func(value float64) {
+		position = value
+		updatePreview()
+	}
+
+	aspectSelect := widget.NewSelect(aspectRatioNames(), func(selected string) {
+		ratio = aspectRatioByName(selected)
+		updatePreview()
+	})
+	aspectSelect.Selected = aspectRatios[0].Name
+
+	rotateLeftBtn := widget.NewButton("⟲ Влево", func() {
+		rotated = rotateImage(rotated, false)
+		updatePreview()
+	})
+	rotateRightBtn := widget.NewButton("⟳ Вправо", func() {
+		rotated = rotateImage(rotated, true)
+		updatePreview()
+	})
+
+	applyBtn := widget.NewButton(fmt.Sprintf("%s Применить", iconSuccess), func() {
+		imgBytes, err := fitImageToLimit(cropToAspect(rotated, ratio, position), maxImageSize)
+		if err != nil {
+			dialog.ShowError(err, editorWindow)
+			return
+		}
+		onApply(imgBytes)
+		editorWindow.Close()
+	})
+	applyBtn.Importance = widget.HighImportance
+
+	cancelBtn := widget.NewButton("Отмена", func() {
+		editorWindow.Close()
+	})
+
+	controls := widget.NewForm(
+		widget.NewFormItem("Пропорции:", aspectSelect),
+		widget.NewFormItem("Положение:", positionSlider),
+	)
+
+	editorWindow.SetContent(container.NewBorder(
+		nil,
+		container.NewVBox(
+			widget.NewSeparator(),
+			container.NewHBox(rotateLeftBtn, rotateRightBtn, layout.NewSpacer(), infoLabel),
+			controls,
+			container.NewHBox(layout.NewSpacer(), cancelBtn, applyBtn),
+		),
+		nil,
+		nil,
+		preview,
+	))
+
+	updatePreview()
+	editorWindow.Show()
+}
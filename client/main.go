@@ -25,25 +25,41 @@ import (
 )
 
 type User struct {
-	ID        int       `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                  int        `json:"id"`
+	Username            string     `json:"username"`
+	Email               string     `json:"email"`
+	DisplayName         string     `json:"display_name"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+	CreatedAt           time.Time  `json:"created_at"`
 }
 
 type Recipe struct {
-	ID           int       `json:"id"`
-	UserID       int       `json:"user_id"`
-	Title        string    `json:"title"`
-	Description  string    `json:"description"`
-	Ingredients  []string  `json:"ingredients"`
-	Instructions string    `json:"instructions"`
-	CookingTime  int       `json:"cooking_time"`
-	Difficulty   string    `json:"difficulty"`
-	ImageBase64  string    `json:"image_base64,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	IsFavorite   bool      `json:"is_favorite"`
+	ID               int       `json:"id"`
+	UserID           int       `json:"user_id"`
+	Title            string    `json:"title"`
+	Description      string    `json:"description"`
+	Ingredients      []string  `json:"ingredients"`
+	Instructions     string    `json:"instructions"`
+	CookingTime      int       `json:"cooking_time"`
+	Servings         int       `json:"servings,omitempty"`
+	Difficulty       string    `json:"difficulty"`
+	ImageBase64      string    `json:"image_base64,omitempty"`
+	Visibility       string    `json:"visibility"`
+	AuthorName       string    `json:"author_name,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	IsFavorite       bool      `json:"is_favorite"`
+	RatingAvg        float64   `json:"rating_avg"`
+	RatingCount      int       `json:"rating_count"`
+	ForkedFromID     *int      `json:"forked_from_id"`
+	ForkedFromTitle  string    `json:"forked_from_title"`
+	ForkedFromAuthor string    `json:"forked_from_author"`
+	ForkCount        int       `json:"fork_count"`
+	ForkNotify       bool      `json:"fork_notify"`
+	CookbookID       *int      `json:"cookbook_id"`
+	Labels           []string  `json:"labels"`
+	CostPerServing   *float64  `json:"cost_per_serving"`
+	CostMissing      int       `json:"cost_missing"`
 }
 
 type AuthResponse struct {
@@ -57,6 +73,8 @@ type RecipesResponse struct {
 	Status  string   `json:"status"`
 	Message string   `json:"message"`
 	Count   int      `json:"count"`
+	Total   int      `json:"total"`
+	Page    int      `json:"page"`
 	Recipes []Recipe `json:"recipes"`
 }
 
@@ -92,6 +110,7 @@ var (
 	searchEntry     *widget.Entry
 	showFavoritesOnly bool = false
 	favoritesBtn     *widget.Button
+	sortSelect       *widget.Select
 )
 
 func getAPIURL() string {
@@ -102,7 +121,9 @@ func getAPIURL() string {
 }
 
 func main() {
-	myApp = app.New()
+	myApp = app.NewWithID("ru.konki.culinarybook")
+	myApp.Settings().SetTheme(konkiTheme{Theme: theme.DefaultTheme()})
+	loadKitchenTimers()
 	myWindow = myApp.NewWindow(fmt.Sprintf("%s Кулинарная книга KonKi", iconFood))
 	myWindow.Resize(fyne.NewSize(900, 700))
 
@@ -171,16 +192,41 @@ func createRecipeCard(recipe Recipe) fyne.CanvasObject {
 	}
 
 	favoriteBtn := widget.NewButton(favoriteIcon, func() {
-		toggleFavorite(recipe.ID, !recipe.IsFavorite)
+		showCollectionPicker(recipe)
 	})
 	favoriteBtn.Importance = widget.LowImportance
 
+	planBtn := widget.NewButton(iconCalendar, func() {
+		planRecipe(recipe)
+	})
+	planBtn.Importance = widget.LowImportance
+
 	cardContent := container.NewVBox(
 		cardImage,
 		widget.NewLabelWithStyle(recipe.Title, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
 		widget.NewLabel(fmt.Sprintf("%s %d мин | %s", iconTime, recipe.CookingTime, recipe.Difficulty)),
-		container.NewCenter(favoriteBtn),
 	)
+	if recipe.RatingCount > 0 || unreadComments[recipe.ID] > 0 {
+		badges := ""
+		if recipe.RatingCount > 0 {
+			badges = ratingSummaryText(recipe.RatingAvg, recipe.RatingCount)
+		}
+		if count := unreadComments[recipe.ID]; count > 0 {
+			badges = strings.TrimSpace(fmt.Sprintf("%s  %s %d новых", badges, iconComment, count))
+		}
+		cardContent.Add(widget.NewLabelWithStyle(badges, fyne.TextAlignCenter, fyne.TextStyle{}))
+	}
+	if badges := recipeLabelBadges(recipe); badges != "" {
+		cardContent.Add(widget.NewLabelWithStyle(badges, fyne.TextAlignCenter, fyne.TextStyle{}))
+	}
+	if cost := recipeCostBadge(recipe); cost != "" {
+		cardContent.Add(widget.NewLabelWithStyle(cost, fyne.TextAlignCenter, fyne.TextStyle{}))
+	}
+	if !isOwnRecipe(recipe) && recipe.AuthorName != "" {
+		cardContent.Add(widget.NewLabelWithStyle(fmt.Sprintf("%s %s", iconUser, recipe.AuthorName),
+			fyne.TextAlignCenter, fyne.TextStyle{Italic: true}))
+	}
+	cardContent.Add(container.NewCenter(container.NewHBox(favoriteBtn, planBtn)))
 
 	cardButton := widget.NewButton("", func() {
 		showRecipeDetails(recipe)
@@ -194,6 +240,10 @@ func createRecipeCard(recipe Recipe) fyne.CanvasObject {
 	return cardContainer
 }
 
+func isOwnRecipe(recipe Recipe) bool {
+	return currentUser != nil && recipe.UserID == currentUser.ID
+}
+
 func containsIngredient(ingredients []string, search string) bool {
 	searchLower := strings.ToLower(search)
 	for _, ing := range ingredients {
@@ -277,6 +327,10 @@ func showMainWindow() {
 
 	refreshBtn := widget.NewButton(fmt.Sprintf("%s Обновить", iconRefresh), func() { loadRecipes() })
 	addBtn := widget.NewButton(fmt.Sprintf("%s Добавить рецепт", iconAdd), func() {
+		if !canAddToCurrentCookbook() {
+			dialog.ShowInformation(iconCookbook, "В этой книге у вас роль читателя — добавлять рецепты нельзя", myWindow)
+			return
+		}
 		showAddRecipeFormWithImage()
 	})
 	logoutBtn := widget.NewButton(fmt.Sprintf("%s Выйти", iconExit), func() {
@@ -284,6 +338,27 @@ func showMainWindow() {
 		currentUser = nil
 		recipes = []Recipe{}
 		filteredRecipes = []Recipe{}
+		browseGrid = nil
+		feedList = nil
+		mainTabs = nil
+		plannerGrid = nil
+		pendingPlanRecipe = nil
+		pantryList = nil
+		if shoppingWindow != nil {
+			shoppingWindow.Close()
+		}
+		if pricesWindow != nil {
+			pricesWindow.Close()
+		}
+		followingIDs = map[int]bool{}
+		notificationsBtn = nil
+		cookbooks = nil
+		currentCookbookID = 0
+		cookbookSelect = nil
+		dietProfiles = nil
+		activeDietProfileID = 0
+		safeForSelect = nil
+		myWindow.SetMainMenu(nil)
 		showAuthWindow()
 	})
 
@@ -316,10 +391,44 @@ func showMainWindow() {
 		}
 	})
 
+	sortSelect = widget.NewSelect(sortLabels(), func(string) {
+		if showFavoritesOnly {
+			showOnlyFavorites()
+		} else {
+			loadRecipes()
+		}
+	})
+	sortSelect.Selected = sortOptions[0].Label
+
+	collectionsBtn := widget.NewButton(fmt.Sprintf("%s Коллекции", iconCollection), func() {
+		showCollectionsWindow()
+	})
+
+	shoppingBtn := widget.NewButton(fmt.Sprintf("%s Покупки", iconShopping), func() {
+		showShoppingListWindow()
+	})
+
+	dietBtn := widget.NewButton(fmt.Sprintf("%s Питание", iconDiet), func() {
+		showDietProfilesWindow()
+	})
+
+	pricesBtn := widget.NewButton(fmt.Sprintf("%s Цены", iconCost), func() {
+		showPricesWindow(reloadCurrentListing)
+	})
+
+	accountBtn := widget.NewButton(fmt.Sprintf("%s Аккаунт", iconSettings), func() {
+		showAccountWindow()
+	})
+
+	notificationsBtn = widget.NewButton(iconNotification, func() {
+		showNotificationsWindow()
+	})
+
 	topPanel := container.NewVBox(
 		container.NewHBox(
 			statusLabel,
 			layout.NewSpacer(),
+			createCookbookSwitcher(),
 			widget.NewLabel(fmt.Sprintf("%s %s", iconUser, currentUser.Username)),
 		),
 		container.NewBorder(
@@ -329,11 +438,11 @@ func showMainWindow() {
 			nil,
 			searchEntry,
 		),
-		container.NewHBox(refreshBtn, addBtn, favoritesBtn, logoutBtn),
+		container.NewHBox(refreshBtn, addBtn, favoritesBtn, collectionsBtn, shoppingBtn, dietBtn, pricesBtn, accountBtn, logoutBtn, layout.NewSpacer(), notificationsBtn, createSafeForSelect(), sortSelect),
 		widget.NewSeparator(),
 	)
 
-	content := container.NewBorder(
+	myRecipesTab := container.NewBorder(
 		topPanel,
 		nil,
 		nil,
@@ -341,7 +450,39 @@ func showMainWindow() {
 		container.NewScroll(recipeGrid),
 	)
 
-	myWindow.SetContent(content)
+	tabs := container.NewAppTabs(
+		container.NewTabItem(fmt.Sprintf("%s Мои рецепты", iconRecipe), myRecipesTab),
+		container.NewTabItem("🌍 Обзор", createBrowseTab()),
+		container.NewTabItem(fmt.Sprintf("%s Лента", iconFeed), createFeedTab()),
+		container.NewTabItem(plannerTabTitle, createPlannerTab()),
+		container.NewTabItem(pantryTabTitle, createPantryTab()),
+	)
+	mainTabs = tabs
+	tabs.OnSelected = func(tab *container.TabItem) {
+		switch tab.Text {
+		case "🌍 Обзор":
+			loadPublicRecipes()
+		case fmt.Sprintf("%s Лента", iconFeed):
+			loadFeed(true)
+		case plannerTabTitle:
+			loadMealPlan()
+		case pantryTabTitle:
+			loadPantry()
+		}
+	}
+
+	if mainTimerStrip != nil {
+		releaseTimerStrip(mainTimerStrip)
+	}
+	var timersBar fyne.CanvasObject
+	timersBar, mainTimerStrip = createTimerStrip(myWindow)
+
+	myWindow.SetContent(container.NewBorder(nil, timersBar, nil, nil, tabs))
+	myWindow.SetMainMenu(createFileMenu())
+	loadFollowing()
+	loadNotifications()
+	loadCookbooks()
+	loadDietProfiles()
 	loadRecipes()
 }
 
@@ -377,6 +518,7 @@ func login(username, password string) {
 		currentUser = authResp.User
 		statusLabel.SetText(fmt.Sprintf("%s Статус: Авторизован", iconSuccess))
 		showMainWindow()
+		warnPendingDeletion(currentUser)
 	} else {
 		dialog.ShowError(fmt.Errorf("%s Ошибка: %s", iconError, authResp.Message), myWindow)
 		statusLabel.SetText(fmt.Sprintf("%s Статус: Ошибка", iconError))
@@ -429,7 +571,12 @@ func loadRecipes() {
 	statusLabel.SetText(fmt.Sprintf("%s Статус: Загрузка рецептов...", iconTime))
 
 	client := &http.Client{}
-	req, _ := http.NewRequest("GET", getAPIURL()+"/my-recipes", nil)
+	path := "/my-recipes?sort=" + sortValue(sortSelect.Selected) + safeForQuery()
+	if currentCookbookID != 0 {
+		path += fmt.Sprintf("&cookbook_id=%d", currentCookbookID)
+	}
+
+	req, _ := http.NewRequest("GET", getAPIURL()+path, nil)
 	req.Header.Set("Authorization", "Bearer "+currentToken)
 
 	resp, err := client.Do(req)
@@ -454,6 +601,7 @@ func loadRecipes() {
 	if recipesResp.Status == "ok" {
 		recipes = recipesResp.Recipes
 		filteredRecipes = recipes
+		loadUnreadComments()
 		updateRecipeGrid()
 		statusLabel.SetText(fmt.Sprintf("%s Статус: %d рецептов загружено",
 			iconSuccess, len(recipes)))
@@ -485,9 +633,15 @@ func showAddRecipeFormWithImage() {
 	timeEntry := widget.NewEntry()
 	timeEntry.SetPlaceHolder("Время приготовления (минуты)")
 
+	servingsEntry := widget.NewEntry()
+	servingsEntry.SetPlaceHolder("Количество порций")
+
 	difficultyEntry := widget.NewSelect([]string{"легкая", "средняя", "сложная"}, nil)
 	difficultyEntry.PlaceHolder = "Выберите сложность"
 
+	visibilityEntry := widget.NewSelect(visibilityLabels(), nil)
+	visibilityEntry.Selected = visibilityLabel("private")
+
 	var imageBase64 string
 
 	imagePreview := canvas.NewImageFromResource(theme.BrokenImageIcon())
@@ -497,27 +651,18 @@ func showAddRecipeFormWithImage() {
 	loadAndDisplayImage := func(filePath string) {
 		fmt.Printf("Загружаем изображение из: %s\n", filePath)
 
-		imgBytes, err := os.ReadFile(filePath)
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("Ошибка чтения файла: %v", err), dialogWindow)
-			return
-		}
-
-		if len(imgBytes) > 1024*1024 {
-			dialog.ShowError(fmt.Errorf("Изображение слишком большое (макс. 1MB)"), dialogWindow)
-			return
-		}
+		showImageEditor(dialogWindow, filePath, func(imgBytes []byte) {
+			imageBase64 = base64.StdEncoding.EncodeToString(imgBytes)
 
-		imageBase64 = base64.StdEncoding.EncodeToString(imgBytes)
-
-		previewResource := fyne.NewStaticResource(
-			filepath.Base(filePath),
-			imgBytes,
-		)
-		imagePreview.Resource = previewResource
-		imagePreview.Refresh()
+			previewResource := fyne.NewStaticResource(
+				filepath.Base(filePath),
+				imgBytes,
+			)
+			imagePreview.Resource = previewResource
+			imagePreview.Refresh()
 
-		dialog.ShowInformation("✅", "Фото загружено!", dialogWindow)
+			dialog.ShowInformation("✅", "Фото загружено!", dialogWindow)
+		})
 	}
 
 	selectImageBtn := widget.NewButton("📁 Выбрать фото", func() {
@@ -576,7 +721,9 @@ func showAddRecipeFormWithImage() {
 		widget.NewFormItem("Ингредиенты:", ingredientsEntry),
 		widget.NewFormItem("Инструкции:", instructionsEntry),
 		widget.NewFormItem("Время (мин):", timeEntry),
+		widget.NewFormItem("Порций:", servingsEntry),
 		widget.NewFormItem("Сложность:", difficultyEntry),
+		widget.NewFormItem("Видимость:", visibilityEntry),
 	)
 
 	form.OnSubmit = func() {
@@ -595,8 +742,10 @@ func showAddRecipeFormWithImage() {
 			parseIngredients(ingredientsEntry.Text),
 			instructionsEntry.Text,
 			timeEntry.Text,
+			servingsEntry.Text,
 			difficultyEntry.Selected,
 			imageBase64,
+			visibilityValue(visibilityEntry.Selected),
 		)
 		dialogWindow.Close()
 	}
@@ -641,11 +790,20 @@ func showEditRecipeForm(recipe Recipe) {
     timeEntry := widget.NewEntry()
     timeEntry.SetText(strconv.Itoa(recipe.CookingTime))
 
+    servingsEntry := widget.NewEntry()
+    servingsEntry.SetPlaceHolder("Количество порций")
+    if recipe.Servings > 0 {
+        servingsEntry.SetText(strconv.Itoa(recipe.Servings))
+    }
+
     difficultyEntry := widget.NewSelect([]string{"легкая", "средняя", "сложная"}, func(selected string) {
     })
     difficultyEntry.Selected = recipe.Difficulty
     difficultyEntry.PlaceHolder = "Выберите сложность"
 
+    visibilityEntry := widget.NewSelect(visibilityLabels(), nil)
+    visibilityEntry.Selected = visibilityLabel(recipe.Visibility)
+
     var imageBase64 string = recipe.ImageBase64
 
     imagePreview := canvas.NewImageFromResource(theme.BrokenImageIcon())
@@ -664,27 +822,18 @@ func showEditRecipeForm(recipe Recipe) {
     loadAndDisplayImage := func(filePath string) {
         fmt.Printf("Загружаем новое изображение из: %s\n", filePath)
 
-        imgBytes, err := os.ReadFile(filePath)
-        if err != nil {
-            dialog.ShowError(fmt.Errorf("Ошибка чтения файла: %v", err), dialogWindow)
-            return
-        }
+        showImageEditor(dialogWindow, filePath, func(imgBytes []byte) {
+            imageBase64 = base64.StdEncoding.EncodeToString(imgBytes)
 
-        if len(imgBytes) > 1024*1024 {
-            dialog.ShowError(fmt.Errorf("Изображение слишком большое (макс. 1MB)"), dialogWindow)
-            return
-        }
-
-        imageBase64 = base64.StdEncoding.EncodeToString(imgBytes)
-
-        previewResource := fyne.NewStaticResource(
-            filepath.Base(filePath),
-            imgBytes,
-        )
-        imagePreview.Resource = previewResource
-        imagePreview.Refresh()
+            previewResource := fyne.NewStaticResource(
+                filepath.Base(filePath),
+                imgBytes,
+            )
+            imagePreview.Resource = previewResource
+            imagePreview.Refresh()
 
-        dialog.ShowInformation("✅", "Новое фото загружено!", dialogWindow)
+            dialog.ShowInformation("✅", "Новое фото загружено!", dialogWindow)
+        })
     }
 
     selectImageBtn := widget.NewButton("📁 Выбрать новое фото", func() {
@@ -743,7 +892,9 @@ func showEditRecipeForm(recipe Recipe) {
         widget.NewFormItem("Ингредиенты:", ingredientsEntry),
         widget.NewFormItem("Инструкции:", instructionsEntry),
         widget.NewFormItem("Время (мин):", timeEntry),
+        widget.NewFormItem("Порций:", servingsEntry),
         widget.NewFormItem("Сложность:", difficultyEntry),
+        widget.NewFormItem("Видимость:", visibilityEntry),
     )
 
     form.OnSubmit = func() {
@@ -763,8 +914,10 @@ func showEditRecipeForm(recipe Recipe) {
             parseIngredients(ingredientsEntry.Text),
             instructionsEntry.Text,
             timeEntry.Text,
+            servingsEntry.Text,
             difficultyEntry.Selected,
             imageBase64,
+            visibilityValue(visibilityEntry.Selected),
         )
         dialogWindow.Close()
     }
@@ -799,7 +952,7 @@ func parseIngredients(text string) []string {
 	return ingredients
 }
 
-func createRecipeWithImage(title, description string, ingredients []string, instructions, timeStr, difficulty, imageBase64 string) {
+func createRecipeWithImage(title, description string, ingredients []string, instructions, timeStr, servingsStr, difficulty, imageBase64, visibility string) {
 	statusLabel.SetText(fmt.Sprintf("%s Статус: Создание рецепта...", iconTime))
 
 	cookingTime := 0
@@ -807,14 +960,22 @@ func createRecipeWithImage(title, description string, ingredients []string, inst
 		cookingTime = n
 	}
 
+	servings := 0
+	if n, err := strconv.Atoi(strings.TrimSpace(servingsStr)); err == nil {
+		servings = n
+	}
+
 	recipeData := map[string]interface{}{
 		"title":        title,
 		"description":  description,
 		"ingredients":  ingredients,
 		"instructions": instructions,
 		"cooking_time": cookingTime,
+		"servings":     servings,
 		"difficulty":   difficulty,
 		"image_base64": imageBase64,
+		"visibility":   visibility,
+		"cookbook_id":  currentCookbookID,
 	}
 
 	jsonData, _ := json.Marshal(recipeData)
@@ -844,7 +1005,7 @@ func createRecipeWithImage(title, description string, ingredients []string, inst
 	}
 }
 
-func updateRecipeWithImage(recipeID int, title, description string, ingredients []string, instructions, timeStr, difficulty, imageBase64 string) {
+func updateRecipeWithImage(recipeID int, title, description string, ingredients []string, instructions, timeStr, servingsStr, difficulty, imageBase64, visibility string) {
     statusLabel.SetText(fmt.Sprintf("%s Статус: Обновление рецепта...", iconTime))
 
     cookingTime := 0
@@ -852,6 +1013,11 @@ func updateRecipeWithImage(recipeID int, title, description string, ingredients
         cookingTime = n
     }
 
+    servings := 0
+    if n, err := strconv.Atoi(strings.TrimSpace(servingsStr)); err == nil {
+        servings = n
+    }
+
     recipeData := map[string]interface{}{
         "id":           recipeID,
         "title":        title,
@@ -859,8 +1025,10 @@ func updateRecipeWithImage(recipeID int, title, description string, ingredients
         "ingredients":  ingredients,
         "instructions": instructions,
         "cooking_time": cookingTime,
+        "servings":     servings,
         "difficulty":   difficulty,
         "image_base64": imageBase64,
+        "visibility":   visibility,
     }
 
     jsonData, _ := json.Marshal(recipeData)
@@ -903,6 +1071,11 @@ func updateRecipeWithImage(recipeID int, title, description string, ingredients
 func showRecipeDetails(recipe Recipe) {
     dialogWindow := myApp.NewWindow(fmt.Sprintf("%s %s", iconFood, recipe.Title))
     dialogWindow.Resize(fyne.NewSize(650, 800))
+    dialogWindow.SetOnClosed(func() {
+        if unreadComments[recipe.ID] == 0 {
+            updateRecipeGrid()
+        }
+    })
 
     titleLabel := widget.NewLabelWithStyle(fmt.Sprintf("%s %s", iconRecipe, recipe.Title),
         fyne.TextAlignCenter, fyne.TextStyle{
@@ -959,7 +1132,17 @@ func showRecipeDetails(recipe Recipe) {
         widget.NewLabel(fmt.Sprintf("%s Время приготовления: %d минут", iconTime, recipe.CookingTime)),
         widget.NewLabel(fmt.Sprintf("%s Сложность: %s %s", difficultyIcon, recipe.Difficulty, difficultyIcon)),
         widget.NewLabel(fmt.Sprintf("%s Добавлен: %s", iconCalendar, recipe.CreatedAt.Format("02.01.2006 15:04"))),
+        widget.NewLabel(fmt.Sprintf("Видимость: %s", visibilityLabel(recipe.Visibility))),
     )
+    if recipe.Servings > 0 {
+        infoCard.Add(widget.NewLabel(fmt.Sprintf("🍽️ Порций: %d", recipe.Servings)))
+    }
+    if !isOwnRecipe(recipe) && recipe.AuthorName != "" {
+        infoCard.Add(widget.NewLabel(fmt.Sprintf("%s Автор: %s", iconUser, recipe.AuthorName)))
+        if currentUser != nil {
+            infoCard.Add(container.NewHBox(createFollowButton(recipe.UserID, recipe.AuthorName, dialogWindow)))
+        }
+    }
 
     ingredientsBox := container.NewVBox(
         widget.NewLabelWithStyle(fmt.Sprintf("%s Ингредиенты", iconAdd),
@@ -1006,10 +1189,45 @@ func showRecipeDetails(recipe Recipe) {
         confirmDialog.Show()
     })
 
+    shareBtn := widget.NewButton("🔗 Поделиться", func() {
+        showShareWindow(recipe)
+    })
+
     closeBtn := widget.NewButton(fmt.Sprintf("%s Закрыть", iconClose), func() {
         dialogWindow.Close()
     })
 
+    moveBtn := widget.NewButton(fmt.Sprintf("%s В книгу", iconCookbook), func() {
+        showMoveRecipeDialog(recipe, dialogWindow)
+    })
+
+    actions := container.NewHBox()
+    if canEditRecipe(recipe) {
+        actions.Add(editBtn)
+        actions.Add(moveBtn)
+    }
+    if canManageRecipe(recipe) {
+        actions.Add(shareBtn)
+    }
+    if canEditRecipe(recipe) {
+        actions.Add(deleteBtn)
+    }
+    if recipe.CookingTime > 0 {
+        actions.Add(widget.NewButton(fmt.Sprintf("%s Таймер", iconTimer), func() {
+            showNewTimerDialog(recipe.Title, time.Duration(recipe.CookingTime)*time.Minute, recipe.ID, dialogWindow)
+        }))
+    }
+    if len(splitSteps(recipe.Instructions)) > 0 {
+        actions.Add(widget.NewButton(fmt.Sprintf("%s Готовить", iconCooking), func() {
+            showCookingMode(recipe)
+        }))
+    }
+    if currentToken != "" {
+        actions.Add(createCookedButton(recipe, dialogWindow))
+    }
+    actions.Add(createExportButton(recipe, dialogWindow))
+    actions.Add(closeBtn)
+
     content := container.NewVBox(
         titleLabel,
         container.NewCenter(imageContainer),
@@ -1017,13 +1235,14 @@ func showRecipeDetails(recipe Recipe) {
         infoCard,
         ingredientsBox,
         instructionsBox,
-        container.NewCenter(
-            container.NewHBox(
-		editBtn,
-                deleteBtn,
-                closeBtn,
-            ),
-        ),
+        createLabelsSection(recipe, dialogWindow),
+        createNutritionSection(recipe, dialogWindow),
+        createCostSection(recipe, dialogWindow),
+        createForkSection(recipe, dialogWindow),
+        createSimilarSection(recipe),
+        createReviewsSection(recipe, dialogWindow),
+        createCommentsSection(recipe, dialogWindow),
+        container.NewCenter(actions),
     )
 
     scroll := container.NewScroll(content)
@@ -1065,7 +1284,7 @@ func showOnlyFavorites() {
     statusLabel.SetText(fmt.Sprintf("%s Статус: Загрузка избранного...", iconTime))
 
     client := &http.Client{}
-    req, _ := http.NewRequest("GET", getAPIURL()+"/favorites", nil)
+    req, _ := http.NewRequest("GET", getAPIURL()+"/favorites?sort="+sortValue(sortSelect.Selected)+safeForQuery(), nil)
     req.Header.Set("Authorization", "Bearer "+currentToken)
 
     resp, err := client.Do(req)
@@ -1090,6 +1309,7 @@ func showOnlyFavorites() {
     if favoritesResp.Status == "ok" {
         recipes = favoritesResp.Recipes
         filteredRecipes = recipes
+        loadUnreadComments()
         updateRecipeGrid()
         statusLabel.SetText(fmt.Sprintf("%s Статус: %d избранных рецептов",
             iconSuccess, len(recipes)))
@@ -1098,51 +1318,3 @@ func showOnlyFavorites() {
         statusLabel.SetText(fmt.Sprintf("%s Статус: Ошибка", iconError))
     }
 }
-
-func toggleFavorite(recipeID int, addToFavorites bool) {
-    if currentToken == "" {
-        return
-    }
-
-    var url string
-    var method string
-
-    if addToFavorites {
-        url = getAPIURL() + "/favorites/add"
-        method = "POST"
-    } else {
-        url = fmt.Sprintf("%s/favorites/remove?recipe_id=%d", getAPIURL(), recipeID)
-        method = "DELETE"
-    }
-
-    client := &http.Client{}
-    var req *http.Request
-
-    if addToFavorites {
-        data, _ := json.Marshal(map[string]int{"recipe_id": recipeID})
-        req, _ = http.NewRequest(method, url, bytes.NewBuffer(data))
-        req.Header.Set("Content-Type", "application/json")
-    } else {
-        req, _ = http.NewRequest(method, url, nil)
-    }
-
-    req.Header.Set("Authorization", "Bearer "+currentToken)
-
-    resp, err := client.Do(req)
-    if err != nil {
-        dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
-        return
-    }
-    defer resp.Body.Close()
-
-    if resp.StatusCode == 200 {
-        if showFavoritesOnly {
-            showOnlyFavorites()
-        } else {
-            loadRecipes()
-        }
-    } else {
-        body, _ := io.ReadAll(resp.Body)
-        dialog.ShowError(fmt.Errorf("%s Ошибка: %s", iconError, string(body)), myWindow)
-    }
-}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+const (
+	iconMarkdown          = "📝"
+	maxMarkdownFolderSize = 100 * 1024 * 1024
+	maxMarkdownFileSize   = 10 * 1024 * 1024
+)
+
+// Файлы, которые импорт Markdown забирает из папки: сами рецепты и картинки к ним
+var markdownFolderExtensions = map[string]bool{
+	".md": true, ".markdown": true,
+	".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true,
+}
+
+// extractZip распаковывает архив в папку dir; пути, выходящие за её пределы, пропускаются.
+func extractZip(data []byte, dir string) (int, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for _, file := range zr.File {
+		name := filepath.FromSlash(file.Name)
+		if file.FileInfo().IsDir() || !filepath.IsLocal(name) {
+			continue
+		}
+
+		target := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return written, err
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return written, err
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(target, content, 0o644); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
+
+// zipMarkdownFolder упаковывает файлы Markdown и картинки из папки и её подпапок, сохраняя структуру.
+func zipMarkdownFolder(dir string) ([]byte, int, error) {
+	var buffer bytes.Buffer
+	zw := zip.NewWriter(&buffer)
+	recipes := 0
+	var total int64
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".") && path != dir {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || !markdownFolderExtensions[ext] {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil || info.Size() > maxMarkdownFileSize {
+			return nil
+		}
+		if total += info.Size(); total > maxMarkdownFolderSize {
+			return fmt.Errorf("в папке больше %d МБ файлов", maxMarkdownFolderSize/1024/1024)
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		file, err := zw.Create(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(content); err != nil {
+			return err
+		}
+
+		if ext == ".md" || ext == ".markdown" {
+			recipes++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, 0, err
+	}
+	return buffer.Bytes(), recipes, nil
+}
+
+// exportMarkdown выгружает рецепт (или все рецепты при recipeID == 0) в Markdown и раскладывает файлы в выбранной папке.
+func exportMarkdown(recipeID int, parent fyne.Window) {
+	path := "/markdown/export"
+	if recipeID != 0 {
+		path = fmt.Sprintf("%s?recipe_id=%d", path, recipeID)
+	}
+
+	body, err := apiRequest("GET", path, nil)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка выгрузки: %v", iconError, err), parent)
+		return
+	}
+
+	chooseFolderWithDialog("Папка для файлов Markdown", parent, func(dir string) {
+		written, err := extractZip(body, dir)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка записи: %v", iconError, err), parent)
+			return
+		}
+		dialog.ShowInformation(fmt.Sprintf("%s Готово", iconSuccess),
+			fmt.Sprintf("Сохранено файлов: %d\n%s", written, dir), parent)
+	})
+}
+
+// importMarkdown импортирует рецепты из файлов Markdown в выбранной папке и её подпапках.
+func importMarkdown(parent fyne.Window) {
+	chooseFolderWithDialog("Папка с рецептами в Markdown", parent, func(dir string) {
+		data, count, err := zipMarkdownFolder(dir)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка чтения: %v", iconError, err), parent)
+			return
+		}
+		if count == 0 {
+			dialog.ShowError(fmt.Errorf("%s В папке нет файлов Markdown", iconError), parent)
+			return
+		}
+
+		showImportModeDialog(parent, func(mode string) {
+			body, err := apiRawRequest("POST", "/markdown/import?mode="+mode, "application/zip", bytes.NewReader(data))
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка импорта: %v", iconError, err), parent)
+				return
+			}
+			showImportResult(body, parent)
+		})
+	})
+}
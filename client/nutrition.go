@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const iconNutrition = "🥗"
+
+type NutritionFacts struct {
+	Calories float64 `json:"calories"`
+	Protein  float64 `json:"protein"`
+	Fat      float64 `json:"fat"`
+	Carbs    float64 `json:"carbs"`
+	Fiber    float64 `json:"fiber"`
+}
+
+type IngredientNutrition struct {
+	Raw      string         `json:"raw"`
+	Name     string         `json:"name"`
+	FoodID   int            `json:"food_id"`
+	FoodName string         `json:"food_name"`
+	Grams    float64        `json:"grams"`
+	Facts    NutritionFacts `json:"facts"`
+	Matched  bool           `json:"matched"`
+	Override bool           `json:"override"`
+	Note     string         `json:"note"`
+}
+
+type RecipeNutrition struct {
+	Servings    int                   `json:"servings"`
+	Total       NutritionFacts        `json:"total"`
+	PerServing  *NutritionFacts       `json:"per_serving"`
+	Ingredients []IngredientNutrition `json:"ingredients"`
+	Unmatched   int                   `json:"unmatched"`
+}
+
+type NutritionResponse struct {
+	Status    string          `json:"status"`
+	Nutrition RecipeNutrition `json:"nutrition"`
+}
+
+type Food struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type FoodsResponse struct {
+	Status string `json:"status"`
+	Foods  []Food `json:"foods"`
+}
+
+func nutritionFactsText(facts NutritionFacts) string {
+	return fmt.Sprintf("%.0f ккал · Б %.1f г · Ж %.1f г · У %.1f г · Клетчатка %.1f г",
+		facts.Calories, facts.Protein, facts.Fat, facts.Carbs, facts.Fiber)
+}
+
+func createNutritionSection(recipe Recipe, parent fyne.Window) fyne.CanvasObject {
+	totalLabel := widget.NewLabel("Загрузка...")
+	servingLabel := widget.NewLabel("")
+	servingLabel.Hide()
+	detailsList := container.NewVBox()
+
+	var loadNutrition func()
+	loadNutrition = func() {
+		body, err := apiRequest("GET", fmt.Sprintf("/nutrition?recipe_id=%d", recipe.ID), nil)
+		if err != nil {
+			totalLabel.SetText("Не удалось рассчитать пищевую ценность")
+			return
+		}
+
+		var nutritionResp NutritionResponse
+		json.Unmarshal(body, &nutritionResp)
+		info := nutritionResp.Nutrition
+
+		totalLabel.SetText("Всего: " + nutritionFactsText(info.Total))
+		if info.PerServing != nil {
+			servingLabel.SetText(fmt.Sprintf("На порцию (%d): %s", info.Servings, nutritionFactsText(*info.PerServing)))
+			servingLabel.Show()
+		} else {
+			servingLabel.Hide()
+		}
+
+		detailsList.Objects = nil
+		if info.Unmatched > 0 {
+			detailsList.Add(widget.NewLabel(fmt.Sprintf("%s Не учтено ингредиентов: %d", iconError, info.Unmatched)))
+		}
+		for _, item := range info.Ingredients {
+			item := item
+
+			var text string
+			switch {
+			case item.Note != "" && item.Matched:
+				text = fmt.Sprintf("%s %s → %s (%s)", iconBullet, item.Raw, item.FoodName, item.Note)
+			case item.Note != "":
+				text = fmt.Sprintf("%s %s (%s)", iconBullet, item.Raw, item.Note)
+			default:
+				text = fmt.Sprintf("%s %s → %s, %.0f г, %.0f ккал", iconBullet, item.Raw, item.FoodName,
+					item.Grams, item.Facts.Calories)
+			}
+			label := widget.NewLabel(text)
+			label.Wrapping = fyne.TextWrapWord
+
+			row := container.NewBorder(nil, nil, nil, nil, label)
+			if currentToken != "" {
+				buttons := container.NewHBox()
+				if item.Override {
+					buttons.Add(widget.NewButton("↺", func() {
+						path := "/nutrition/overrides/delete?ingredient=" + url.QueryEscape(item.Name)
+						if _, err := apiRequest("DELETE", path, nil); err != nil {
+							dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+							return
+						}
+						loadNutrition()
+					}))
+				}
+				buttons.Add(widget.NewButton(iconEdit, func() {
+					showFoodOverrideDialog(item, parent, loadNutrition)
+				}))
+				row = container.NewBorder(nil, nil, nil, buttons, label)
+			}
+			detailsList.Add(row)
+		}
+		detailsList.Refresh()
+	}
+
+	loadNutrition()
+
+	details := widget.NewAccordion(widget.NewAccordionItem("По ингредиентам", detailsList))
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(fmt.Sprintf("%s Пищевая ценность", iconNutrition),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewSeparator(),
+		totalLabel,
+		servingLabel,
+		details,
+	)
+}
+
+// showFoodOverrideDialog сопоставляет ингредиент продукту из таблицы, вес указывается при необходимости.
+func showFoodOverrideDialog(item IngredientNutrition, parent fyne.Window, onSaved func()) {
+	var foods []Food
+
+	foodSelect := widget.NewSelect(nil, nil)
+	foodSelect.PlaceHolder = "Найдите продукт"
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Название продукта")
+	searchEntry.SetText(item.Name)
+
+	search := func() {
+		query := strings.TrimSpace(searchEntry.Text)
+		if query == "" {
+			return
+		}
+		body, err := apiRequest("GET", "/nutrition/foods?q="+url.QueryEscape(query), nil)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+			return
+		}
+
+		var foodsResp FoodsResponse
+		json.Unmarshal(body, &foodsResp)
+		foods = foodsResp.Foods
+
+		options := make([]string, len(foods))
+		for i, food := range foods {
+			options[i] = food.Name
+		}
+		foodSelect.Options = options
+		foodSelect.ClearSelected()
+		if len(options) > 0 {
+			foodSelect.SetSelectedIndex(0)
+		} else {
+			foodSelect.PlaceHolder = "Ничего не найдено"
+		}
+		foodSelect.Refresh()
+	}
+	searchEntry.OnSubmitted = func(string) { search() }
+	search()
+
+	gramsEntry := widget.NewEntry()
+	gramsEntry.SetPlaceHolder("Вес в граммах (если не указан в рецепте)")
+	if item.Override && item.Grams > 0 {
+		gramsEntry.SetText(strconv.FormatFloat(item.Grams, 'f', -1, 64))
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Ингредиент:", widget.NewLabel(item.Raw)),
+		widget.NewFormItem("Поиск:", container.NewBorder(nil, nil, nil,
+			widget.NewButton("🔍", search), searchEntry)),
+		widget.NewFormItem("Продукт:", foodSelect),
+		widget.NewFormItem("Вес, г:", gramsEntry),
+	}
+
+	formDialog := dialog.NewForm(fmt.Sprintf("%s Сопоставить продукт", iconNutrition), "Сохранить", "Отмена", items,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			index := foodSelect.SelectedIndex()
+			if index < 0 || index >= len(foods) {
+				dialog.ShowError(fmt.Errorf("Выберите продукт"), parent)
+				return
+			}
+
+			grams := 0.0
+			if text := strings.TrimSpace(gramsEntry.Text); text != "" {
+				value, err := strconv.ParseFloat(strings.Replace(text, ",", ".", 1), 64)
+				if err != nil || value < 0 {
+					dialog.ShowError(fmt.Errorf("Вес должен быть числом"), parent)
+					return
+				}
+				grams = value
+			}
+
+			payload := map[string]interface{}{
+				"ingredient": item.Name,
+				"food_id":    foods[index].ID,
+				"grams":      grams,
+			}
+			if _, err := apiRequest("POST", "/nutrition/overrides", payload); err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+				return
+			}
+			onSaved()
+		}, parent)
+	formDialog.Resize(fyne.NewSize(480, 320))
+	formDialog.Show()
+}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const iconPantry = "🥫"
+
+var pantryTabTitle = fmt.Sprintf("%s Запасы", iconPantry)
+
+var pantryLocations = []struct {
+	Value string
+	Label string
+}{
+	{"fridge", "Холодильник"},
+	{"freezer", "Морозилка"},
+	{"shelf", "Шкаф"},
+}
+
+var pantrySortOptions = []struct {
+	Value string
+	Label string
+}{
+	{"expiry", "По сроку годности"},
+	{"name", "По названию"},
+}
+
+type PantryItem struct {
+	ID           int     `json:"id"`
+	Name         string  `json:"name"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	Display      string  `json:"display"`
+	Location     string  `json:"location"`
+	ExpiresAt    *string `json:"expires_at"`
+	DaysLeft     *int    `json:"days_left"`
+	ExpiringSoon bool    `json:"expiring_soon"`
+	Expired      bool    `json:"expired"`
+}
+
+type PantryResponse struct {
+	Status   string       `json:"status"`
+	Count    int          `json:"count"`
+	Expiring int          `json:"expiring"`
+	Items    []PantryItem `json:"items"`
+}
+
+type UseSoonSuggestion struct {
+	Recipe Recipe   `json:"recipe"`
+	Uses   []string `json:"uses"`
+}
+
+type UseSoonResponse struct {
+	Status  string              `json:"status"`
+	Recipes []UseSoonSuggestion `json:"recipes"`
+}
+
+var (
+	pantryList       *fyne.Container
+	pantrySummary    *widget.Label
+	pantrySortSelect *widget.Select
+)
+
+func pantryLocationLabel(location string) string {
+	for _, l := range pantryLocations {
+		if l.Value == location {
+			return l.Label
+		}
+	}
+	return location
+}
+
+func pantryLocationValue(label string) string {
+	for _, l := range pantryLocations {
+		if l.Label == label {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+func pantryLocationLabels() []string {
+	labels := make([]string, len(pantryLocations))
+	for i, l := range pantryLocations {
+		labels[i] = l.Label
+	}
+	return labels
+}
+
+// parseExpiryDate принимает дату как «25.12.2024», «25.12» или «2024-12-25».
+func parseExpiryDate(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", nil
+	}
+
+	for _, layout := range []string{planDateLayout, "02.01.2006", "2.1.2006"} {
+		if date, err := time.Parse(layout, text); err == nil {
+			return date.Format(planDateLayout), nil
+		}
+	}
+	if date, err := time.Parse("02.01", text); err == nil {
+		now := time.Now()
+		date = date.AddDate(now.Year(), 0, 0)
+		if date.Before(now.AddDate(0, 0, -1)) {
+			date = date.AddDate(1, 0, 0)
+		}
+		return date.Format(planDateLayout), nil
+	}
+
+	return "", fmt.Errorf("дата должна быть в формате ДД.ММ.ГГГГ")
+}
+
+func pantryExpiryText(item PantryItem) string {
+	if item.ExpiresAt == nil || item.DaysLeft == nil {
+		return "срок не указан"
+	}
+
+	date, _ := time.Parse(planDateLayout, *item.ExpiresAt)
+	switch days := *item.DaysLeft; {
+	case item.Expired:
+		return fmt.Sprintf("❗ просрочено %s", date.Format("02.01"))
+	case days == 0:
+		return "⚠ истекает сегодня"
+	case item.ExpiringSoon:
+		return fmt.Sprintf("⚠ осталось %d дн. (до %s)", days, date.Format("02.01"))
+	default:
+		return fmt.Sprintf("до %s", date.Format("02.01.2006"))
+	}
+}
+
+func createPantryTab() fyne.CanvasObject {
+	pantryList = container.NewVBox()
+	pantrySummary = widget.NewLabel("")
+
+	sortLabels := make([]string, len(pantrySortOptions))
+	for i, option := range pantrySortOptions {
+		sortLabels[i] = option.Label
+	}
+	pantrySortSelect = widget.NewSelect(sortLabels, func(string) {
+		loadPantry()
+	})
+	pantrySortSelect.Selected = sortLabels[0]
+
+	itemEntry := widget.NewEntry()
+	itemEntry.SetPlaceHolder("Например: 1 л молока")
+
+	locationSelect := widget.NewSelect(pantryLocationLabels(), nil)
+	locationSelect.SetSelected(pantryLocations[0].Label)
+
+	expiryEntry := widget.NewEntry()
+	expiryEntry.SetPlaceHolder("Годен до, ДД.ММ")
+
+	addItem := func() {
+		text := strings.TrimSpace(itemEntry.Text)
+		if text == "" {
+			return
+		}
+
+		expiresAt, err := parseExpiryDate(expiryEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s %v", iconError, err), myWindow)
+			return
+		}
+
+		_, err = apiRequest("POST", "/pantry/add", map[string]interface{}{
+			"text":       text,
+			"location":   pantryLocationValue(locationSelect.Selected),
+			"expires_at": expiresAt,
+		})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+			return
+		}
+
+		itemEntry.SetText("")
+		expiryEntry.SetText("")
+		loadPantry()
+	}
+	itemEntry.OnSubmitted = func(string) { addItem() }
+
+	useSoonBtn := widget.NewButton("💡 Что приготовить", func() {
+		showUseSoonWindow()
+	})
+
+	form := container.NewBorder(nil, nil, nil,
+		container.NewHBox(locationSelect, container.NewGridWrap(fyne.NewSize(130, 36), expiryEntry), widget.NewButton(iconAdd, addItem)),
+		itemEntry,
+	)
+
+	return container.NewBorder(
+		container.NewVBox(
+			container.NewHBox(pantrySummary, layout.NewSpacer(), useSoonBtn, pantrySortSelect),
+			form,
+			widget.NewSeparator(),
+		),
+		nil,
+		nil,
+		nil,
+		container.NewScroll(pantryList),
+	)
+}
+
+func loadPantry() {
+	if pantryList == nil || currentToken == "" {
+		return
+	}
+
+	sortValue := pantrySortOptions[0].Value
+	for _, option := range pantrySortOptions {
+		if option.Label == pantrySortSelect.Selected {
+			sortValue = option.Value
+		}
+	}
+
+	body, err := apiRequest("GET", "/pantry?sort="+sortValue, nil)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка загрузки запасов: %v", iconError, err), myWindow)
+		return
+	}
+
+	var pantryResp PantryResponse
+	json.Unmarshal(body, &pantryResp)
+
+	summary := fmt.Sprintf("%s Продуктов: %d", iconPantry, pantryResp.Count)
+	if pantryResp.Expiring > 0 {
+		summary += fmt.Sprintf(" · ⚠ пора использовать: %d", pantryResp.Expiring)
+	}
+	pantrySummary.SetText(summary)
+
+	pantryList.Objects = nil
+	if pantryResp.Count == 0 {
+		pantryList.Add(widget.NewLabel("Запасов пока нет. Добавьте, что есть дома, чтобы видеть сроки годности."))
+	}
+
+	for _, item := range pantryResp.Items {
+		item := item
+
+		text := item.Name
+		if item.Display != "" {
+			text = fmt.Sprintf("%s — %s", item.Name, item.Display)
+		}
+		style := fyne.TextStyle{Bold: item.ExpiringSoon || item.Expired}
+
+		pantryList.Add(container.NewBorder(nil, nil, nil,
+			container.NewHBox(
+				widget.NewLabel(pantryExpiryText(item)),
+				widget.NewButton(iconEdit, func() {
+					showEditPantryItemDialog(item)
+				}),
+				widget.NewButton(iconDelete, func() {
+					if _, err := apiRequest("DELETE", fmt.Sprintf("/pantry/delete?id=%d", item.ID), nil); err != nil {
+						dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+						return
+					}
+					loadPantry()
+				}),
+			),
+			widget.NewLabelWithStyle(fmt.Sprintf("%s · %s", text, pantryLocationLabel(item.Location)),
+				fyne.TextAlignLeading, style),
+		))
+	}
+
+	pantryList.Refresh()
+}
+
+func showEditPantryItemDialog(item PantryItem) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(item.Name)
+
+	quantityEntry := widget.NewEntry()
+	if item.Quantity > 0 {
+		quantityEntry.SetText(strconv.FormatFloat(item.Quantity, 'f', -1, 64))
+	}
+
+	unitSelect := widget.NewSelect([]string{"г", "мл", "шт"}, nil)
+	unitSelect.SetSelected(item.Unit)
+
+	locationSelect := widget.NewSelect(pantryLocationLabels(), nil)
+	locationSelect.SetSelected(pantryLocationLabel(item.Location))
+
+	expiryEntry := widget.NewEntry()
+	if item.ExpiresAt != nil {
+		if date, err := time.Parse(planDateLayout, *item.ExpiresAt); err == nil {
+			expiryEntry.SetText(date.Format("02.01.2006"))
+		}
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Название", nameEntry),
+		widget.NewFormItem("Количество", quantityEntry),
+		widget.NewFormItem("Единица", unitSelect),
+		widget.NewFormItem("Где хранится", locationSelect),
+		widget.NewFormItem("Годен до", expiryEntry),
+	}
+
+	dialog.ShowForm(fmt.Sprintf("%s Продукт", iconEdit), "Сохранить", "Отмена", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		quantity := 0.0
+		if text := strings.TrimSpace(quantityEntry.Text); text != "" {
+			value, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", "."), 64)
+			if err != nil || value < 0 {
+				dialog.ShowError(fmt.Errorf("%s Количество должно быть числом", iconError), myWindow)
+				return
+			}
+			quantity = value
+		}
+
+		expiresAt, err := parseExpiryDate(expiryEntry.Text)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s %v", iconError, err), myWindow)
+			return
+		}
+
+		_, err = apiRequest("PUT", "/pantry/update", map[string]interface{}{
+			"id":         item.ID,
+			"name":       nameEntry.Text,
+			"quantity":   quantity,
+			"unit":       unitSelect.Selected,
+			"location":   pantryLocationValue(locationSelect.Selected),
+			"expires_at": expiresAt,
+		})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+			return
+		}
+		loadPantry()
+	}, myWindow)
+}
+
+func showUseSoonWindow() {
+	body, err := apiRequest("GET", "/pantry/use-soon", nil)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+		return
+	}
+
+	var useSoonResp UseSoonResponse
+	json.Unmarshal(body, &useSoonResp)
+
+	useSoonWindow := myApp.NewWindow("💡 Использовать скорее")
+	useSoonWindow.Resize(fyne.NewSize(500, 480))
+
+	content := container.NewVBox()
+	if len(useSoonResp.Recipes) == 0 {
+		content.Add(widget.NewLabel("Среди ваших и избранных рецептов нет подходящих для продуктов с истекающим сроком"))
+	}
+	for _, suggestion := range useSoonResp.Recipes {
+		suggestion := suggestion
+		uses := widget.NewLabel("Использует: " + strings.Join(suggestion.Uses, ", "))
+		uses.Wrapping = fyne.TextWrapWord
+		content.Add(widget.NewButton(fmt.Sprintf("%s %s", iconRecipe, suggestion.Recipe.Title), func() {
+			showRecipeDetails(suggestion.Recipe)
+		}))
+		content.Add(uses)
+		content.Add(widget.NewSeparator())
+	}
+
+	useSoonWindow.SetContent(container.NewScroll(content))
+	useSoonWindow.Show()
+}
+
+// createCookedButton списывает ингредиенты рецепта из запасов после готовки.
+func createCookedButton(recipe Recipe, parent fyne.Window) fyne.CanvasObject {
+	return widget.NewButton("🍽 Приготовлено", func() {
+		multiplierEntry := widget.NewEntry()
+		multiplierEntry.SetText("1")
+
+		items := []*widget.FormItem{
+			widget.NewFormItem("Сколько раз по рецепту", multiplierEntry),
+		}
+
+		dialog.ShowForm("🍽 Списать из запасов", "Списать", "Отмена", items, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			multiplier, err := strconv.ParseFloat(strings.ReplaceAll(multiplierEntry.Text, ",", "."), 64)
+			if err != nil || multiplier <= 0 {
+				dialog.ShowError(fmt.Errorf("%s Множитель должен быть положительным числом", iconError), parent)
+				return
+			}
+
+			body, err := apiRequest("POST", "/pantry/cooked", map[string]interface{}{
+				"recipe_id":  recipe.ID,
+				"multiplier": multiplier,
+			})
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+				return
+			}
+
+			var cookedResp struct {
+				Deductions []struct {
+					Name      string  `json:"name"`
+					Deducted  float64 `json:"deducted"`
+					Remaining float64 `json:"remaining"`
+					Unit      string  `json:"unit"`
+				} `json:"deductions"`
+				Missing []string `json:"missing"`
+			}
+			json.Unmarshal(body, &cookedResp)
+
+			var lines []string
+			for _, d := range cookedResp.Deductions {
+				line := fmt.Sprintf("%s −%s %s", d.Name, strconv.FormatFloat(d.Deducted, 'f', -1, 64), d.Unit)
+				if d.Remaining <= 0 {
+					line += " (закончилось)"
+				}
+				lines = append(lines, line)
+			}
+			if len(lines) == 0 {
+				lines = append(lines, "В запасах не нашлось ингредиентов этого рецепта")
+			}
+			if len(cookedResp.Missing) > 0 {
+				lines = append(lines, "", "Не хватило или нет в запасах:", strings.Join(cookedResp.Missing, ", "))
+			}
+
+			dialog.ShowInformation("🍽 Списано из запасов", strings.Join(lines, "\n"), parent)
+			loadPantry()
+		}, parent)
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconPDF         = "📖"
+	pdfExtension    = ".pdf"
+	pdfSourceChosen = "Отмеченные рецепты из списка"
+)
+
+// pdfBookRequest — тело запроса /pdf-book: коллекция или список рецептов.
+type pdfBookRequest struct {
+	Title        string `json:"title,omitempty"`
+	Subtitle     string `json:"subtitle,omitempty"`
+	RecipeIDs    []int  `json:"recipe_ids,omitempty"`
+	CollectionID *int   `json:"collection_id,omitempty"`
+}
+
+// savePDFBook запрашивает книгу у сервера и сохраняет её в выбранный файл.
+func savePDFBook(req pdfBookRequest, fileName string, parent fyne.Window) {
+	body, err := apiRequest("POST", "/pdf-book", req)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка создания PDF: %v", iconError, err), parent)
+		return
+	}
+
+	saveFileWithDialog("Сохранить книгу в PDF", fileName, []string{pdfExtension}, body, parent, func() {
+		dialog.ShowInformation(fmt.Sprintf("%s Готово", iconSuccess), "Книга сохранена", parent)
+	})
+}
+
+// exportRecipePDF печатает один рецепт на странице A4.
+func exportRecipePDF(recipe Recipe, parent fyne.Window) {
+	savePDFBook(pdfBookRequest{RecipeIDs: []int{recipe.ID}}, safeFileName(recipe.Title)+pdfExtension, parent)
+}
+
+// showPDFBookDialog собирает книгу из избранного, коллекции или рецептов, отмеченных в текущем списке.
+func showPDFBookDialog(parent fyne.Window) {
+	collections, err := loadCollections(0)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка загрузки коллекций: %v", iconError, err), parent)
+		return
+	}
+
+	labels := make([]string, 0, len(collections)+1)
+	byLabel := make(map[string]Collection)
+	for _, collection := range collections {
+		label := collectionLabel(collection)
+		labels = append(labels, label)
+		byLabel[label] = collection
+	}
+	labels = append(labels, pdfSourceChosen)
+
+	titleEntry := widget.NewEntry()
+	titleEntry.SetPlaceHolder("По умолчанию — название коллекции")
+	subtitleEntry := widget.NewEntry()
+	subtitleEntry.SetPlaceHolder("Необязательно")
+
+	checks := make([]*widget.Check, len(recipes))
+	checkList := container.NewVBox()
+	for i, recipe := range recipes {
+		checks[i] = widget.NewCheck(recipe.Title, nil)
+		checkList.Add(checks[i])
+	}
+	if len(recipes) == 0 {
+		checkList.Add(widget.NewLabel("В текущем списке нет рецептов"))
+	}
+	checkScroll := container.NewVScroll(checkList)
+	checkScroll.SetMinSize(fyne.NewSize(0, 220))
+	checkScroll.Hide()
+
+	sourceSelect := widget.NewSelect(labels, func(selected string) {
+		if selected == pdfSourceChosen {
+			checkScroll.Show()
+		} else {
+			checkScroll.Hide()
+		}
+	})
+	sourceSelect.SetSelectedIndex(0)
+
+	content := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Название", titleEntry),
+			widget.NewFormItem("Подзаголовок", subtitleEntry),
+			widget.NewFormItem("Рецепты", sourceSelect),
+		),
+		checkScroll,
+	)
+
+	bookDialog := dialog.NewCustomConfirm(fmt.Sprintf("%s Книга в PDF", iconPDF), "Создать", "Отмена", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		req := pdfBookRequest{Title: titleEntry.Text, Subtitle: subtitleEntry.Text}
+		if collection, ok := byLabel[sourceSelect.Selected]; ok {
+			id := collection.ID
+			req.CollectionID = &id
+		} else {
+			for i, check := range checks {
+				if check.Checked {
+					req.RecipeIDs = append(req.RecipeIDs, recipes[i].ID)
+				}
+			}
+			if len(req.RecipeIDs) == 0 {
+				dialog.ShowError(fmt.Errorf("%s Отметьте хотя бы один рецепт", iconError), parent)
+				return
+			}
+		}
+
+		savePDFBook(req, fmt.Sprintf("cookbook-%s%s", time.Now().Format("2006-01-02"), pdfExtension), parent)
+	}, parent)
+	bookDialog.Resize(fyne.NewSize(520, 0))
+	bookDialog.Show()
+}
@@ -0,0 +1,490 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	planDateLayout      = "2006-01-02"
+	defaultPlanServings = 2
+)
+
+var plannerTabTitle = fmt.Sprintf("%s Меню", iconCalendar)
+
+var mealSlots = []struct {
+	Value string
+	Label string
+}{
+	{"breakfast", "Завтрак"},
+	{"lunch", "Обед"},
+	{"dinner", "Ужин"},
+	{"snack", "Перекус"},
+}
+
+var weekdayLabels = []string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}
+
+type MealPlanEntry struct {
+	ID          int    `json:"id"`
+	Date        string `json:"date"`
+	Slot        string `json:"slot"`
+	RecipeID    *int   `json:"recipe_id,omitempty"`
+	RecipeTitle string `json:"recipe_title"`
+	Servings    int    `json:"servings"`
+	Note        string `json:"note"`
+}
+
+type MealPlanResponse struct {
+	Status  string          `json:"status"`
+	Entries []MealPlanEntry `json:"entries"`
+}
+
+var (
+	mainTabs          *container.AppTabs
+	plannerGrid       *fyne.Container
+	plannerRecipes    *fyne.Container
+	plannerWeekLabel  *widget.Label
+	plannerWeek       time.Time
+	pendingPlanRecipe *Recipe
+	plannerCells      []plannerCell
+	plannerDragPos    fyne.Position
+)
+
+// plannerCell — ячейка сетки меню, в которую можно бросить рецепт.
+type plannerCell struct {
+	object     fyne.CanvasObject
+	date, slot string
+}
+
+// plannerCellAt находит ячейку меню под точкой в координатах окна.
+func plannerCellAt(pos fyne.Position) *plannerCell {
+	driver := fyne.CurrentApp().Driver()
+	for i := range plannerCells {
+		cell := &plannerCells[i]
+		origin := driver.AbsolutePositionForObject(cell.object)
+		size := cell.object.Size()
+		if pos.X >= origin.X && pos.X < origin.X+size.Width && pos.Y >= origin.Y && pos.Y < origin.Y+size.Height {
+			return cell
+		}
+	}
+	return nil
+}
+
+// planDragRecipe — рецепт в списке рядом с сеткой меню. Его перетаскивают в ячейку;
+// нажатие выбирает рецепт так же, как кнопка 📆 на карточке.
+type planDragRecipe struct {
+	widget.Label
+	recipe   Recipe
+	dragging bool
+}
+
+func newPlanDragRecipe(recipe Recipe) *planDragRecipe {
+	item := &planDragRecipe{recipe: recipe}
+	item.Text = fmt.Sprintf("%s %s", iconRecipe, truncateText(recipe.Title, 24))
+	item.ExtendBaseWidget(item)
+	return item
+}
+
+func (p *planDragRecipe) Tapped(*fyne.PointEvent) {
+	planRecipe(p.recipe)
+}
+
+func (p *planDragRecipe) Dragged(event *fyne.DragEvent) {
+	if !p.dragging {
+		p.dragging = true
+		statusLabel.SetText(fmt.Sprintf("%s «%s»: отпустите над ячейкой меню", iconCalendar, p.recipe.Title))
+	}
+	plannerDragPos = event.AbsolutePosition
+}
+
+func (p *planDragRecipe) DragEnd() {
+	p.dragging = false
+	cell := plannerCellAt(plannerDragPos)
+	if cell == nil {
+		statusLabel.SetText("")
+		return
+	}
+	addRecipeToPlan(p.recipe, cell.date, cell.slot, defaultPlanServings, "")
+}
+
+// mondayOf возвращает начало недели (понедельник) для даты.
+func mondayOf(date time.Time) time.Time {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+}
+
+func mealSlotLabel(slot string) string {
+	for _, s := range mealSlots {
+		if s.Value == slot {
+			return s.Label
+		}
+	}
+	return slot
+}
+
+func mealPlanEntryText(entry MealPlanEntry) string {
+	if entry.RecipeID == nil {
+		return "📝 " + entry.Note
+	}
+
+	title := entry.RecipeTitle
+	if title == "" {
+		title = "Рецепт недоступен"
+	}
+	text := fmt.Sprintf("%s %s ×%d", iconRecipe, truncateText(title, 18), entry.Servings)
+	if entry.Note != "" {
+		text += " · " + truncateText(entry.Note, 12)
+	}
+	return text
+}
+
+func createPlannerTab() fyne.CanvasObject {
+	plannerWeek = mondayOf(time.Now())
+	plannerGrid = container.NewGridWithColumns(len(weekdayLabels) + 1)
+	plannerRecipes = container.NewVBox()
+	plannerWeekLabel = widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	shiftWeek := func(days int) {
+		plannerWeek = plannerWeek.AddDate(0, 0, days)
+		loadMealPlan()
+	}
+
+	prevBtn := widget.NewButton("◀", func() { shiftWeek(-7) })
+	nextBtn := widget.NewButton("▶", func() { shiftWeek(7) })
+	todayBtn := widget.NewButton("Сегодня", func() {
+		plannerWeek = mondayOf(time.Now())
+		loadMealPlan()
+	})
+
+	copyBtn := widget.NewButton("📋 Скопировать прошлую неделю", func() {
+		dialog.ShowConfirm("Копирование недели",
+			"Добавить в эту неделю всё, что было запланировано на прошлой?",
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				_, err := apiRequest("POST", "/meal-plan/copy-week", map[string]interface{}{
+					"from_week": plannerWeek.AddDate(0, 0, -7).Format(planDateLayout),
+					"to_week":   plannerWeek.Format(planDateLayout),
+				})
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+					return
+				}
+				loadMealPlan()
+			}, myWindow)
+	})
+
+	clearBtn := widget.NewButton(fmt.Sprintf("%s Очистить неделю", iconDelete), func() {
+		dialog.ShowConfirm("Очистка недели", "Удалить все записи этой недели?", func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if _, err := apiRequest("DELETE", "/meal-plan/clear-week?week="+plannerWeek.Format(planDateLayout), nil); err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+				return
+			}
+			loadMealPlan()
+		}, myWindow)
+	})
+
+	shoppingBtn := widget.NewButton(fmt.Sprintf("%s В покупки", iconShopping), func() {
+		generateShoppingListFromWeek()
+	})
+
+	return container.NewBorder(
+		container.NewVBox(
+			container.NewHBox(prevBtn, todayBtn, nextBtn, plannerWeekLabel, layout.NewSpacer(), shoppingBtn, copyBtn, clearBtn),
+			widget.NewLabel("Перетащите рецепт из списка слева в ячейку или нажмите 📆 на карточке рецепта, затем ➕ в нужной ячейке"),
+			widget.NewSeparator(),
+		),
+		nil,
+		container.NewBorder(
+			widget.NewLabelWithStyle("Мои рецепты", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			nil, nil, nil,
+			container.NewVScroll(plannerRecipes),
+		),
+		nil,
+		container.NewScroll(plannerGrid),
+	)
+}
+
+// planRecipe запоминает рецепт с карточки и переводит на вкладку меню, где его можно поставить в ячейку.
+func planRecipe(recipe Recipe) {
+	pendingPlanRecipe = &recipe
+	statusLabel.SetText(fmt.Sprintf("%s «%s»: выберите ячейку в меню", iconCalendar, recipe.Title))
+	if mainTabs != nil {
+		for _, tab := range mainTabs.Items {
+			if tab.Text == plannerTabTitle {
+				mainTabs.Select(tab)
+			}
+		}
+	}
+}
+
+func loadMealPlan() {
+	if plannerGrid == nil || currentToken == "" {
+		return
+	}
+
+	weekEnd := plannerWeek.AddDate(0, 0, 6)
+	plannerWeekLabel.SetText(fmt.Sprintf("%s – %s", plannerWeek.Format("02.01"), weekEnd.Format("02.01.2006")))
+
+	body, err := apiRequest("GET", fmt.Sprintf("/meal-plan?from=%s&to=%s",
+		plannerWeek.Format(planDateLayout), weekEnd.Format(planDateLayout)), nil)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка загрузки меню: %v", iconError, err), myWindow)
+		return
+	}
+
+	var planResp MealPlanResponse
+	json.Unmarshal(body, &planResp)
+
+	cells := make(map[string][]MealPlanEntry)
+	for _, entry := range planResp.Entries {
+		key := entry.Date + "|" + entry.Slot
+		cells[key] = append(cells[key], entry)
+	}
+
+	plannerRecipes.Objects = nil
+	for _, recipe := range recipes {
+		plannerRecipes.Add(newPlanDragRecipe(recipe))
+	}
+	plannerRecipes.Refresh()
+
+	plannerGrid.Objects = nil
+	plannerCells = nil
+
+	plannerGrid.Add(widget.NewLabel(""))
+	today := time.Now().Format(planDateLayout)
+	for i, label := range weekdayLabels {
+		day := plannerWeek.AddDate(0, 0, i)
+		style := fyne.TextStyle{Bold: day.Format(planDateLayout) == today}
+		plannerGrid.Add(widget.NewLabelWithStyle(fmt.Sprintf("%s %s", label, day.Format("02.01")), fyne.TextAlignCenter, style))
+	}
+
+	for _, slot := range mealSlots {
+		plannerGrid.Add(widget.NewLabelWithStyle(slot.Label, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+
+		for i := range weekdayLabels {
+			date := plannerWeek.AddDate(0, 0, i).Format(planDateLayout)
+			slotValue := slot.Value
+
+			cell := container.NewVBox()
+			for _, entry := range cells[date+"|"+slotValue] {
+				entry := entry
+				entryBtn := widget.NewButton(mealPlanEntryText(entry), func() {
+					showMealPlanEntryDialog(entry)
+				})
+				entryBtn.Importance = widget.LowImportance
+				entryBtn.Alignment = widget.ButtonAlignLeading
+				cell.Add(entryBtn)
+			}
+
+			addBtn := widget.NewButton(iconAdd, func() {
+				if pendingPlanRecipe != nil {
+					addRecipeToPlan(*pendingPlanRecipe, date, slotValue, defaultPlanServings, "")
+					return
+				}
+				showAddMealPlanDialog(date, slotValue)
+			})
+			addBtn.Importance = widget.LowImportance
+			cell.Add(addBtn)
+
+			plannerCells = append(plannerCells, plannerCell{object: cell, date: date, slot: slotValue})
+			plannerGrid.Add(cell)
+		}
+	}
+
+	plannerGrid.Refresh()
+}
+
+func addRecipeToPlan(recipe Recipe, date, slot string, servings int, note string) {
+	_, err := apiRequest("POST", "/meal-plan/add", map[string]interface{}{
+		"date":      date,
+		"slot":      slot,
+		"recipe_id": recipe.ID,
+		"servings":  servings,
+		"note":      note,
+	})
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+		return
+	}
+
+	pendingPlanRecipe = nil
+	statusLabel.SetText(fmt.Sprintf("%s «%s» добавлен в меню", iconSuccess, recipe.Title))
+	loadMealPlan()
+}
+
+// showAddMealPlanDialog добавляет в ячейку рецепт из списка «Мои рецепты» или произвольную запись.
+func showAddMealPlanDialog(date, slot string) {
+	titles := []string{}
+	byTitle := make(map[string]Recipe)
+	for _, recipe := range recipes {
+		title := recipe.Title
+		if _, exists := byTitle[title]; exists {
+			title = fmt.Sprintf("%s (#%d)", recipe.Title, recipe.ID)
+		}
+		titles = append(titles, title)
+		byTitle[title] = recipe
+	}
+
+	recipeSelect := widget.NewSelect(titles, nil)
+	recipeSelect.PlaceHolder = "Без рецепта"
+
+	servingsEntry := widget.NewEntry()
+	servingsEntry.SetText(strconv.Itoa(defaultPlanServings))
+
+	noteEntry := widget.NewEntry()
+	noteEntry.SetPlaceHolder("Например: ужин в гостях")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Рецепт", recipeSelect),
+		widget.NewFormItem("Порций", servingsEntry),
+		widget.NewFormItem("Заметка", noteEntry),
+	}
+
+	title := fmt.Sprintf("%s %s, %s", iconCalendar, mealSlotLabel(slot), formatPlanDate(date))
+	dialog.ShowForm(title, "Добавить", "Отмена", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		note := strings.TrimSpace(noteEntry.Text)
+		recipe, hasRecipe := byTitle[recipeSelect.Selected]
+		if hasRecipe {
+			servings, err := strconv.Atoi(servingsEntry.Text)
+			if err != nil || servings <= 0 {
+				dialog.ShowError(fmt.Errorf("%s Количество порций должно быть положительным числом", iconError), myWindow)
+				return
+			}
+			addRecipeToPlan(recipe, date, slot, servings, note)
+			return
+		}
+
+		if note == "" {
+			dialog.ShowError(fmt.Errorf("%s Выберите рецепт или напишите заметку", iconError), myWindow)
+			return
+		}
+		_, err := apiRequest("POST", "/meal-plan/add", map[string]interface{}{
+			"date": date,
+			"slot": slot,
+			"note": note,
+		})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+			return
+		}
+		loadMealPlan()
+	}, myWindow)
+}
+
+// showMealPlanEntryDialog позволяет перенести запись на другой день или приём пищи, поменять порции или удалить её.
+func showMealPlanEntryDialog(entry MealPlanEntry) {
+	entryDate, _ := time.Parse(planDateLayout, entry.Date)
+	start := mondayOf(entryDate)
+
+	dayOptions := make([]string, len(weekdayLabels))
+	dayDates := make(map[string]string)
+	for i, label := range weekdayLabels {
+		day := start.AddDate(0, 0, i)
+		dayOptions[i] = fmt.Sprintf("%s %s", label, day.Format("02.01"))
+		dayDates[dayOptions[i]] = day.Format(planDateLayout)
+	}
+	daySelect := widget.NewSelect(dayOptions, nil)
+	daySelect.SetSelectedIndex((int(entryDate.Weekday()) + 6) % 7)
+
+	slotOptions := make([]string, len(mealSlots))
+	slotValues := make(map[string]string)
+	for i, slot := range mealSlots {
+		slotOptions[i] = slot.Label
+		slotValues[slot.Label] = slot.Value
+	}
+	slotSelect := widget.NewSelect(slotOptions, nil)
+	slotSelect.SetSelected(mealSlotLabel(entry.Slot))
+
+	servingsEntry := widget.NewEntry()
+	servingsEntry.SetText(strconv.Itoa(entry.Servings))
+
+	noteEntry := widget.NewEntry()
+	noteEntry.SetText(entry.Note)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("День", daySelect),
+		widget.NewFormItem("Приём пищи", slotSelect),
+	}
+	if entry.RecipeID != nil {
+		items = append(items, widget.NewFormItem("Порций", servingsEntry))
+	}
+	items = append(items, widget.NewFormItem("Заметка", noteEntry))
+
+	var entryDialog dialog.Dialog
+
+	deleteBtn := widget.NewButton(fmt.Sprintf("%s Удалить из меню", iconDelete), func() {
+		if _, err := apiRequest("DELETE", fmt.Sprintf("/meal-plan/delete?id=%d", entry.ID), nil); err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+			return
+		}
+		entryDialog.Hide()
+		loadMealPlan()
+	})
+
+	actions := container.NewHBox(deleteBtn)
+	if entry.RecipeID != nil && entry.RecipeTitle != "" {
+		recipeID := *entry.RecipeID
+		actions.Add(widget.NewButton(fmt.Sprintf("%s Открыть рецепт", iconRecipe), func() {
+			openRecipeByID(recipeID)
+		}))
+	}
+
+	content := container.NewVBox(widget.NewForm(items...), actions)
+
+	entryDialog = dialog.NewCustomConfirm(mealPlanEntryText(entry), "Сохранить", "Закрыть", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		servings := entry.Servings
+		if entry.RecipeID != nil {
+			value, err := strconv.Atoi(servingsEntry.Text)
+			if err != nil || value <= 0 {
+				dialog.ShowError(fmt.Errorf("%s Количество порций должно быть положительным числом", iconError), myWindow)
+				return
+			}
+			servings = value
+		}
+
+		_, err := apiRequest("PUT", "/meal-plan/update", map[string]interface{}{
+			"id":       entry.ID,
+			"date":     dayDates[daySelect.Selected],
+			"slot":     slotValues[slotSelect.Selected],
+			"servings": servings,
+			"note":     noteEntry.Text,
+		})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), myWindow)
+			return
+		}
+		loadMealPlan()
+	}, myWindow)
+	entryDialog.Resize(fyne.NewSize(420, 360))
+	entryDialog.Show()
+}
+
+func formatPlanDate(date string) string {
+	day, err := time.Parse(planDateLayout, date)
+	if err != nil {
+		return date
+	}
+	return fmt.Sprintf("%s %s", weekdayLabels[(int(day.Weekday())+6)%7], day.Format("02.01"))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const iconRating = "⭐"
+
+type Review struct {
+	ID         int       `json:"id"`
+	RecipeID   int       `json:"recipe_id"`
+	UserID     int       `json:"user_id"`
+	AuthorName string    `json:"author_name"`
+	Rating     int       `json:"rating"`
+	Text       string    `json:"text"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ReviewsResponse struct {
+	Status  string   `json:"status"`
+	Reviews []Review `json:"reviews"`
+	Summary struct {
+		Average float64 `json:"average"`
+		Count   int     `json:"count"`
+	} `json:"summary"`
+	MyReview *Review `json:"my_review"`
+}
+
+var sortOptions = []struct {
+	Value string
+	Label string
+}{
+	{"newest", "🆕 Сначала новые"},
+	{"rating", "⭐ По рейтингу"},
+	{"cost", "💰 Дешевле за порцию"},
+}
+
+func sortLabels() []string {
+	labels := make([]string, 0, len(sortOptions))
+	for _, opt := range sortOptions {
+		labels = append(labels, opt.Label)
+	}
+	return labels
+}
+
+func sortValue(label string) string {
+	for _, opt := range sortOptions {
+		if opt.Label == label {
+			return opt.Value
+		}
+	}
+	return sortOptions[0].Value
+}
+
+func ratingStars(rating int) string {
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
+
+func ratingSummaryText(average float64, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%s Оценок пока нет", iconRating)
+	}
+	return fmt.Sprintf("%s %.1f (%d)", iconRating, average, count)
+}
+
+func createReviewsSection(recipe Recipe, parent fyne.Window) fyne.CanvasObject {
+	summaryLabel := widget.NewLabelWithStyle(ratingSummaryText(recipe.RatingAvg, recipe.RatingCount),
+		fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	reviewsList := container.NewVBox()
+	reviewBtn := widget.NewButton(fmt.Sprintf("%s Оставить отзыв", iconRating), nil)
+
+	var loadReviews func()
+	loadReviews = func() {
+		body, err := apiRequest("GET", fmt.Sprintf("/reviews?recipe_id=%d", recipe.ID), nil)
+		if err != nil {
+			reviewsList.Objects = []fyne.CanvasObject{widget.NewLabel("Не удалось загрузить отзывы")}
+			reviewsList.Refresh()
+			return
+		}
+
+		var reviewsResp ReviewsResponse
+		json.Unmarshal(body, &reviewsResp)
+
+		summaryLabel.SetText(ratingSummaryText(reviewsResp.Summary.Average, reviewsResp.Summary.Count))
+
+		myReview := reviewsResp.MyReview
+		if myReview != nil {
+			reviewBtn.SetText(fmt.Sprintf("%s Изменить мой отзыв", iconEdit))
+		} else {
+			reviewBtn.SetText(fmt.Sprintf("%s Оставить отзыв", iconRating))
+		}
+		reviewBtn.OnTapped = func() {
+			showReviewForm(recipe, myReview, parent, loadReviews)
+		}
+
+		reviewsList.Objects = nil
+		for _, review := range reviewsResp.Reviews {
+			header := fmt.Sprintf("%s  %s %s · %s", ratingStars(review.Rating), iconUser,
+				review.AuthorName, review.UpdatedAt.Local().Format("02.01.2006"))
+			reviewsList.Add(widget.NewLabelWithStyle(header, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			if review.Text != "" {
+				textLabel := widget.NewLabel(review.Text)
+				textLabel.Wrapping = fyne.TextWrapWord
+				reviewsList.Add(textLabel)
+			}
+		}
+		reviewsList.Refresh()
+	}
+
+	loadReviews()
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(fmt.Sprintf("%s Отзывы", iconRating),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewSeparator(),
+		summaryLabel,
+		reviewBtn,
+		reviewsList,
+	)
+}
+
+func showReviewForm(recipe Recipe, existing *Review, parent fyne.Window, onSaved func()) {
+	ratingOptions := []string{ratingStars(5), ratingStars(4), ratingStars(3), ratingStars(2), ratingStars(1)}
+
+	ratingSelect := widget.NewRadioGroup(ratingOptions, nil)
+	ratingSelect.Required = true
+
+	textEntry := widget.NewMultiLineEntry()
+	textEntry.SetPlaceHolder("Как получилось? (необязательно)")
+	textEntry.Wrapping = fyne.TextWrapWord
+	textEntry.SetMinRowsVisible(4)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Оценка:", ratingSelect),
+		widget.NewFormItem("Отзыв:", textEntry),
+	}
+
+	var formDialog dialog.Dialog
+
+	if existing != nil {
+		ratingSelect.SetSelected(ratingStars(existing.Rating))
+		textEntry.SetText(existing.Text)
+
+		deleteBtn := widget.NewButton(fmt.Sprintf("%s Удалить отзыв", iconDelete), func() {
+			formDialog.Hide()
+			if _, err := apiRequest("DELETE", fmt.Sprintf("/reviews/delete?recipe_id=%d", recipe.ID), nil); err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+				return
+			}
+			onSaved()
+		})
+		items = append(items, widget.NewFormItem("", deleteBtn))
+	}
+
+	formDialog = dialog.NewForm(fmt.Sprintf("%s Отзыв: %s", iconRating, recipe.Title), "Сохранить", "Отмена", items,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			rating := strings.Count(ratingSelect.Selected, "★")
+			if rating == 0 {
+				dialog.ShowError(fmt.Errorf("Выберите оценку"), parent)
+				return
+			}
+
+			_, err := apiRequest("POST", "/reviews/save", map[string]interface{}{
+				"recipe_id": recipe.ID,
+				"rating":    rating,
+				"text":      textEntry.Text,
+			})
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+				return
+			}
+
+			onSaved()
+		}, parent)
+
+	formDialog.Resize(fyne.NewSize(420, 320))
+	formDialog.Show()
+}
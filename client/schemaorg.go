@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconWebImport          = "🌐"
+	maxImportImageDownload = 10 * 1024 * 1024
+	defaultImportTitle     = "Рецепт без названия"
+	defaultImportLevel     = "средняя"
+)
+
+// ParsedRecipe — рецепт, найденный сервером в разметке schema.org. Картинку по ImageURL
+// клиент загружает сам: относительный путь указывает на файл рядом с сохранённой страницей.
+type ParsedRecipe struct {
+	Recipe   Recipe `json:"recipe"`
+	ImageURL string `json:"image_url"`
+	Source   string `json:"source"`
+}
+
+type ParseRecipesResponse struct {
+	Status  string         `json:"status"`
+	Count   int            `json:"count"`
+	Recipes []ParsedRecipe `json:"recipes"`
+}
+
+// exportRecipeJSONLD сохраняет рецепт в файл schema.org JSON-LD.
+func exportRecipeJSONLD(recipe Recipe, parent fyne.Window) {
+	body, err := apiRequest("GET", fmt.Sprintf("/schema-org?recipe_id=%d", recipe.ID), nil)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка выгрузки: %v", iconError, err), parent)
+		return
+	}
+
+	saveFileWithDialog("Сохранить рецепт в JSON-LD", safeFileName(recipe.Title)+".jsonld", []string{".jsonld", ".json"}, body, parent, func() {
+		dialog.ShowInformation(fmt.Sprintf("%s Готово", iconSuccess), "Рецепт сохранен", parent)
+	})
+}
+
+// loadImportImage загружает картинку рецепта по ссылке, пути file:// или пути относительно папки страницы.
+func loadImportImage(ref, baseDir string) ([]byte, error) {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "//") {
+		ref = "https:" + ref
+	}
+
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("неверная ссылка на изображение")
+	}
+
+	switch parsed.Scheme {
+	case "http", "https":
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Get(ref)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("сервер ответил %s", resp.Status)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxImportImageDownload+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxImportImageDownload {
+			return nil, fmt.Errorf("изображение больше %d МБ", maxImportImageDownload/1024/1024)
+		}
+		return data, nil
+	case "file":
+		path := parsed.Path
+		// file:///C:/... — путь Windows с буквой диска
+		if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+			path = path[1:]
+		}
+		return os.ReadFile(filepath.FromSlash(path))
+	case "":
+		if baseDir == "" {
+			return nil, errors.New("относительный путь, а файл страницы не выбран")
+		}
+		return os.ReadFile(filepath.Join(baseDir, filepath.FromSlash(parsed.Path)))
+	}
+	return nil, fmt.Errorf("неподдерживаемая ссылка %s", parsed.Scheme)
+}
+
+// encodeImportedImage приводит картинку к тому же виду, что и редактор фото: JPEG в пределах maxImageSize.
+func encodeImportedImage(data []byte) (string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("не удалось распознать изображение")
+	}
+
+	jpegBytes, err := fitImageToLimit(applyOrientation(limitImageDimension(img), exifOrientation(data)), maxImageSize)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(jpegBytes), nil
+}
+
+// saveImportedRecipe создаёт рецепт из импортированных данных в текущей книге.
+// Сложности в schema.org нет, поэтому пустая заменяется средней; рецепт создаётся личным.
+func saveImportedRecipe(recipe Recipe) error {
+	if strings.TrimSpace(recipe.Title) == "" {
+		recipe.Title = defaultImportTitle
+	}
+	if recipe.Difficulty == "" {
+		recipe.Difficulty = defaultImportLevel
+	}
+	if recipe.Visibility == "" {
+		recipe.Visibility = "private"
+	}
+
+	_, err := apiRequest("POST", "/create-recipe", map[string]interface{}{
+		"title":        recipe.Title,
+		"description":  recipe.Description,
+		"ingredients":  recipe.Ingredients,
+		"instructions": recipe.Instructions,
+		"cooking_time": recipe.CookingTime,
+		"servings":     recipe.Servings,
+		"difficulty":   recipe.Difficulty,
+		"image_base64": recipe.ImageBase64,
+		"visibility":   recipe.Visibility,
+		"cookbook_id":  currentCookbookID,
+	})
+	return err
+}
+
+func parsedRecipeSummary(parsed ParsedRecipe) string {
+	parts := []string{
+		fmt.Sprintf("%s %d ингр.", iconBullet, len(parsed.Recipe.Ingredients)),
+		fmt.Sprintf("%d шагов", len(splitSteps(parsed.Recipe.Instructions))),
+	}
+	if parsed.Recipe.CookingTime > 0 {
+		parts = append(parts, fmt.Sprintf("%s %d мин", iconTime, parsed.Recipe.CookingTime))
+	}
+	if parsed.Recipe.Servings > 0 {
+		parts = append(parts, fmt.Sprintf("🍽️ %d", parsed.Recipe.Servings))
+	}
+	if parsed.Recipe.ImageBase64 != "" || parsed.ImageURL != "" {
+		parts = append(parts, "📷")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// showWebImportWindow — импорт рецептов из разметки schema.org: из сохранённой HTML-страницы
+// или вставленного текста. Найденные рецепты показываются списком, сохраняются отмеченные.
+func showWebImportWindow() {
+	importWindow := myApp.NewWindow(fmt.Sprintf("%s Импорт со страницы", iconWebImport))
+	importWindow.Resize(fyne.NewSize(600, 650))
+
+	var fileContent, baseDir string
+	var parsed []ParsedRecipe
+	var checks []*widget.Check
+
+	fileLabel := widget.NewLabel("")
+	pasteEntry := widget.NewMultiLineEntry()
+	pasteEntry.SetPlaceHolder("Вставьте HTML-код страницы рецепта или JSON-LD")
+	pasteEntry.Wrapping = fyne.TextWrapBreak
+	pasteEntry.SetMinRowsVisible(8)
+	pasteEntry.OnChanged = func(text string) {
+		if text != "" && fileContent != "" {
+			fileContent, baseDir = "", ""
+			fileLabel.SetText("")
+		}
+	}
+
+	results := container.NewVBox()
+	var saveBtn *widget.Button
+
+	openBtn := widget.NewButton("📂 Открыть HTML-файл…", func() {
+		openFileWithDialog("Выберите сохраненную страницу рецепта", []string{".html", ".htm", ".jsonld", ".json"}, importWindow,
+			func(path string, data []byte) {
+				pasteEntry.SetText("")
+				fileContent, baseDir = string(data), filepath.Dir(path)
+				fileLabel.SetText(fmt.Sprintf("📄 %s (%d КБ)", filepath.Base(path), len(data)/1024+1))
+			})
+	})
+
+	findBtn := widget.NewButton("🔍 Найти рецепты", func() {
+		content := fileContent
+		if content == "" {
+			content = pasteEntry.Text
+		}
+		if strings.TrimSpace(content) == "" {
+			dialog.ShowError(fmt.Errorf("%s Выберите файл или вставьте текст страницы", iconError), importWindow)
+			return
+		}
+
+		body, err := apiRequest("POST", "/schema-org/parse", map[string]string{"content": content})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), importWindow)
+			return
+		}
+
+		var parseResp ParseRecipesResponse
+		json.Unmarshal(body, &parseResp)
+		parsed = parseResp.Recipes
+
+		results.RemoveAll()
+		checks = nil
+		results.Add(widget.NewLabelWithStyle(fmt.Sprintf("Найдено рецептов: %d", len(parsed)),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, item := range parsed {
+			title := item.Recipe.Title
+			if title == "" {
+				title = defaultImportTitle
+			}
+			check := widget.NewCheck(title, nil)
+			check.SetChecked(true)
+			checks = append(checks, check)
+			results.Add(container.NewVBox(check, widget.NewLabel(parsedRecipeSummary(item))))
+		}
+		if len(parsed) > 0 {
+			saveBtn.Enable()
+		}
+	})
+
+	saveBtn = widget.NewButton("💾 Сохранить отмеченные", func() {
+		saveBtn.Disable()
+		go func() {
+			saved := 0
+			var warnings []string
+			for i, item := range parsed {
+				if !checks[i].Checked {
+					continue
+				}
+
+				recipe := item.Recipe
+				if recipe.ImageBase64 == "" && item.ImageURL != "" {
+					data, err := loadImportImage(item.ImageURL, baseDir)
+					if err == nil {
+						recipe.ImageBase64, err = encodeImportedImage(data)
+					}
+					if err != nil {
+						warnings = append(warnings, fmt.Sprintf("%s «%s»: фото не загружено (%v)", iconBullet, recipe.Title, err))
+					}
+				}
+
+				if err := saveImportedRecipe(recipe); err != nil {
+					warnings = append(warnings, fmt.Sprintf("%s «%s»: %v", iconBullet, recipe.Title, err))
+					continue
+				}
+				saved++
+			}
+
+			lines := append([]string{fmt.Sprintf("Сохранено рецептов: %d", saved)}, warnings...)
+			dialog.ShowInformation(fmt.Sprintf("%s Импорт завершен", iconSuccess), strings.Join(lines, "\n"), importWindow)
+			reloadCurrentListing()
+		}()
+	})
+	saveBtn.Disable()
+
+	importWindow.SetContent(container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel("Рецепт ищется в разметке schema.org (JSON-LD или микроданные)."),
+			container.NewHBox(openBtn, fileLabel),
+			pasteEntry,
+			findBtn,
+			widget.NewSeparator(),
+		),
+		container.NewHBox(saveBtn),
+		nil, nil,
+		container.NewScroll(results),
+	))
+	importWindow.Show()
+}
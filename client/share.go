@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+type RecipeShare struct {
+	ID        int        `json:"id"`
+	RecipeID  int        `json:"recipe_id"`
+	Token     string     `json:"token"`
+	Path      string     `json:"path"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type SharesResponse struct {
+	Status string        `json:"status"`
+	Shares []RecipeShare `json:"shares"`
+}
+
+var shareExpiryOptions = []struct {
+	Label string
+	Hours int
+}{
+	{"Без срока", 0},
+	{"1 день", 24},
+	{"7 дней", 24 * 7},
+	{"30 дней", 24 * 30},
+}
+
+func getServerURL() string {
+	return strings.TrimSuffix(strings.TrimSuffix(getAPIURL(), "/"), "/api")
+}
+
+func shareURL(share RecipeShare) string {
+	return getServerURL() + share.Path
+}
+
+func showShareWindow(recipe Recipe) {
+	shareWindow := myApp.NewWindow(fmt.Sprintf("🔗 Ссылки: %s", recipe.Title))
+	shareWindow.Resize(fyne.NewSize(560, 420))
+
+	sharesList := container.NewVBox()
+
+	var loadShares func()
+	loadShares = func() {
+		body, err := apiRequest("GET", fmt.Sprintf("/shares?recipe_id=%d", recipe.ID), nil)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка загрузки ссылок: %v", iconError, err), shareWindow)
+			return
+		}
+
+		var sharesResp SharesResponse
+		json.Unmarshal(body, &sharesResp)
+
+		sharesList.Objects = nil
+		if len(sharesResp.Shares) == 0 {
+			sharesList.Add(widget.NewLabel("Активных ссылок нет"))
+		}
+
+		for _, share := range sharesResp.Shares {
+			share := share
+
+			expires := "бессрочно"
+			if share.ExpiresAt != nil {
+				expires = "до " + share.ExpiresAt.Local().Format("02.01.2006 15:04")
+			}
+
+			copyBtn := widget.NewButton("📋 Копировать", func() {
+				shareWindow.Clipboard().SetContent(shareURL(share))
+				dialog.ShowInformation(iconSuccess, "Ссылка скопирована в буфер обмена", shareWindow)
+			})
+
+			revokeBtn := widget.NewButton(fmt.Sprintf("%s Отозвать", iconDelete), func() {
+				dialog.ShowConfirm("Отзыв ссылки", "Ссылка перестанет открываться. Продолжить?", func(confirmed bool) {
+					if !confirmed {
+						return
+					}
+					if _, err := apiRequest("DELETE", fmt.Sprintf("/shares/revoke?id=%d", share.ID), nil); err != nil {
+						dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), shareWindow)
+						return
+					}
+					loadShares()
+				}, shareWindow)
+			})
+
+			urlEntry := widget.NewEntry()
+			urlEntry.SetText(shareURL(share))
+
+			sharesList.Add(container.NewVBox(
+				widget.NewLabel(fmt.Sprintf("%s Создана %s, действует %s",
+					iconCalendar, share.CreatedAt.Local().Format("02.01.2006 15:04"), expires)),
+				container.NewBorder(nil, nil, nil, container.NewHBox(copyBtn, revokeBtn), urlEntry),
+				widget.NewSeparator(),
+			))
+		}
+		sharesList.Refresh()
+	}
+
+	expiryLabels := make([]string, 0, len(shareExpiryOptions))
+	for _, opt := range shareExpiryOptions {
+		expiryLabels = append(expiryLabels, opt.Label)
+	}
+	expirySelect := widget.NewSelect(expiryLabels, nil)
+	expirySelect.Selected = expiryLabels[0]
+
+	createBtn := widget.NewButton("🔗 Создать ссылку", func() {
+		hours := 0
+		for _, opt := range shareExpiryOptions {
+			if opt.Label == expirySelect.Selected {
+				hours = opt.Hours
+			}
+		}
+
+		_, err := apiRequest("POST", "/shares/create", map[string]int{
+			"recipe_id":        recipe.ID,
+			"expires_in_hours": hours,
+		})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка создания ссылки: %v", iconError, err), shareWindow)
+			return
+		}
+		loadShares()
+	})
+	createBtn.Importance = widget.HighImportance
+
+	closeBtn := widget.NewButton(fmt.Sprintf("%s Закрыть", iconClose), func() {
+		shareWindow.Close()
+	})
+
+	shareWindow.SetContent(container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel("Рецепт можно открыть по ссылке без регистрации, только для чтения."),
+			container.NewHBox(widget.NewLabel("Срок действия:"), expirySelect, createBtn),
+			widget.NewSeparator(),
+		),
+		container.NewHBox(layout.NewSpacer(), closeBtn),
+		nil,
+		nil,
+		container.NewScroll(sharesList),
+	))
+
+	loadShares()
+	shareWindow.Show()
+}
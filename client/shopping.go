@@ -0,0 +1,350 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconShopping         = "🛒"
+	shoppingSyncInterval = 15 * time.Second
+)
+
+type ShoppingItem struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Display  string  `json:"display"`
+	Category string  `json:"category"`
+	Checked  bool    `json:"checked"`
+	Manual   bool    `json:"manual"`
+	Sources  string  `json:"sources"`
+}
+
+type ShoppingGroup struct {
+	Category string         `json:"category"`
+	Items    []ShoppingItem `json:"items"`
+}
+
+type ShoppingListResponse struct {
+	Status  string          `json:"status"`
+	Count   int             `json:"count"`
+	Checked int             `json:"checked"`
+	Groups  []ShoppingGroup `json:"groups"`
+}
+
+var shoppingWindow fyne.Window
+
+func shoppingItemText(item ShoppingItem) string {
+	if item.Display == "" {
+		return item.Name
+	}
+	return fmt.Sprintf("%s — %s", item.Name, item.Display)
+}
+
+// generateShoppingList собирает покупки на сервере и открывает список.
+func generateShoppingList(payload map[string]interface{}, parent fyne.Window) {
+	if _, err := apiRequest("POST", "/shopping-list/generate", payload); err != nil {
+		dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), parent)
+		return
+	}
+	showShoppingListWindow()
+}
+
+// generateShoppingListFromWeek берёт рецепты из меню текущей недели планировщика.
+func generateShoppingListFromWeek() {
+	replaceCheck := widget.NewCheck("Заменить прежние покупки из рецептов", nil)
+	replaceCheck.SetChecked(true)
+
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Собрать покупки для меню на %s?", plannerWeekLabel.Text)),
+		replaceCheck,
+		widget.NewLabel("Добавленное вручную останется в списке."),
+	)
+
+	dialog.ShowCustomConfirm(fmt.Sprintf("%s Список покупок", iconShopping), "Собрать", "Отмена", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		generateShoppingList(map[string]interface{}{
+			"from":    plannerWeek.Format(planDateLayout),
+			"to":      plannerWeek.AddDate(0, 0, 6).Format(planDateLayout),
+			"replace": replaceCheck.Checked,
+		}, myWindow)
+	}, myWindow)
+}
+
+// showShoppingFromRecipesDialog даёт выбрать рецепты из «Моих рецептов» и множитель для каждого.
+func showShoppingFromRecipesDialog(parent fyne.Window) {
+	if len(recipes) == 0 {
+		dialog.ShowInformation("Список покупок", "Нет рецептов для выбора", parent)
+		return
+	}
+
+	type selection struct {
+		check      *widget.Check
+		multiplier *widget.Entry
+	}
+
+	list := container.NewVBox()
+	selections := make(map[int]selection)
+	for _, recipe := range recipes {
+		check := widget.NewCheck(recipe.Title, nil)
+		multiplier := widget.NewEntry()
+		multiplier.SetText("1")
+		selections[recipe.ID] = selection{check, multiplier}
+		list.Add(container.NewBorder(nil, nil, nil,
+			container.NewHBox(widget.NewLabel("×"), container.NewGridWrap(fyne.NewSize(60, 36), multiplier)),
+			check,
+		))
+	}
+
+	replaceCheck := widget.NewCheck("Заменить прежние покупки из рецептов", nil)
+
+	scroll := container.NewVScroll(list)
+	scroll.SetMinSize(fyne.NewSize(420, 320))
+
+	content := container.NewBorder(nil, replaceCheck, nil, nil, scroll)
+
+	dialog.ShowCustomConfirm(fmt.Sprintf("%s Из рецептов", iconShopping), "Собрать", "Отмена", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		var selected []map[string]interface{}
+		for recipeID, s := range selections {
+			if !s.check.Checked {
+				continue
+			}
+			multiplier, err := strconv.ParseFloat(strings.ReplaceAll(s.multiplier.Text, ",", "."), 64)
+			if err != nil || multiplier <= 0 {
+				dialog.ShowError(fmt.Errorf("%s Множитель для «%s» должен быть положительным числом", iconError, s.check.Text), parent)
+				return
+			}
+			selected = append(selected, map[string]interface{}{"recipe_id": recipeID, "multiplier": multiplier})
+		}
+
+		if len(selected) == 0 {
+			return
+		}
+
+		generateShoppingList(map[string]interface{}{
+			"recipes": selected,
+			"replace": replaceCheck.Checked,
+		}, parent)
+	}, parent)
+}
+
+func showShoppingListWindow() {
+	if shoppingWindow != nil {
+		shoppingWindow.RequestFocus()
+		return
+	}
+
+	shoppingWindow = myApp.NewWindow(fmt.Sprintf("%s Список покупок", iconShopping))
+	shoppingWindow.Resize(fyne.NewSize(520, 640))
+	window := shoppingWindow
+
+	content := container.NewVBox()
+	summaryLabel := widget.NewLabel("")
+
+	var render func(body []byte)
+	reload := func() {
+		body, err := apiRequest("GET", "/shopping-list", nil)
+		if err != nil {
+			summaryLabel.SetText(fmt.Sprintf("%s Ошибка синхронизации: %v", iconError, err))
+			return
+		}
+		render(body)
+	}
+
+	// mutate выполняет изменение и перерисовывает список из ответа сервера
+	mutate := func(method, path string, payload interface{}) {
+		body, err := apiRequest(method, path, payload)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), window)
+			return
+		}
+		render(body)
+	}
+
+	render = func(body []byte) {
+		var listResp ShoppingListResponse
+		json.Unmarshal(body, &listResp)
+
+		summaryLabel.SetText(fmt.Sprintf("Куплено %d из %d", listResp.Checked, listResp.Count))
+		content.Objects = nil
+
+		if listResp.Count == 0 {
+			content.Add(widget.NewLabel("Список пуст. Соберите его из рецептов или меню недели."))
+		}
+
+		for _, group := range listResp.Groups {
+			content.Add(widget.NewLabelWithStyle(group.Category, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+
+			for _, item := range group.Items {
+				item := item
+
+				check := widget.NewCheck(shoppingItemText(item), nil)
+				check.SetChecked(item.Checked)
+				check.OnChanged = func(checked bool) {
+					_, err := apiRequest("POST", "/shopping-list/check", map[string]interface{}{
+						"id":      item.ID,
+						"checked": checked,
+					})
+					if err != nil {
+						dialog.ShowError(fmt.Errorf("%s Ошибка: %v", iconError, err), window)
+					}
+					reload()
+				}
+
+				row := container.NewVBox(container.NewBorder(nil, nil, nil,
+					container.NewHBox(
+						widget.NewButton(iconEdit, func() {
+							showEditShoppingItemDialog(item, window, mutate)
+						}),
+						widget.NewButton(iconDelete, func() {
+							mutate("DELETE", fmt.Sprintf("/shopping-list/delete?id=%d", item.ID), nil)
+						}),
+					),
+					check,
+				))
+				if item.Sources != "" {
+					sources := widget.NewLabelWithStyle("    "+item.Sources, fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+					sources.Wrapping = fyne.TextWrapWord
+					row.Add(sources)
+				}
+				content.Add(row)
+			}
+			content.Add(widget.NewSeparator())
+		}
+
+		content.Refresh()
+	}
+
+	itemEntry := widget.NewEntry()
+	itemEntry.SetPlaceHolder("Например: 2 кг картошки")
+	addItem := func() {
+		text := strings.TrimSpace(itemEntry.Text)
+		if text == "" {
+			return
+		}
+		mutate("POST", "/shopping-list/add", map[string]interface{}{"text": text})
+		itemEntry.SetText("")
+	}
+	itemEntry.OnSubmitted = func(string) { addItem() }
+
+	toolbar := container.NewHBox(
+		widget.NewButton(fmt.Sprintf("%s Из рецептов", iconRecipe), func() {
+			showShoppingFromRecipesDialog(window)
+		}),
+		widget.NewButton(fmt.Sprintf("%s Из меню недели", iconCalendar), func() {
+			generateShoppingListFromWeek()
+		}),
+		layout.NewSpacer(),
+		widget.NewButton("Убрать купленное", func() {
+			mutate("DELETE", "/shopping-list/clear?checked=true", nil)
+		}),
+		widget.NewButton(fmt.Sprintf("%s Всё", iconDelete), func() {
+			dialog.ShowConfirm("Очистка списка", "Удалить все покупки?", func(confirmed bool) {
+				if confirmed {
+					mutate("DELETE", "/shopping-list/clear", nil)
+				}
+			}, window)
+		}),
+	)
+
+	top := container.NewVBox(
+		toolbar,
+		container.NewBorder(nil, nil, nil, widget.NewButton(iconAdd, addItem), itemEntry),
+		summaryLabel,
+		widget.NewSeparator(),
+	)
+
+	reload()
+
+	// Список могут отмечать с другого устройства, поэтому пока окно открыто, он периодически перечитывается
+	ticker := time.NewTicker(shoppingSyncInterval)
+	go func() {
+		for range ticker.C {
+			reload()
+		}
+	}()
+
+	window.SetOnClosed(func() {
+		ticker.Stop()
+		shoppingWindow = nil
+	})
+	window.SetContent(container.NewBorder(top, nil, nil, nil, container.NewScroll(content)))
+	window.Show()
+}
+
+func showEditShoppingItemDialog(item ShoppingItem, parent fyne.Window, mutate func(method, path string, payload interface{})) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(item.Name)
+
+	quantityEntry := widget.NewEntry()
+	if item.Quantity > 0 {
+		quantityEntry.SetText(strconv.FormatFloat(item.Quantity, 'f', -1, 64))
+	}
+
+	unitSelect := widget.NewSelect([]string{"г", "мл", "шт"}, nil)
+	unitSelect.SetSelected(item.Unit)
+
+	categorySelect := widget.NewSelect(shoppingCategories, nil)
+	categorySelect.SetSelected(item.Category)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Название", nameEntry),
+		widget.NewFormItem("Количество", quantityEntry),
+		widget.NewFormItem("Единица", unitSelect),
+		widget.NewFormItem("Отдел", categorySelect),
+	}
+
+	dialog.ShowForm(fmt.Sprintf("%s Покупка", iconEdit), "Сохранить", "Отмена", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		quantity := 0.0
+		if text := strings.TrimSpace(quantityEntry.Text); text != "" {
+			value, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", "."), 64)
+			if err != nil || value < 0 {
+				dialog.ShowError(fmt.Errorf("%s Количество должно быть числом", iconError), parent)
+				return
+			}
+			quantity = value
+		}
+
+		mutate("PUT", "/shopping-list/update", map[string]interface{}{
+			"id":       item.ID,
+			"name":     nameEntry.Text,
+			"quantity": quantity,
+			"unit":     unitSelect.Selected,
+			"category": categorySelect.Selected,
+		})
+	}, parent)
+}
+
+// shoppingCategories повторяет отделы магазина из backend/ingredients.
+var shoppingCategories = []string{
+	"Овощи и фрукты",
+	"Мясо и рыба",
+	"Молочные продукты и яйца",
+	"Хлеб и выпечка",
+	"Бакалея",
+	"Специи и соусы",
+	"Напитки",
+	"Замороженные продукты",
+	"Прочее",
+}
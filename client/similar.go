@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const iconSimilar = "🔗"
+
+type SimilarRecipe struct {
+	Recipe
+	Similarity float64 `json:"similarity"`
+}
+
+type SimilarRecipesResponse struct {
+	Status  string          `json:"status"`
+	Count   int             `json:"count"`
+	Recipes []SimilarRecipe `json:"recipes"`
+}
+
+// createSimilarSection показывает ленту похожих рецептов; без похожих раздел скрыт.
+func createSimilarSection(recipe Recipe) fyne.CanvasObject {
+	section := container.NewVBox()
+
+	body, err := apiRequest("GET", fmt.Sprintf("/similar-recipes?recipe_id=%d", recipe.ID), nil)
+	if err != nil {
+		return section
+	}
+
+	var similarResp SimilarRecipesResponse
+	json.Unmarshal(body, &similarResp)
+	if len(similarResp.Recipes) == 0 {
+		return section
+	}
+
+	strip := container.NewHBox()
+	for _, similar := range similarResp.Recipes {
+		strip.Add(createSimilarCard(similar))
+	}
+
+	scroll := container.NewHScroll(strip)
+	scroll.SetMinSize(fyne.NewSize(0, 190))
+
+	section.Add(widget.NewLabelWithStyle(fmt.Sprintf("%s Похожие рецепты", iconSimilar),
+		fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	section.Add(widget.NewSeparator())
+	section.Add(scroll)
+	return section
+}
+
+func createSimilarCard(similar SimilarRecipe) fyne.CanvasObject {
+	var imageResource fyne.Resource
+	if len(similar.ImageBase64) > 100 {
+		if imgData, err := base64.StdEncoding.DecodeString(similar.ImageBase64); err == nil {
+			imageResource = fyne.NewStaticResource("similar_"+strconv.Itoa(similar.ID), imgData)
+		}
+	}
+	if imageResource == nil {
+		imageResource = theme.FileIcon()
+	}
+
+	cardImage := canvas.NewImageFromResource(imageResource)
+	cardImage.FillMode = canvas.ImageFillContain
+	cardImage.SetMinSize(fyne.NewSize(150, 90))
+
+	title := widget.NewLabelWithStyle(similar.Title, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	title.Truncation = fyne.TextTruncateEllipsis
+
+	recipe := similar.Recipe
+	cardButton := widget.NewButton("", func() {
+		showRecipeDetails(recipe)
+	})
+
+	return container.NewGridWrap(fyne.NewSize(170, 180), container.NewStack(
+		cardButton,
+		container.NewVBox(
+			cardImage,
+			title,
+			widget.NewLabelWithStyle(fmt.Sprintf("Сходство %.0f%%", similar.Similarity*100),
+				fyne.TextAlignCenter, fyne.TextStyle{Italic: true}),
+		),
+	))
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// alarmSounds — системные звуки, которые пробуются по очереди.
+var alarmSounds = [][]string{
+	{"canberra-gtk-play", "-i", "alarm-clock-elapsed"},
+	{"paplay", "/usr/share/sounds/freedesktop/stereo/alarm-clock-elapsed.oga"},
+	{"paplay", "/usr/share/sounds/freedesktop/stereo/complete.oga"},
+	{"aplay", "-q", "/usr/share/sounds/alsa/Front_Center.wav"},
+}
+
+// playAlarm проигрывает звук сигнала таймера; если плееров нет, подаёт сигнал терминала.
+func playAlarm() {
+	go func() {
+		for _, command := range alarmSounds {
+			path, err := exec.LookPath(command[0])
+			if err != nil {
+				continue
+			}
+			if exec.Command(path, command[1:]...).Run() == nil {
+				return
+			}
+		}
+		fmt.Print("\a")
+	}()
+}
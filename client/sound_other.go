@@ -0,0 +1,12 @@
+//go:build !linux && !windows
+
+package main
+
+import (
+	"fmt"
+)
+
+// playAlarm на остальных платформах подаёт сигнал терминала.
+func playAlarm() {
+	fmt.Print("\a")
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package main
+
+import (
+	"syscall"
+)
+
+const mbIconExclamation = 0x00000030
+
+var messageBeep = syscall.NewLazyDLL("user32.dll").NewProc("MessageBeep")
+
+// playAlarm проигрывает системный звук предупреждения.
+func playAlarm() {
+	if messageBeep.Find() != nil {
+		return
+	}
+	go messageBeep.Call(uintptr(mbIconExclamation))
+}
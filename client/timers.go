@@ -0,0 +1,367 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	iconTimer          = "⏲"
+	iconAlarm          = "⏰"
+	kitchenTimersKey   = "kitchen_timers"
+	maxKitchenTimers   = 12
+	maxTimerNameLength = 60
+)
+
+// KitchenTimer — именованный таймер обратного отсчёта. У идущего таймера задан EndsAt,
+// у остановленного — Left. Таймеры хранятся в настройках приложения и переживают перезапуск.
+type KitchenTimer struct {
+	ID       int64         `json:"id"`
+	Name     string        `json:"name"`
+	RecipeID int           `json:"recipe_id,omitempty"`
+	Duration time.Duration `json:"duration"`
+	EndsAt   time.Time     `json:"ends_at"`
+	Paused   bool          `json:"paused"`
+	Left     time.Duration `json:"left"`
+	Fired    bool          `json:"fired"`
+}
+
+func (t *KitchenTimer) remaining(now time.Time) time.Duration {
+	if t.Paused {
+		return t.Left
+	}
+	if left := t.EndsAt.Sub(now); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// timerStrip — полоса таймеров в окне; их может быть несколько (главное окно и режим готовки).
+type timerStrip struct {
+	box    *fyne.Container
+	labels map[int64]*widget.Label
+}
+
+var (
+	timersMu       sync.Mutex
+	kitchenTimers  []*KitchenTimer
+	timerStrips    []*timerStrip
+	mainTimerStrip *timerStrip
+
+	timerDurationPattern = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)(?:\s*[-–]\s*(\d+(?:[.,]\d+)?))?\s*(час|мин|сек)`)
+)
+
+// StepDuration — время, найденное в тексте шага.
+type StepDuration struct {
+	Label    string
+	Duration time.Duration
+}
+
+// parseStepDurations находит в тексте длительности вида «15 минут», «1,5 часа», «10-15 мин».
+// Для диапазона берётся верхняя граница.
+func parseStepDurations(text string) []StepDuration {
+	var durations []StepDuration
+	for _, match := range timerDurationPattern.FindAllStringSubmatch(text, -1) {
+		value := match[1]
+		if match[2] != "" {
+			value = match[2]
+		}
+		amount, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil || amount <= 0 {
+			continue
+		}
+
+		unit := time.Minute
+		switch strings.ToLower(match[3]) {
+		case "час":
+			unit = time.Hour
+		case "сек":
+			unit = time.Second
+		}
+
+		duration := time.Duration(amount * float64(unit))
+		if duration > 24*time.Hour {
+			continue
+		}
+		durations = append(durations, StepDuration{Label: formatTimerDuration(duration), Duration: duration})
+	}
+	return durations
+}
+
+func formatTimerDuration(duration time.Duration) string {
+	duration = duration.Round(time.Second)
+	hours := int(duration.Hours())
+	minutes := int(duration.Minutes()) % 60
+	seconds := int(duration.Seconds()) % 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+}
+
+func saveKitchenTimersLocked() {
+	data, _ := json.Marshal(kitchenTimers)
+	myApp.Preferences().SetString(kitchenTimersKey, string(data))
+}
+
+// loadKitchenTimers восстанавливает таймеры после запуска и запускает ежесекундный отсчёт.
+// Таймеры, истёкшие пока приложение было закрыто, срабатывают сразу.
+func loadKitchenTimers() {
+	timersMu.Lock()
+	json.Unmarshal([]byte(myApp.Preferences().String(kitchenTimersKey)), &kitchenTimers)
+	timersMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			tickKitchenTimers()
+		}
+	}()
+}
+
+func tickKitchenTimers() {
+	now := time.Now()
+
+	timersMu.Lock()
+	var fired []string
+	for _, timer := range kitchenTimers {
+		if !timer.Fired && !timer.Paused && !now.Before(timer.EndsAt) {
+			timer.Fired = true
+			fired = append(fired, timer.Name)
+		}
+	}
+	if len(fired) > 0 {
+		saveKitchenTimersLocked()
+	}
+
+	for _, strip := range timerStrips {
+		for _, timer := range kitchenTimers {
+			if label, ok := strip.labels[timer.ID]; ok {
+				label.SetText(timerChipText(timer, now))
+			}
+		}
+	}
+	timersMu.Unlock()
+
+	for _, name := range fired {
+		myApp.SendNotification(fyne.NewNotification(fmt.Sprintf("%s Таймер", iconAlarm), name+" — время вышло!"))
+		playAlarm()
+	}
+	if len(fired) > 0 {
+		refreshTimerStrips()
+	}
+}
+
+func timerChipText(timer *KitchenTimer, now time.Time) string {
+	switch {
+	case timer.Fired:
+		return fmt.Sprintf("%s %s: готово!", iconAlarm, timer.Name)
+	case timer.Paused:
+		return fmt.Sprintf("⏸ %s: %s", timer.Name, formatTimerDuration(timer.Left))
+	}
+	return fmt.Sprintf("%s %s: %s", iconTimer, timer.Name, formatTimerDuration(timer.remaining(now)))
+}
+
+func startKitchenTimer(name string, duration time.Duration, recipeID int) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Таймер"
+	}
+	if runes := []rune(name); len(runes) > maxTimerNameLength {
+		name = string(runes[:maxTimerNameLength])
+	}
+	if duration <= 0 || duration > 24*time.Hour {
+		return fmt.Errorf("Время таймера должно быть от 1 секунды до 24 часов")
+	}
+
+	timersMu.Lock()
+	if len(kitchenTimers) >= maxKitchenTimers {
+		timersMu.Unlock()
+		return fmt.Errorf("Одновременно можно запустить не более %d таймеров", maxKitchenTimers)
+	}
+	now := time.Now()
+	kitchenTimers = append(kitchenTimers, &KitchenTimer{
+		ID:       now.UnixNano(),
+		Name:     name,
+		RecipeID: recipeID,
+		Duration: duration,
+		EndsAt:   now.Add(duration),
+	})
+	saveKitchenTimersLocked()
+	timersMu.Unlock()
+
+	refreshTimerStrips()
+	return nil
+}
+
+// updateKitchenTimer изменяет таймер под блокировкой и сохраняет результат.
+func updateKitchenTimer(id int64, change func(timer *KitchenTimer, index int)) {
+	timersMu.Lock()
+	for i, timer := range kitchenTimers {
+		if timer.ID == id {
+			change(timer, i)
+			break
+		}
+	}
+	saveKitchenTimersLocked()
+	timersMu.Unlock()
+
+	refreshTimerStrips()
+}
+
+func removeKitchenTimer(id int64) {
+	updateKitchenTimer(id, func(_ *KitchenTimer, index int) {
+		kitchenTimers = append(kitchenTimers[:index], kitchenTimers[index+1:]...)
+	})
+}
+
+func toggleKitchenTimer(id int64) {
+	updateKitchenTimer(id, func(timer *KitchenTimer, _ int) {
+		if timer.Fired {
+			return
+		}
+		if timer.Paused {
+			timer.EndsAt = time.Now().Add(timer.Left)
+			timer.Paused, timer.Left = false, 0
+		} else {
+			timer.Left = timer.remaining(time.Now())
+			timer.Paused = true
+		}
+	})
+}
+
+// extendKitchenTimer добавляет минуту; сработавший таймер запускается снова на эту минуту.
+func extendKitchenTimer(id int64) {
+	updateKitchenTimer(id, func(timer *KitchenTimer, _ int) {
+		switch {
+		case timer.Paused:
+			timer.Left += time.Minute
+		case timer.Fired:
+			timer.Fired = false
+			timer.EndsAt = time.Now().Add(time.Minute)
+		default:
+			timer.EndsAt = timer.EndsAt.Add(time.Minute)
+		}
+	})
+}
+
+// createTimerStrip создаёт полосу таймеров; вызывающий удаляет её через releaseTimerStrip при закрытии окна.
+func createTimerStrip(parent fyne.Window) (fyne.CanvasObject, *timerStrip) {
+	strip := &timerStrip{box: container.NewHBox(), labels: map[int64]*widget.Label{}}
+
+	addBtn := widget.NewButton(fmt.Sprintf("%s Таймер", iconTimer), func() {
+		showNewTimerDialog("", 0, 0, parent)
+	})
+	addBtn.Importance = widget.LowImportance
+
+	timersMu.Lock()
+	timerStrips = append(timerStrips, strip)
+	timersMu.Unlock()
+	refreshTimerStrips()
+
+	return container.NewBorder(nil, nil, addBtn, nil, container.NewHScroll(strip.box)), strip
+}
+
+func releaseTimerStrip(strip *timerStrip) {
+	timersMu.Lock()
+	defer timersMu.Unlock()
+
+	for i, s := range timerStrips {
+		if s == strip {
+			timerStrips = append(timerStrips[:i], timerStrips[i+1:]...)
+			return
+		}
+	}
+}
+
+// refreshTimerStrips перестраивает все полосы таймеров после добавления, удаления или срабатывания.
+func refreshTimerStrips() {
+	timersMu.Lock()
+	defer timersMu.Unlock()
+
+	now := time.Now()
+	for _, strip := range timerStrips {
+		strip.box.Objects = nil
+		strip.labels = map[int64]*widget.Label{}
+
+		for _, timer := range kitchenTimers {
+			id := timer.ID
+
+			label := widget.NewLabelWithStyle(timerChipText(timer, now), fyne.TextAlignLeading,
+				fyne.TextStyle{Bold: timer.Fired, Monospace: !timer.Fired})
+			strip.labels[id] = label
+
+			pauseText := "⏸"
+			if timer.Paused {
+				pauseText = "▶"
+			}
+			pauseBtn := widget.NewButton(pauseText, func() { toggleKitchenTimer(id) })
+			if timer.Fired {
+				pauseBtn.Disable()
+			}
+			extendBtn := widget.NewButton("+1", func() { extendKitchenTimer(id) })
+			removeBtn := widget.NewButton(iconClose, func() { removeKitchenTimer(id) })
+			for _, button := range []*widget.Button{pauseBtn, extendBtn, removeBtn} {
+				button.Importance = widget.LowImportance
+			}
+
+			chip := container.NewHBox(label, pauseBtn, extendBtn, removeBtn)
+			if timer.Fired {
+				strip.box.Add(widget.NewCard("", "", chip))
+			} else {
+				strip.box.Add(chip)
+			}
+			strip.box.Add(widget.NewSeparator())
+		}
+		if len(kitchenTimers) == 0 {
+			strip.box.Add(widget.NewLabel("Таймеров нет"))
+		}
+		strip.box.Refresh()
+	}
+}
+
+// showNewTimerDialog запрашивает название и время нового таймера; значения подставляются из рецепта или шага.
+func showNewTimerDialog(name string, duration time.Duration, recipeID int, parent fyne.Window) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Например: Паста")
+	nameEntry.SetText(name)
+
+	minutesEntry := widget.NewEntry()
+	minutesEntry.SetPlaceHolder("Минуты")
+	if duration > 0 {
+		minutesEntry.SetText(strconv.FormatFloat(duration.Minutes(), 'f', -1, 64))
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Название:", nameEntry),
+		widget.NewFormItem("Минут:", minutesEntry),
+	}
+
+	dialog.ShowForm(fmt.Sprintf("%s Новый таймер", iconTimer), "Запустить", "Отмена", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		minutes, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(minutesEntry.Text), ",", ".", 1), 64)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("Время должно быть числом минут"), parent)
+			return
+		}
+
+		if err := startKitchenTimer(nameEntry.Text, time.Duration(minutes*float64(time.Minute)), recipeID); err != nil {
+			dialog.ShowError(err, parent)
+		}
+	}, parent)
+}